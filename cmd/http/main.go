@@ -2,18 +2,32 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"time"
 
 	_ "github.com/cidmiranda/go-ws/docs"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/breached"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/hasher"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/jwt"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/oidc"
 	paseto "github.com/cidmiranda/go-ws/internal/adapter/auth/passeto"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/policy"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/secret"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/totp"
 	"github.com/cidmiranda/go-ws/internal/adapter/config"
 	"github.com/cidmiranda/go-ws/internal/adapter/handler/http"
 	"github.com/cidmiranda/go-ws/internal/adapter/logger"
+	"github.com/cidmiranda/go-ws/internal/adapter/mailer/outbox"
+	"github.com/cidmiranda/go-ws/internal/adapter/mailer/smtp"
 	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
 	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres/repository"
 	"github.com/cidmiranda/go-ws/internal/adapter/storage/redis"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
 	"github.com/cidmiranda/go-ws/internal/core/service"
 )
 
@@ -22,13 +36,21 @@ import (
 // @description					This is a simple RESTful Service API written in Go using Gin web framework, PostgreSQL database, and Redis cache.
 
 // @host						localhost:8080
-// @BasePath					/v1
+// @BasePath					/
 // @schemes						http https
 //
 // @securityDefinitions.apikey	BearerAuth
 // @in							header
 // @name						Authorization
 // @description					Type "Bearer" followed by a space and the access token.
+//
+// @securityDefinitions.apikey	ApiKeyAuth
+// @in							header
+// @name						Authorization
+// @description					Type "ApiKey" followed by a space and the api key, or send the api key alone in the X-API-Key header.
+//
+// @securityDefinitions.basic	BasicAuth
+// @description					For internal tools that only support HTTP basic authentication, the password is an api key and the username is ignored.
 func main() {
 	// Load environment variables
 	config, err := config.New()
@@ -73,28 +95,212 @@ func main() {
 	slog.Info("Successfully connected to the cache server")
 
 	// Init token service
-	token, err := paseto.New(config.Token)
+	token, err := newTokenService(config.Token)
 	if err != nil {
 		slog.Error("Error initializing token service", "error", err)
 		os.Exit(1)
 	}
 
+	slog.Info("Successfully initialized the token service", "type", config.Token.Type)
+
+	refreshDuration, err := time.ParseDuration(config.Token.RefreshDuration)
+	if err != nil {
+		slog.Error("Error parsing refresh token duration", "error", err)
+		os.Exit(1)
+	}
+
+	lockoutPolicy, err := newLockoutPolicy(config.Lockout)
+	if err != nil {
+		slog.Error("Error parsing login lockout configuration", "error", err)
+		os.Exit(1)
+	}
+
+	secretCipher, err := secret.New(config.TwoFactor)
+	if err != nil {
+		slog.Error("Error initializing two-factor secret encryption", "error", err)
+		os.Exit(1)
+	}
+
+	// Init mailer
+	mailer, err := newMailer(config.Mail)
+	if err != nil {
+		slog.Error("Error initializing mailer", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Successfully initialized the mailer", "driver", config.Mail.Driver)
+
+	verificationKey, err := hex.DecodeString(config.Mail.VerificationKey)
+	if err != nil || len(verificationKey) < 32 {
+		slog.Error("Error parsing email verification key", "error", "key must be at least 32 hex encoded bytes")
+		os.Exit(1)
+	}
+
+	verificationDuration, err := time.ParseDuration(config.Mail.VerificationTTL)
+	if err != nil {
+		slog.Error("Error parsing email verification duration", "error", err)
+		os.Exit(1)
+	}
+
+	requireVerifiedEmail, err := strconv.ParseBool(config.Mail.RequireVerified)
+	if err != nil {
+		slog.Error("Error parsing email verification requirement", "error", err)
+		os.Exit(1)
+	}
+
+	resetDuration, err := time.ParseDuration(config.Reset.Duration)
+	if err != nil {
+		slog.Error("Error parsing password reset duration", "error", err)
+		os.Exit(1)
+	}
+
+	// Init password policy
+	passwordPolicy, err := newPasswordPolicy(config.Password)
+	if err != nil {
+		slog.Error("Error parsing password policy configuration", "error", err)
+		os.Exit(1)
+	}
+
+	breachedPasswords, err := breached.New(config.Password)
+	if err != nil {
+		slog.Error("Error loading breached passwords", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Successfully loaded the breached passwords", "file", config.Password.BreachedFile, "hashes", breachedPasswords.Size())
+
+	passwordPolicyService := service.NewPasswordPolicyService(breachedPasswords, passwordPolicy)
+
+	passwordHasher, err := hasher.New(config.Password)
+	if err != nil {
+		slog.Error("Error initializing password hasher", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Successfully initialized the password hasher", "algorithm", config.Password.HashAlgorithm)
+
+	// Init single sign-on, disabled when no issuer is configured
+	var identityProvider port.IdentityProvider
+	if config.OIDC.Issuer != "" {
+		oidcProvider, err := oidc.New(ctx, config.OIDC)
+		if err != nil {
+			slog.Error("Error discovering the OpenID Connect provider", "error", err)
+			os.Exit(1)
+		}
+
+		identityProvider = oidcProvider
+
+		slog.Info("Successfully discovered the OpenID Connect provider", "provider", config.OIDC.Provider, "issuer", config.OIDC.Issuer)
+	}
+
+	// Init authorization policy
+	policyRepo := policy.New(config.Authz)
+	authorizer := service.NewAuthorizerService(policyRepo)
+	err = authorizer.Reload(ctx)
+	if err != nil {
+		slog.Error("Error loading authorization policy", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Successfully loaded the authorization policy", "file", config.Authz.PolicyFile)
+
 	// Dependency injection
+	// Audit
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := http.NewAuditHandler(auditService)
+
 	// User
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo, cache)
-	userHandler := http.NewUserHandler(userService)
+	verificationService := service.NewVerificationService(userRepo, cache, mailer, verificationKey, config.Mail.VerificationURL, verificationDuration)
+	userService := service.NewUserService(userRepo, cache, authorizer, verificationService, passwordPolicyService, passwordHasher, auditService)
+	userHandler := http.NewUserHandler(userService, verificationService)
+
+	// Two-factor
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, userRepo, cache, totp.New(config.TwoFactor), secretCipher)
+	twoFactorHandler := http.NewTwoFactorHandler(twoFactorService)
+
+	// Browser sessions
+	sessionCookies, err := http.NewSessionCookies(config.HTTP)
+	if err != nil {
+		slog.Error("Error parsing session cookie configuration", "error", err)
+		os.Exit(1)
+	}
 
 	// Auth
-	authService := service.NewAuthService(userRepo, token)
-	authHandler := http.NewAuthHandler(authService)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	lockoutService := service.NewLockoutService(cache, lockoutPolicy)
+	authService := service.NewAuthService(userRepo, token, refreshTokenRepo, sessionRepo, cache, lockoutService, twoFactorService, passwordHasher, auditService, refreshDuration, requireVerifiedEmail)
+	authHandler := http.NewAuthHandler(authService, sessionCookies)
+
+	// Sessions
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo, cache)
+	sessionHandler := http.NewSessionHandler(sessionService)
+
+	// Impersonation
+	impersonationRepo := repository.NewImpersonationRepository(db)
+	impersonationService := service.NewImpersonationService(impersonationRepo, userRepo, token, cache)
+	impersonationHandler := http.NewImpersonationHandler(impersonationService)
+
+	// Password reset
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	passwordResetService := service.NewPasswordResetService(userRepo, passwordResetRepo, cache, mailer, authService, passwordPolicyService, passwordHasher, config.Reset.URL, resetDuration)
+	passwordResetHandler := http.NewPasswordResetHandler(passwordResetService)
+
+	// API keys
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo)
+	apiKeyHandler := http.NewAPIKeyHandler(apiKeyService)
+
+	// OAuth
+	oauthClientRepo := repository.NewOAuthClientRepository(db)
+	oauthService := service.NewOAuthService(oauthClientRepo, userRepo, token, cache)
+	oauthHandler := http.NewOAuthHandler(oauthService)
+
+	// Single sign-on
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	oidcService := service.NewOIDCService(identityProvider, userIdentityRepo, userRepo, cache, authService, passwordHasher)
+	oidcHandler := http.NewOIDCHandler(oidcService, sessionCookies)
+
+	// Keys
+	keyHandler := http.NewKeyHandler(token)
+
+	// Policy
+	policyHandler := http.NewPolicyHandler(authorizer)
+
+	// Rate limit
+	rateLimitService := service.NewRateLimitService(cache)
+
+	// Authentication schemes, tried in order
+	authenticator := http.NewAuthenticatorChain(
+		http.NewBearerAuthenticator(authService),
+		http.NewAPIKeyAuthenticator(apiKeyService),
+		http.NewBasicAuthenticator(apiKeyService),
+		http.NewCookieAuthenticator(authService, sessionCookies),
+		http.NewCertificateAuthenticator(authService),
+	)
 
 	// Init router
 	router, err := http.NewRouter(
 		config.HTTP,
-		token,
+		authenticator,
+		authorizer,
+		rateLimitService,
+		impersonationService,
 		*userHandler,
 		*authHandler,
+		*keyHandler,
+		*policyHandler,
+		*twoFactorHandler,
+		*passwordResetHandler,
+		*apiKeyHandler,
+		*oauthHandler,
+		*oidcHandler,
+		*sessionHandler,
+		*impersonationHandler,
+		*auditHandler,
 	)
 	if err != nil {
 		slog.Error("Error initializing router", "error", err)
@@ -110,3 +316,100 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// tokenService is a token adapter that also exposes its public keys
+type tokenService interface {
+	port.TokenService
+	port.PublicKeyProvider
+}
+
+// newTokenService creates the token adapter selected by the TOKEN_TYPE configuration
+func newTokenService(config *config.Token) (tokenService, error) {
+	if config.Type == "jwt" {
+		return jwt.New(config)
+	}
+
+	return paseto.New(config)
+}
+
+// newMailer creates the mailer adapter selected by the MAIL_DRIVER configuration
+func newMailer(config *config.Mail) (port.Mailer, error) {
+	if config.Driver == "smtp" {
+		return smtp.New(config)
+	}
+
+	return outbox.New(config)
+}
+
+// newLockoutPolicy parses the login lockout configuration
+func newLockoutPolicy(config *config.Lockout) (domain.LockoutPolicy, error) {
+	var policy domain.LockoutPolicy
+	var err error
+
+	policy.MaxAttempts, err = strconv.ParseInt(config.MaxAttempts, 10, 64)
+	if err != nil {
+		return policy, fmt.Errorf("invalid max attempts: %w", err)
+	}
+
+	policy.MaxAttemptsPerIP, err = strconv.ParseInt(config.MaxAttemptsPerIP, 10, 64)
+	if err != nil {
+		return policy, fmt.Errorf("invalid max attempts per ip: %w", err)
+	}
+
+	policy.AttemptWindow, err = time.ParseDuration(config.AttemptWindow)
+	if err != nil {
+		return policy, fmt.Errorf("invalid attempt window: %w", err)
+	}
+
+	policy.LockoutDuration, err = time.ParseDuration(config.Duration)
+	if err != nil || policy.LockoutDuration <= 0 {
+		return policy, fmt.Errorf("invalid lockout duration %q", config.Duration)
+	}
+
+	policy.MaxLockoutDuration, err = time.ParseDuration(config.MaxDuration)
+	if err != nil || policy.MaxLockoutDuration < policy.LockoutDuration {
+		return policy, fmt.Errorf("invalid max lockout duration %q", config.MaxDuration)
+	}
+
+	return policy, nil
+}
+
+// newPasswordPolicy parses the password policy configuration,
+// breached passwords are only denied if a breached password file is configured
+func newPasswordPolicy(config *config.Password) (domain.PasswordPolicy, error) {
+	var policy domain.PasswordPolicy
+	var err error
+
+	policy.MinLength, err = strconv.Atoi(config.MinLength)
+	if err != nil || policy.MinLength < 1 {
+		return policy, fmt.Errorf("invalid min length %q", config.MinLength)
+	}
+
+	policy.MaxLength, err = strconv.Atoi(config.MaxLength)
+	if err != nil || policy.MaxLength < policy.MinLength {
+		return policy, fmt.Errorf("invalid max length %q", config.MaxLength)
+	}
+
+	rules := []struct {
+		name  string
+		value string
+		rule  *bool
+	}{
+		{"require upper", config.RequireUpper, &policy.RequireUpper},
+		{"require lower", config.RequireLower, &policy.RequireLower},
+		{"require digit", config.RequireDigit, &policy.RequireDigit},
+		{"require symbol", config.RequireSymbol, &policy.RequireSymbol},
+		{"deny personal info", config.DenyPersonalInfo, &policy.DenyPersonalInfo},
+	}
+
+	for _, rule := range rules {
+		*rule.rule, err = strconv.ParseBool(rule.value)
+		if err != nil {
+			return policy, fmt.Errorf("invalid %s: %w", rule.name, err)
+		}
+	}
+
+	policy.DenyBreached = config.BreachedFile != ""
+
+	return policy, nil
+}
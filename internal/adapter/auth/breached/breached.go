@@ -0,0 +1,94 @@
+package breached
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+)
+
+const (
+	// hashLength is the length of a hex encoded SHA-1 hash
+	hashLength = 40
+	// prefixLength is the length of the hash prefix the corpus is indexed by
+	prefixLength = 5
+)
+
+/**
+ * Corpus implements port.BreachedPasswordRepository interface
+ * and looks up breached passwords in a local file of SHA-1 hashes,
+ * one hex encoded hash per line optionally followed by ":" and the breach count,
+ * as in the downloadable Pwned Passwords lists.
+ * The hashes are indexed by their 5 character prefix like the Pwned Passwords range API
+ */
+type Corpus struct {
+	ranges map[string]map[string]struct{}
+}
+
+// New loads the breached password file, no password is breached if no file is configured
+func New(config *config.Password) (*Corpus, error) {
+	corpus := &Corpus{
+		ranges: make(map[string]map[string]struct{}),
+	}
+
+	if config.BreachedFile == "" {
+		return corpus, nil
+	}
+
+	file, err := os.Open(config.BreachedFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		if len(hash) != hashLength {
+			return nil, fmt.Errorf("%s:%d: invalid SHA-1 hash %q", config.BreachedFile, number, hash)
+		}
+
+		hash = strings.ToUpper(hash)
+		prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+		if corpus.ranges[prefix] == nil {
+			corpus.ranges[prefix] = make(map[string]struct{})
+		}
+		corpus.ranges[prefix][suffix] = struct{}{}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return corpus, nil
+}
+
+// ContainsHash reports whether the uppercase hex encoded SHA-1 hash is in the corpus
+func (c *Corpus) ContainsHash(ctx context.Context, hash string) (bool, error) {
+	if len(hash) != hashLength {
+		return false, nil
+	}
+
+	_, ok := c.ranges[hash[:prefixLength]][hash[prefixLength:]]
+	return ok, nil
+}
+
+// Size returns the number of hashes in the corpus
+func (c *Corpus) Size() int {
+	size := 0
+	for _, suffixes := range c.ranges {
+		size += len(suffixes)
+	}
+
+	return size
+}
@@ -0,0 +1,141 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// argon2SaltLength is the length in bytes of the random salt
+	argon2SaltLength = 16
+	// argon2KeyLength is the length in bytes of the derived key
+	argon2KeyLength = 32
+)
+
+// errInvalidArgon2Hash is an error for when a hash is not in the argon2id PHC string format
+var errInvalidArgon2Hash = errors.New("invalid argon2id hash")
+
+/**
+ * Argon2id hashes passwords with argon2id,
+ * the hashes are PHC strings that record the version, memory, iterations and parallelism:
+ * $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+ */
+type Argon2id struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// argon2Hash represents a parsed argon2id PHC string
+type argon2Hash struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// NewArgon2id creates a new argon2id algorithm with the memory in KiB, the iterations and the parallelism
+func NewArgon2id(memory, iterations uint32, parallelism uint8) *Argon2id {
+	return &Argon2id{
+		memory,
+		iterations,
+		parallelism,
+	}
+}
+
+// Identifies reports whether the hash was created by argon2id
+func (a *Argon2id) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// Hash hashes the password with a random salt
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.iterations, a.memory, a.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		a.memory,
+		a.iterations,
+		a.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Compare returns an error if the password does not match the hash,
+// the parameters recorded in the hash are used rather than the configured ones
+func (a *Argon2id) Compare(password, hash string) error {
+	parsed, err := parseArgon2Hash(hash)
+	if err != nil {
+		return err
+	}
+
+	key := argon2.IDKey([]byte(password), parsed.salt, parsed.iterations, parsed.memory, parsed.parallelism, uint32(len(parsed.key)))
+	if subtle.ConstantTimeCompare(key, parsed.key) != 1 {
+		return errMismatchedPassword
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether the hash was created with other parameters
+func (a *Argon2id) NeedsRehash(hash string) bool {
+	parsed, err := parseArgon2Hash(hash)
+	if err != nil {
+		return true
+	}
+
+	return parsed.version != argon2.Version ||
+		parsed.memory != a.memory ||
+		parsed.iterations != a.iterations ||
+		parsed.parallelism != a.parallelism ||
+		len(parsed.salt) != argon2SaltLength ||
+		len(parsed.key) != argon2KeyLength
+}
+
+// parseArgon2Hash parses an argon2id PHC string
+func parseArgon2Hash(hash string) (*argon2Hash, error) {
+	fields := strings.Split(hash, "$")
+	if len(fields) != 6 || fields[0] != "" || fields[1] != "argon2id" {
+		return nil, errInvalidArgon2Hash
+	}
+
+	var parsed argon2Hash
+
+	_, err := fmt.Sscanf(fields[2], "v=%d", &parsed.version)
+	if err != nil {
+		return nil, errInvalidArgon2Hash
+	}
+
+	_, err = fmt.Sscanf(fields[3], "m=%d,t=%d,p=%d", &parsed.memory, &parsed.iterations, &parsed.parallelism)
+	if err != nil || parsed.iterations == 0 || parsed.parallelism == 0 {
+		return nil, errInvalidArgon2Hash
+	}
+
+	parsed.salt, err = base64.RawStdEncoding.DecodeString(fields[4])
+	if err != nil {
+		return nil, errInvalidArgon2Hash
+	}
+
+	parsed.key, err = base64.RawStdEncoding.DecodeString(fields[5])
+	if err != nil || len(parsed.key) == 0 {
+		return nil, errInvalidArgon2Hash
+	}
+
+	return &parsed, nil
+}
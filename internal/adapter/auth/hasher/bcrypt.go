@@ -0,0 +1,50 @@
+package hasher
+
+import (
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+/**
+ * Bcrypt hashes passwords with bcrypt,
+ * the hashes are in the modular crypt format that records the cost
+ */
+type Bcrypt struct {
+	cost int
+}
+
+// NewBcrypt creates a new bcrypt algorithm with the given cost
+func NewBcrypt(cost int) *Bcrypt {
+	return &Bcrypt{
+		cost,
+	}
+}
+
+// Identifies reports whether the hash was created by bcrypt
+func (b *Bcrypt) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") ||
+		strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
+
+// Hash hashes the password with a random salt
+func (b *Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Compare returns an error if the password does not match the hash
+func (b *Bcrypt) Compare(password, hash string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
+
+// NeedsRehash reports whether the hash was created with another cost
+func (b *Bcrypt) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != b.cost
+}
@@ -0,0 +1,102 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// errMismatchedPassword is an error for when a password does not match the hash
+	errMismatchedPassword = errors.New("password does not match the hash")
+	// errUnknownAlgorithm is an error for when a hash was created by an unsupported algorithm
+	errUnknownAlgorithm = errors.New("unknown password hash algorithm")
+)
+
+// algorithm is a password hashing algorithm
+type algorithm interface {
+	Identifies(hash string) bool
+	Hash(password string) (string, error)
+	Compare(password, hash string) error
+	NeedsRehash(hash string) bool
+}
+
+/**
+ * Hasher implements port.PasswordHasher interface
+ * and hashes new passwords with the configured algorithm.
+ * Hashes of every supported algorithm can be compared,
+ * so that existing hashes keep working until they are rehashed
+ */
+type Hasher struct {
+	active     algorithm
+	algorithms []algorithm
+}
+
+// New creates a new hasher for the algorithm and parameters selected by the password configuration
+func New(config *config.Password) (*Hasher, error) {
+	cost, err := strconv.Atoi(config.BcryptCost)
+	if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("invalid bcrypt cost %q", config.BcryptCost)
+	}
+
+	memory, err := strconv.ParseUint(config.Argon2Memory, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid argon2 memory %q", config.Argon2Memory)
+	}
+
+	iterations, err := strconv.ParseUint(config.Argon2Iterations, 10, 32)
+	if err != nil || iterations == 0 {
+		return nil, fmt.Errorf("invalid argon2 iterations %q", config.Argon2Iterations)
+	}
+
+	parallelism, err := strconv.ParseUint(config.Argon2Parallelism, 10, 8)
+	if err != nil || parallelism == 0 {
+		return nil, fmt.Errorf("invalid argon2 parallelism %q", config.Argon2Parallelism)
+	}
+
+	bcryptAlgorithm := NewBcrypt(cost)
+	argon2Algorithm := NewArgon2id(uint32(memory), uint32(iterations), uint8(parallelism))
+
+	hasher := &Hasher{
+		algorithms: []algorithm{argon2Algorithm, bcryptAlgorithm},
+	}
+
+	switch config.HashAlgorithm {
+	case "argon2id":
+		hasher.active = argon2Algorithm
+	case "bcrypt":
+		hasher.active = bcryptAlgorithm
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %q", config.HashAlgorithm)
+	}
+
+	return hasher, nil
+}
+
+// Hash hashes the password with the configured algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.active.Hash(password)
+}
+
+// Compare returns an error if the password does not match the hash of any supported algorithm
+func (h *Hasher) Compare(password, hash string) error {
+	for _, algorithm := range h.algorithms {
+		if algorithm.Identifies(hash) {
+			return algorithm.Compare(password, hash)
+		}
+	}
+
+	return errUnknownAlgorithm
+}
+
+// NeedsRehash reports whether the hash was created by another algorithm or with other parameters
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if !h.active.Identifies(hash) {
+		return true
+	}
+
+	return h.active.NeedsRehash(hash)
+}
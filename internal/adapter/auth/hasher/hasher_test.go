@@ -0,0 +1,291 @@
+package hasher_test
+
+import (
+	"testing"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/hasher"
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const password = "correct horse battery staple"
+
+// newPasswordConfig creates a password configuration with cheap parameters for the given algorithm
+func newPasswordConfig(algorithm string) *config.Password {
+	return &config.Password{
+		HashAlgorithm:     algorithm,
+		BcryptCost:        "4",
+		Argon2Memory:      "64",
+		Argon2Iterations:  "1",
+		Argon2Parallelism: "1",
+	}
+}
+
+// newHash hashes the password with a hasher created from the configuration
+func newHash(t *testing.T, passwordConfig *config.Password) string {
+	h, err := hasher.New(passwordConfig)
+	require.NoError(t, err)
+
+	hash, err := h.Hash(password)
+	require.NoError(t, err)
+
+	return hash
+}
+
+func TestHasher_Compare(t *testing.T) {
+	argon2Hash := newHash(t, newPasswordConfig("argon2id"))
+	bcryptHash := newHash(t, newPasswordConfig("bcrypt"))
+
+	otherArgon2Config := newPasswordConfig("argon2id")
+	otherArgon2Config.Argon2Memory = "128"
+	otherArgon2Config.Argon2Iterations = "2"
+	otherArgon2Hash := newHash(t, otherArgon2Config)
+
+	testCases := []struct {
+		desc     string
+		password string
+		hash     string
+		valid    bool
+	}{
+		{
+			desc:     "Success_Argon2id",
+			password: password,
+			hash:     argon2Hash,
+			valid:    true,
+		},
+		{
+			desc:     "Success_Argon2idRecordedParameters",
+			password: password,
+			hash:     otherArgon2Hash,
+			valid:    true,
+		},
+		{
+			desc:     "Success_Bcrypt",
+			password: password,
+			hash:     bcryptHash,
+			valid:    true,
+		},
+		{
+			desc:     "Fail_Argon2idMismatchedPassword",
+			password: "wrong password",
+			hash:     argon2Hash,
+			valid:    false,
+		},
+		{
+			desc:     "Fail_BcryptMismatchedPassword",
+			password: "wrong password",
+			hash:     bcryptHash,
+			valid:    false,
+		},
+		{
+			desc:     "Fail_Argon2idMissingField",
+			password: password,
+			hash:     "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_Argon2idMalformedVersion",
+			password: password,
+			hash:     "$argon2id$version$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_Argon2idMalformedParameters",
+			password: password,
+			hash:     "$argon2id$v=19$memory=64$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_Argon2idZeroIterations",
+			password: password,
+			hash:     "$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_Argon2idZeroParallelism",
+			password: password,
+			hash:     "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_Argon2idMalformedSalt",
+			password: password,
+			hash:     "$argon2id$v=19$m=64,t=1,p=1$not*base64$a2V5",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_Argon2idEmptyKey",
+			password: password,
+			hash:     "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_BcryptMalformed",
+			password: password,
+			hash:     "$2a$04$short",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_UnknownAlgorithm",
+			password: password,
+			hash:     "$5$rounds=5000$salt$hash",
+			valid:    false,
+		},
+		{
+			desc:     "Fail_PlainPassword",
+			password: password,
+			hash:     password,
+			valid:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for _, algorithm := range []string{"argon2id", "bcrypt"} {
+				h, err := hasher.New(newPasswordConfig(algorithm))
+				require.NoError(t, err)
+
+				err = h.Compare(tc.password, tc.hash)
+				assert.Equal(t, tc.valid, err == nil, "Comparison mismatch with active %s", algorithm)
+			}
+		})
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	argon2Hash := newHash(t, newPasswordConfig("argon2id"))
+	bcryptHash := newHash(t, newPasswordConfig("bcrypt"))
+
+	otherArgon2Config := newPasswordConfig("argon2id")
+	otherArgon2Config.Argon2Parallelism = "2"
+	otherArgon2Hash := newHash(t, otherArgon2Config)
+
+	otherBcryptConfig := newPasswordConfig("bcrypt")
+	otherBcryptConfig.BcryptCost = "5"
+	otherBcryptHash := newHash(t, otherBcryptConfig)
+
+	testCases := []struct {
+		desc     string
+		active   string
+		hash     string
+		expected bool
+	}{
+		{
+			desc:     "Argon2id_SameParameters",
+			active:   "argon2id",
+			hash:     argon2Hash,
+			expected: false,
+		},
+		{
+			desc:     "Argon2id_OtherParameters",
+			active:   "argon2id",
+			hash:     otherArgon2Hash,
+			expected: true,
+		},
+		{
+			desc:     "Argon2id_BcryptHash",
+			active:   "argon2id",
+			hash:     bcryptHash,
+			expected: true,
+		},
+		{
+			desc:     "Argon2id_MalformedHash",
+			active:   "argon2id",
+			hash:     "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA",
+			expected: true,
+		},
+		{
+			desc:     "Bcrypt_SameCost",
+			active:   "bcrypt",
+			hash:     bcryptHash,
+			expected: false,
+		},
+		{
+			desc:     "Bcrypt_OtherCost",
+			active:   "bcrypt",
+			hash:     otherBcryptHash,
+			expected: true,
+		},
+		{
+			desc:     "Bcrypt_Argon2idHash",
+			active:   "bcrypt",
+			hash:     argon2Hash,
+			expected: true,
+		},
+		{
+			desc:     "Bcrypt_MalformedHash",
+			active:   "bcrypt",
+			hash:     "$2a$04$short",
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := hasher.New(newPasswordConfig(tc.active))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, h.NeedsRehash(tc.hash), "Rehash mismatch")
+		})
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	// withConfig changes a field of a valid configuration
+	withConfig := func(change func(passwordConfig *config.Password)) *config.Password {
+		passwordConfig := newPasswordConfig("argon2id")
+		change(passwordConfig)
+		return passwordConfig
+	}
+
+	testCases := []struct {
+		desc   string
+		config *config.Password
+	}{
+		{
+			desc:   "UnsupportedAlgorithm",
+			config: newPasswordConfig("scrypt"),
+		},
+		{
+			desc:   "BcryptCostTooLow",
+			config: withConfig(func(c *config.Password) { c.BcryptCost = "3" }),
+		},
+		{
+			desc:   "BcryptCostTooHigh",
+			config: withConfig(func(c *config.Password) { c.BcryptCost = "32" }),
+		},
+		{
+			desc:   "MalformedArgon2Memory",
+			config: withConfig(func(c *config.Password) { c.Argon2Memory = "64KiB" }),
+		},
+		{
+			desc:   "ZeroArgon2Iterations",
+			config: withConfig(func(c *config.Password) { c.Argon2Iterations = "0" }),
+		},
+		{
+			desc:   "ZeroArgon2Parallelism",
+			config: withConfig(func(c *config.Password) { c.Argon2Parallelism = "0" }),
+		},
+		{
+			desc:   "Argon2ParallelismOverflow",
+			config: withConfig(func(c *config.Password) { c.Argon2Parallelism = "256" }),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := hasher.New(tc.config)
+			assert.Error(t, err, "Error mismatch")
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/keysource"
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// algorithmHS256 signs tokens with HMAC using SHA-256 and a shared secret
+	algorithmHS256 = "HS256"
+	// algorithmRS256 signs tokens with RSASSA-PKCS1-v1_5 using SHA-256
+	algorithmRS256 = "RS256"
+	// algorithmEdDSA signs tokens with Ed25519
+	algorithmEdDSA = "EdDSA"
+	// minSecretSize is the minimum size in bytes of an HS256 secret
+	minSecretSize = 32
+	// rsaKeySize is the size in bits of ephemeral RSA keys
+	rsaKeySize = 2048
+)
+
+// claims represents the claims of a jwt token
+type claims struct {
+	Payload *domain.TokenPayload `json:"payload"`
+	jwt.RegisteredClaims
+}
+
+/**
+ * JWTToken implements port.TokenService and port.PublicKeyProvider interfaces
+ * and provides an access to the jwt library
+ */
+type JWTToken struct {
+	method   jwt.SigningMethod
+	activeID string
+	ids      []string
+	signing  map[string]crypto.PrivateKey
+	parser   *jwt.Parser
+	duration time.Duration
+	issuer   string
+	audience string
+}
+
+// New creates a new jwt instance
+func New(config *config.Token) (*JWTToken, error) {
+	durationStr := config.Duration
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return nil, domain.ErrTokenDuration
+	}
+
+	algorithm := config.Algorithm
+	if algorithm == "" {
+		algorithm = algorithmHS256
+	}
+
+	method := jwt.GetSigningMethod(algorithm)
+	if method == nil || (algorithm != algorithmHS256 && algorithm != algorithmRS256 && algorithm != algorithmEdDSA) {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", domain.ErrTokenKey, algorithm)
+	}
+
+	entries, err := keysource.Load(config)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	jt := &JWTToken{
+		method:   method,
+		signing:  make(map[string]crypto.PrivateKey),
+		parser:   jwt.NewParser(options...),
+		duration: duration,
+		issuer:   config.Issuer,
+		audience: config.Audience,
+	}
+
+	if len(entries) == 0 {
+		return jt, jt.addEphemeralKey()
+	}
+
+	jt.activeID, err = keysource.ActiveID(config, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		key, err := parseKey(algorithm, entry.Material)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", domain.ErrTokenKey, entry.ID, err)
+		}
+
+		jt.ids = append(jt.ids, entry.ID)
+		jt.signing[entry.ID] = key
+	}
+
+	return jt, nil
+}
+
+// CreateToken creates a new jwt token, filling in the payload ID, validity period and standard claims
+func (jt *JWTToken) CreateToken(payload *domain.TokenPayload) (string, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", domain.ErrTokenCreation
+	}
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(jt.duration)
+
+	payload.ID = id
+	payload.Issuer = jt.issuer
+	payload.Audience = jt.audience
+	payload.IssuedAt = issuedAt
+	payload.ExpiredAt = expiredAt
+
+	if payload.Subject == "" {
+		payload.Subject = strconv.FormatUint(payload.UserID, 10)
+	}
+
+	var audience jwt.ClaimStrings
+	if jt.audience != "" {
+		audience = jwt.ClaimStrings{jt.audience}
+	}
+
+	tokenClaims := claims{
+		Payload: payload,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.String(),
+			Issuer:    jt.issuer,
+			Subject:   payload.Subject,
+			Audience:  audience,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiredAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jt.method, tokenClaims)
+	token.Header["kid"] = jt.activeID
+
+	signed, err := token.SignedString(jt.signing[jt.activeID])
+	if err != nil {
+		return "", domain.ErrTokenCreation
+	}
+
+	return signed, nil
+}
+
+// VerifyToken verifies the jwt token and its issuer and audience
+func (jt *JWTToken) VerifyToken(token string) (*domain.TokenPayload, error) {
+	var tokenClaims claims
+
+	_, err := jt.parser.ParseWithClaims(token, &tokenClaims, jt.verificationKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			return nil, domain.ErrExpiredToken
+		case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+			return nil, domain.ErrInvalidTokenIssuer
+		case errors.Is(err, jwt.ErrTokenInvalidAudience):
+			return nil, domain.ErrInvalidTokenAudience
+		default:
+			return nil, domain.ErrInvalidToken
+		}
+	}
+
+	if tokenClaims.Payload == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return tokenClaims.Payload, nil
+}
+
+// PublicKeys returns the public keys used to verify asymmetric tokens,
+// it is empty when the tokens are signed with a shared secret
+func (jt *JWTToken) PublicKeys() []domain.PublicKey {
+	keys := []domain.PublicKey{}
+
+	for _, id := range jt.ids {
+		signer, ok := jt.signing[id].(crypto.Signer)
+		if !ok {
+			continue
+		}
+
+		keys = append(keys, domain.PublicKey{
+			ID:        id,
+			Algorithm: jt.method.Alg(),
+			Key:       signer.Public(),
+		})
+	}
+
+	return keys
+}
+
+// verificationKey looks up the key identified by the token header
+func (jt *JWTToken) verificationKey(token *jwt.Token) (any, error) {
+	keyID, _ := token.Header["kid"].(string)
+
+	key, ok := jt.signing[keyID]
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if signer, ok := key.(crypto.Signer); ok {
+		return signer.Public(), nil
+	}
+
+	return key, nil
+}
+
+// addEphemeralKey adds a random key that only lives as long as the process
+func (jt *JWTToken) addEphemeralKey() error {
+	slog.Warn("No token keys configured, using an ephemeral key. Tokens will not survive a restart")
+
+	var key crypto.PrivateKey
+	var err error
+
+	switch jt.method.Alg() {
+	case algorithmRS256:
+		key, err = rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case algorithmEdDSA:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		secret := make([]byte, minSecretSize)
+		_, err = rand.Read(secret)
+		key = secret
+	}
+	if err != nil {
+		return err
+	}
+
+	jt.activeID = uuid.NewString()
+	jt.ids = []string{jt.activeID}
+	jt.signing[jt.activeID] = key
+
+	return nil
+}
+
+// parseKey parses the key material for the given algorithm.
+// HS256 secrets are hex encoded, RS256 keys are PEM encoded and
+// EdDSA keys are either PEM encoded or a hex encoded seed or secret key
+func parseKey(algorithm, material string) (crypto.PrivateKey, error) {
+	switch algorithm {
+	case algorithmRS256:
+		return jwt.ParseRSAPrivateKeyFromPEM([]byte(material))
+	case algorithmEdDSA:
+		if strings.HasPrefix(material, "-----BEGIN") {
+			return jwt.ParseEdPrivateKeyFromPEM([]byte(material))
+		}
+
+		bytes, err := hex.DecodeString(material)
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(bytes) {
+		case ed25519.SeedSize:
+			return ed25519.NewKeyFromSeed(bytes), nil
+		case ed25519.PrivateKeySize:
+			return ed25519.PrivateKey(bytes), nil
+		default:
+			return nil, errors.New("invalid ed25519 key size")
+		}
+	default:
+		secret, err := hex.DecodeString(material)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(secret) < minSecretSize {
+			return nil, fmt.Errorf("secret must be at least %d bytes", minSecretSize)
+		}
+
+		return secret, nil
+	}
+}
@@ -0,0 +1,290 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/jwt"
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldSecret = "707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f"
+	newSecret = "a0a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebf"
+	edSeed    = "c0c1c2c3c4c5c6c7c8c9cacbcccdcecfd0d1d2d3d4d5d6d7d8d9dadbdcdddedf"
+)
+
+// newTokenConfig creates a token configuration with the given algorithm, keys and active key
+func newTokenConfig(algorithm, keys, activeKeyID string) *config.Token {
+	return &config.Token{
+		Duration:    "15m",
+		Algorithm:   algorithm,
+		Keys:        keys,
+		ActiveKeyID: activeKeyID,
+	}
+}
+
+// writeRSAKey writes a new PEM encoded RSA private key to a file and returns its key source
+func writeRSAKey(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "rsa.pem")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600)
+	require.NoError(t, err)
+
+	return "file:" + path
+}
+
+// tokenHeader decodes the header of a token
+func tokenHeader(t *testing.T, token string) map[string]any {
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3, "Malformed token")
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+
+	var header map[string]any
+	err = json.Unmarshal(rawHeader, &header)
+	require.NoError(t, err)
+
+	return header
+}
+
+func TestJWTToken_Algorithms(t *testing.T) {
+	rsaKey := writeRSAKey(t)
+
+	testCases := []struct {
+		desc      string
+		config    *config.Token
+		algorithm string
+		publicKey func(key any) bool
+	}{
+		{
+			desc:      "Default",
+			config:    newTokenConfig("", "k1:"+oldSecret, ""),
+			algorithm: "HS256",
+		},
+		{
+			desc:      "HS256",
+			config:    newTokenConfig("HS256", "k1:"+oldSecret, ""),
+			algorithm: "HS256",
+		},
+		{
+			desc:      "RS256",
+			config:    newTokenConfig("RS256", "k1:"+rsaKey, ""),
+			algorithm: "RS256",
+			publicKey: func(key any) bool {
+				_, ok := key.(*rsa.PublicKey)
+				return ok
+			},
+		},
+		{
+			desc:      "EdDSA",
+			config:    newTokenConfig("EdDSA", "k1:"+edSeed, ""),
+			algorithm: "EdDSA",
+			publicKey: func(key any) bool {
+				_, ok := key.(ed25519.PublicKey)
+				return ok
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			jt, err := jwt.New(tc.config)
+			require.NoError(t, err)
+
+			payload := &domain.TokenPayload{
+				UserID: 1,
+				Role:   domain.Cashier,
+			}
+
+			token, err := jt.CreateToken(payload)
+			require.NoError(t, err)
+
+			header := tokenHeader(t, token)
+			assert.Equal(t, tc.algorithm, header["alg"], "Algorithm mismatch")
+			assert.Equal(t, "k1", header["kid"], "Key ID mismatch")
+
+			output, err := jt.VerifyToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, payload.ID, output.ID, "Token ID mismatch")
+			assert.Equal(t, "1", output.Subject, "Subject mismatch")
+
+			keys := jt.PublicKeys()
+			if tc.publicKey == nil {
+				assert.Empty(t, keys, "Shared secret exposed")
+				return
+			}
+
+			require.Len(t, keys, 1, "Public keys mismatch")
+			assert.Equal(t, "k1", keys[0].ID, "Key ID mismatch")
+			assert.Equal(t, tc.algorithm, keys[0].Algorithm, "Algorithm mismatch")
+			assert.True(t, tc.publicKey(keys[0].Key), "Public key type mismatch")
+		})
+	}
+}
+
+func TestJWTToken_VerifyToken(t *testing.T) {
+	rsaKey := writeRSAKey(t)
+
+	issuerConfig := newTokenConfig("HS256", "old:"+oldSecret, "")
+	issuerConfig.Issuer = "go-ws"
+	issuerConfig.Audience = "go-ws-api"
+
+	// withConfig copies the issuer configuration with the given keys and active key
+	withConfig := func(algorithm, keys, activeKeyID string) *config.Token {
+		tokenConfig := newTokenConfig(algorithm, keys, activeKeyID)
+		tokenConfig.Issuer = issuerConfig.Issuer
+		tokenConfig.Audience = issuerConfig.Audience
+		return tokenConfig
+	}
+
+	otherIssuerConfig := withConfig("HS256", "old:"+oldSecret, "")
+	otherIssuerConfig.Issuer = "other"
+
+	otherAudienceConfig := withConfig("HS256", "old:"+oldSecret, "")
+	otherAudienceConfig.Audience = "other"
+
+	testCases := []struct {
+		desc     string
+		config   *config.Token
+		expected error
+	}{
+		{
+			desc:     "Success_SameKey",
+			config:   issuerConfig,
+			expected: nil,
+		},
+		{
+			desc:     "Success_RetiredKey",
+			config:   withConfig("HS256", "new:"+newSecret+",old:"+oldSecret, "new"),
+			expected: nil,
+		},
+		{
+			desc:     "Fail_RemovedKey",
+			config:   withConfig("HS256", "new:"+newSecret, ""),
+			expected: domain.ErrInvalidToken,
+		},
+		{
+			desc:     "Fail_DifferentKeyWithSameID",
+			config:   withConfig("HS256", "old:"+newSecret, ""),
+			expected: domain.ErrInvalidToken,
+		},
+		{
+			desc:     "Fail_UnexpectedAlgorithm",
+			config:   withConfig("RS256", "old:"+rsaKey, ""),
+			expected: domain.ErrInvalidToken,
+		},
+		{
+			desc:     "Fail_Issuer",
+			config:   otherIssuerConfig,
+			expected: domain.ErrInvalidTokenIssuer,
+		},
+		{
+			desc:     "Fail_Audience",
+			config:   otherAudienceConfig,
+			expected: domain.ErrInvalidTokenAudience,
+		},
+	}
+
+	issuer, err := jwt.New(issuerConfig)
+	require.NoError(t, err)
+
+	token, err := issuer.CreateToken(&domain.TokenPayload{UserID: 1})
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			verifier, err := jwt.New(tc.config)
+			require.NoError(t, err)
+
+			_, err = verifier.VerifyToken(token)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
+
+func TestJWTToken_PublicKeys(t *testing.T) {
+	otherSeed := strings.Repeat("ab", ed25519.SeedSize)
+
+	jt, err := jwt.New(newTokenConfig("EdDSA", "new:"+edSeed+",old:"+otherSeed, "new"))
+	require.NoError(t, err)
+
+	token, err := jt.CreateToken(&domain.TokenPayload{UserID: 1})
+	require.NoError(t, err)
+
+	keys := jt.PublicKeys()
+	require.Len(t, keys, 2, "Public keys mismatch")
+	assert.Equal(t, "new", keys[0].ID, "Declaration order mismatch")
+	assert.Equal(t, "old", keys[1].ID, "Declaration order mismatch")
+
+	// a downstream verifier checks the signature with the published key of the token's kid
+	parts := strings.Split(token, ".")
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+
+	publicKey := keys[0].Key.(ed25519.PublicKey)
+	assert.True(t, ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature), "Signature mismatch")
+	assert.False(t, ed25519.Verify(keys[1].Key.(ed25519.PublicKey), []byte(parts[0]+"."+parts[1]), signature), "Signature verified by another key")
+}
+
+func TestNew_InvalidKeys(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *config.Token
+	}{
+		{
+			desc:   "UnsupportedAlgorithm",
+			config: newTokenConfig("HS512", "k1:"+oldSecret, ""),
+		},
+		{
+			desc:   "ShortSecret",
+			config: newTokenConfig("HS256", "k1:0102030405", ""),
+		},
+		{
+			desc:   "InvalidEd25519Size",
+			config: newTokenConfig("EdDSA", "k1:0102030405", ""),
+		},
+		{
+			desc:   "MalformedRSAKey",
+			config: newTokenConfig("RS256", "k1:"+oldSecret, ""),
+		},
+		{
+			desc:   "UnknownActiveKey",
+			config: newTokenConfig("HS256", "k1:"+oldSecret, "k2"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := jwt.New(tc.config)
+			assert.ErrorIs(t, err, domain.ErrTokenKey, "Error mismatch")
+		})
+	}
+}
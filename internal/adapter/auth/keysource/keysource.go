@@ -0,0 +1,140 @@
+package keysource
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+const (
+	// sourceFile is the prefix of a key read from a file
+	sourceFile = "file:"
+	// sourceEnv is the prefix of a key read from an environment variable
+	sourceEnv = "env:"
+)
+
+// Entry is a key declared in the token configuration
+type Entry struct {
+	ID       string
+	Material string
+}
+
+// Load reads the keys declared in the token configuration, in declaration order.
+// Keys are declared as "id:source" entries, either comma separated in TOKEN_KEYS
+// or one per line in TOKEN_KEYS_FILE, where source is the key itself,
+// "file:/path/to/key" or "env:VARIABLE_NAME"
+func Load(config *config.Token) ([]Entry, error) {
+	declarations := splitEntries(config.Keys)
+
+	if config.KeysFile != "" {
+		fileDeclarations, err := readEntries(config.KeysFile)
+		if err != nil {
+			return nil, err
+		}
+		declarations = append(declarations, fileDeclarations...)
+	}
+
+	var entries []Entry
+	seen := make(map[string]bool, len(declarations))
+
+	for _, declaration := range declarations {
+		id, source, found := strings.Cut(declaration, ":")
+		if !found || id == "" {
+			return nil, fmt.Errorf("%w: malformed entry for key %q", domain.ErrTokenKey, id)
+		}
+
+		if seen[id] {
+			return nil, fmt.Errorf("%w: duplicated key %q", domain.ErrTokenKey, id)
+		}
+		seen[id] = true
+
+		material, err := loadSource(source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", domain.ErrTokenKey, id, err)
+		}
+
+		entries = append(entries, Entry{id, material})
+	}
+
+	return entries, nil
+}
+
+// ActiveID returns the configured active key ID, defaulting to the first declared key
+func ActiveID(config *config.Token, entries []Entry) (string, error) {
+	if config.ActiveKeyID == "" {
+		return entries[0].ID, nil
+	}
+
+	for _, entry := range entries {
+		if entry.ID == config.ActiveKeyID {
+			return entry.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: active key %q not found", domain.ErrTokenKey, config.ActiveKeyID)
+}
+
+// splitEntries splits a comma separated list of key entries
+func splitEntries(value string) []string {
+	var entries []string
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// readEntries reads key entries from a file, one per line, ignoring blank lines and comments
+func readEntries(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrTokenKey, err)
+	}
+	defer file.Close()
+
+	var entries []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrTokenKey, err)
+	}
+
+	return entries, nil
+}
+
+// loadSource resolves a key source into the key material
+func loadSource(source string) (string, error) {
+	switch {
+	case strings.HasPrefix(source, sourceFile):
+		bytes, err := os.ReadFile(strings.TrimPrefix(source, sourceFile))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(bytes)), nil
+	case strings.HasPrefix(source, sourceEnv):
+		name := strings.TrimPrefix(source, sourceEnv)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return strings.TrimSpace(value), nil
+	default:
+		return source, nil
+	}
+}
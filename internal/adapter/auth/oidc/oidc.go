@@ -0,0 +1,108 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+/**
+ * Provider implements port.IdentityProvider interface
+ * and signs users in with an OpenID Connect provider using the authorization code flow with PKCE.
+ * The endpoints and signing keys are discovered from the issuer,
+ * and the ID token is verified against the issuer's JWKS
+ */
+type Provider struct {
+	name     string
+	verifier *oidc.IDTokenVerifier
+	config   oauth2.Config
+}
+
+// claims are the ID token claims used to link the external identity to a user
+type claims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// New discovers the OpenID Connect provider configuration from the issuer and creates a new provider instance
+func New(ctx context.Context, config *config.OIDC) (*Provider, error) {
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(config.Scopes)
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+
+	return &Provider{
+		name: config.Provider,
+		verifier: provider.Verifier(&oidc.Config{
+			ClientID: config.ClientID,
+		}),
+		config: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  config.RedirectURL,
+			Scopes:       scopes,
+		},
+	}, nil
+}
+
+// Name returns the name the provider's identities are linked under
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL returns the url of the provider's consent page, bound to the state, nonce and PKCE code verifier
+func (p *Provider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.config.AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(codeVerifier),
+	)
+}
+
+// Exchange redeems the authorization code and returns the identity from the verified ID token
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalIdentity, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	var claims claims
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ExternalIdentity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
@@ -0,0 +1,229 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/oidc"
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	clientID     = "go-ws"
+	clientSecret = "client-secret"
+	redirectURL  = "http://localhost:5173/login/oidc/callback"
+	keyID        = "test-key"
+	authCode     = "SplxlOBeZQQYbYS6WxSbIA"
+	codeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+// fakeIdP is an in-process OpenID Connect provider serving discovery, JWKS and a token endpoint
+type fakeIdP struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+	// claims returns the claims of the id_token issued for the nonce of the authorization request
+	claims func(issuer, nonce string) map[string]any
+	// nonce and challenge are taken from the last authorization request
+	nonce     string
+	challenge string
+}
+
+// newFakeIdP starts a fake identity provider, the handler for the token endpoint checks the client credentials and PKCE code verifier
+func newFakeIdP(t *testing.T) *fakeIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &fakeIdP{
+		key: key,
+		claims: func(issuer, nonce string) map[string]any {
+			return map[string]any{
+				"iss":            issuer,
+				"aud":            clientID,
+				"sub":            "248289761001",
+				"exp":            time.Now().Add(time.Hour).Unix(),
+				"iat":            time.Now().Unix(),
+				"nonce":          nonce,
+				"email":          "test@example.com",
+				"email_verified": true,
+				"name":           "John Doe",
+			}
+		},
+	}
+
+	discovery := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{
+			{
+				PublicKey: key.Public(),
+				KeyID:     keyID,
+				Algorithm: gooidc.RS256,
+			},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/.well-known/openid-configuration", discovery)
+	mux.Handle("/keys", discovery)
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != clientID || secret != clientSecret {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("code") != authCode ||
+			r.PostFormValue("redirect_uri") != redirectURL ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != idp.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		claims, err := json.Marshal(idp.claims(idp.URL, idp.nonce))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "idp-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     oidctest.SignIDToken(idp.key, keyID, gooidc.RS256, string(claims)),
+		})
+	})
+
+	idp.Server = httptest.NewServer(mux)
+	discovery.SetIssuer(idp.URL)
+	t.Cleanup(idp.Close)
+
+	return idp
+}
+
+// authorize follows the authorization url like the user's browser would and records the nonce and code challenge
+func (idp *fakeIdP) authorize(t *testing.T, authorizationURL string) {
+	u, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+
+	query := u.Query()
+	require.Equal(t, idp.URL+"/auth", fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path), "Authorization endpoint mismatch")
+	require.Equal(t, clientID, query.Get("client_id"), "Client mismatch")
+	require.Equal(t, "S256", query.Get("code_challenge_method"), "Code challenge method mismatch")
+
+	idp.nonce = query.Get("nonce")
+	idp.challenge = query.Get("code_challenge")
+}
+
+// newProvider discovers the fake identity provider
+func newProvider(t *testing.T, idp *fakeIdP) *oidc.Provider {
+	provider, err := oidc.New(context.Background(), &config.OIDC{
+		Provider:     "corporate",
+		Issuer:       idp.URL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       "openid email profile",
+	})
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		provider := newProvider(t, idp)
+
+		idp.authorize(t, provider.AuthCodeURL("state", "nonce", codeVerifier))
+
+		identity, err := provider.Exchange(ctx, authCode, codeVerifier, "nonce")
+		require.NoError(t, err)
+		assert.Equal(t, "corporate", identity.Provider, "Provider mismatch")
+		assert.Equal(t, "248289761001", identity.Subject, "Subject mismatch")
+		assert.Equal(t, "test@example.com", identity.Email, "Email mismatch")
+		assert.True(t, identity.EmailVerified, "Email verified mismatch")
+		assert.Equal(t, "John Doe", identity.Name, "Name mismatch")
+	})
+
+	t.Run("Fail_NonceMismatch", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		provider := newProvider(t, idp)
+
+		idp.authorize(t, provider.AuthCodeURL("state", "nonce", codeVerifier))
+
+		_, err := provider.Exchange(ctx, authCode, codeVerifier, "another-nonce")
+		assert.Error(t, err, "Error mismatch")
+	})
+
+	t.Run("Fail_CodeVerifierMismatch", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		provider := newProvider(t, idp)
+
+		idp.authorize(t, provider.AuthCodeURL("state", "nonce", codeVerifier))
+
+		_, err := provider.Exchange(ctx, authCode, "another-code-verifier-another-code-verifier", "nonce")
+		assert.Error(t, err, "Error mismatch")
+	})
+
+	t.Run("Fail_AudienceMismatch", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		claims := idp.claims
+		idp.claims = func(issuer, nonce string) map[string]any {
+			tokenClaims := claims(issuer, nonce)
+			tokenClaims["aud"] = "another-client"
+			return tokenClaims
+		}
+		provider := newProvider(t, idp)
+
+		idp.authorize(t, provider.AuthCodeURL("state", "nonce", codeVerifier))
+
+		_, err := provider.Exchange(ctx, authCode, codeVerifier, "nonce")
+		assert.Error(t, err, "Error mismatch")
+	})
+
+	t.Run("Fail_Expired", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		claims := idp.claims
+		idp.claims = func(issuer, nonce string) map[string]any {
+			tokenClaims := claims(issuer, nonce)
+			tokenClaims["exp"] = time.Now().Add(-time.Hour).Unix()
+			return tokenClaims
+		}
+		provider := newProvider(t, idp)
+
+		idp.authorize(t, provider.AuthCodeURL("state", "nonce", codeVerifier))
+
+		_, err := provider.Exchange(ctx, authCode, codeVerifier, "nonce")
+		assert.Error(t, err, "Error mismatch")
+	})
+
+	t.Run("Fail_UnknownSigningKey", func(t *testing.T) {
+		idp := newFakeIdP(t)
+		provider := newProvider(t, idp)
+
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		idp.key = otherKey
+
+		idp.authorize(t, provider.AuthCodeURL("state", "nonce", codeVerifier))
+
+		_, err = provider.Exchange(ctx, authCode, codeVerifier, "nonce")
+		assert.Error(t, err, "Error mismatch")
+	})
+}
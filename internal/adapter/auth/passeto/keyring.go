@@ -0,0 +1,183 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"log/slog"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/cidmiranda/go-ws/internal/adapter/auth/keysource"
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// purposeLocal is the token purpose for symmetric encrypted tokens (v4.local)
+	purposeLocal = "local"
+	// purposePublic is the token purpose for asymmetric signed tokens (v4.public)
+	purposePublic = "public"
+	// algorithmPublic identifies the public keys exposed to downstream verifiers
+	algorithmPublic = "v4.public"
+)
+
+// footer is the unencrypted part of a token that identifies the key used to create it
+type footer struct {
+	KeyID string `json:"kid"`
+}
+
+/**
+ * keyRing holds every key accepted for verification, identified by key ID.
+ * New tokens are always created with the active key, while the other keys
+ * stay valid for verification until they are removed from the configuration.
+ * Depending on the purpose, it holds either symmetric keys or Ed25519 secret keys
+ */
+type keyRing struct {
+	purpose  string
+	activeID string
+	ids      []string
+	local    map[string]paseto.V4SymmetricKey
+	secret   map[string]paseto.V4AsymmetricSecretKey
+}
+
+// newKeyRing loads the key ring from the token configuration
+func newKeyRing(config *config.Token) (*keyRing, error) {
+	purpose := config.Purpose
+	if purpose == "" {
+		purpose = purposeLocal
+	}
+
+	if purpose != purposeLocal && purpose != purposePublic {
+		return nil, fmt.Errorf("%w: unsupported purpose %q", domain.ErrTokenKey, purpose)
+	}
+
+	entries, err := keysource.Load(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return newEphemeralKeyRing(purpose), nil
+	}
+
+	activeID, err := keysource.ActiveID(config, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	ring := &keyRing{
+		purpose:  purpose,
+		activeID: activeID,
+		local:    make(map[string]paseto.V4SymmetricKey),
+		secret:   make(map[string]paseto.V4AsymmetricSecretKey),
+	}
+
+	for _, entry := range entries {
+		err = ring.add(entry.ID, entry.Material)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", domain.ErrTokenKey, entry.ID, err)
+		}
+	}
+
+	return ring, nil
+}
+
+// newEphemeralKeyRing creates a key ring with a random key that only lives as long as the process
+func newEphemeralKeyRing(purpose string) *keyRing {
+	slog.Warn("No token keys configured, using an ephemeral key. Tokens will not survive a restart")
+
+	id := uuid.NewString()
+
+	ring := &keyRing{
+		purpose:  purpose,
+		activeID: id,
+		ids:      []string{id},
+		local:    make(map[string]paseto.V4SymmetricKey),
+		secret:   make(map[string]paseto.V4AsymmetricSecretKey),
+	}
+
+	if purpose == purposePublic {
+		ring.secret[id] = paseto.NewV4AsymmetricSecretKey()
+	} else {
+		ring.local[id] = paseto.NewV4SymmetricKey()
+	}
+
+	return ring
+}
+
+// add parses a hex encoded key according to the key ring purpose and adds it to the ring.
+// Ed25519 keys may be given either as a 64 bytes secret key or as a 32 bytes seed
+func (kr *keyRing) add(id, hexKey string) error {
+	switch kr.purpose {
+	case purposePublic:
+		var key paseto.V4AsymmetricSecretKey
+		var err error
+
+		if len(hexKey) == ed25519.SeedSize*2 {
+			key, err = paseto.NewV4AsymmetricSecretKeyFromSeed(hexKey)
+		} else {
+			key, err = paseto.NewV4AsymmetricSecretKeyFromHex(hexKey)
+		}
+		if err != nil {
+			return err
+		}
+
+		kr.secret[id] = key
+	default:
+		key, err := paseto.V4SymmetricKeyFromHex(hexKey)
+		if err != nil {
+			return err
+		}
+
+		kr.local[id] = key
+	}
+
+	kr.ids = append(kr.ids, id)
+
+	return nil
+}
+
+// activeLocal returns the key ID and the symmetric key used to create new tokens
+func (kr *keyRing) activeLocal() (string, paseto.V4SymmetricKey) {
+	return kr.activeID, kr.local[kr.activeID]
+}
+
+// activeSecret returns the key ID and the secret key used to sign new tokens
+func (kr *keyRing) activeSecret() (string, paseto.V4AsymmetricSecretKey) {
+	return kr.activeID, kr.secret[kr.activeID]
+}
+
+// getLocal returns the symmetric key with the given ID
+func (kr *keyRing) getLocal(id string) (paseto.V4SymmetricKey, bool) {
+	key, ok := kr.local[id]
+	return key, ok
+}
+
+// getPublic returns the public key matching the secret key with the given ID
+func (kr *keyRing) getPublic(id string) (paseto.V4AsymmetricPublicKey, bool) {
+	key, ok := kr.secret[id]
+	if !ok {
+		return paseto.V4AsymmetricPublicKey{}, false
+	}
+	return key.Public(), true
+}
+
+// publicKeys returns the public keys of the ring in declaration order
+func (kr *keyRing) publicKeys() []domain.PublicKey {
+	keys := []domain.PublicKey{}
+
+	for _, id := range kr.ids {
+		secret, ok := kr.secret[id]
+		if !ok {
+			continue
+		}
+
+		keys = append(keys, domain.PublicKey{
+			ID:        id,
+			Algorithm: algorithmPublic,
+			Key:       ed25519.PublicKey(secret.Public().ExportBytes()),
+		})
+	}
+
+	return keys
+}
@@ -1,80 +1,117 @@
 package paseto
 
 import (
+	"encoding/json"
+	"strconv"
 	"time"
 
 	"aidanwoods.dev/go-paseto"
 	"github.com/cidmiranda/go-ws/internal/adapter/config"
 	"github.com/cidmiranda/go-ws/internal/core/domain"
-	"github.com/cidmiranda/go-ws/internal/core/port"
 	"github.com/google/uuid"
 )
 
 /**
- * PasetoToken implements port.TokenService interface
+ * PasetoToken implements port.TokenService and port.PublicKeyProvider interfaces
  * and provides an access to the paseto library
  */
 type PasetoToken struct {
-	token    *paseto.Token
-	key      *paseto.V4SymmetricKey
+	keys     *keyRing
 	parser   *paseto.Parser
 	duration time.Duration
+	issuer   string
+	audience string
 }
 
 // New creates a new paseto instance
-func New(config *config.Token) (port.TokenService, error) {
+func New(config *config.Token) (*PasetoToken, error) {
 	durationStr := config.Duration
 	duration, err := time.ParseDuration(durationStr)
 	if err != nil {
 		return nil, domain.ErrTokenDuration
 	}
 
-	token := paseto.NewToken()
-	key := paseto.NewV4SymmetricKey()
+	keys, err := newKeyRing(config)
+	if err != nil {
+		return nil, err
+	}
+
 	parser := paseto.NewParser()
 
 	return &PasetoToken{
-		&token,
-		&key,
+		keys,
 		&parser,
 		duration,
+		config.Issuer,
+		config.Audience,
 	}, nil
 }
 
-// CreateToken creates a new paseto token
-func (pt *PasetoToken) CreateToken(user *domain.User) (string, error) {
+// CreateToken creates a new paseto token, filling in the payload ID, validity period and standard claims
+func (pt *PasetoToken) CreateToken(payload *domain.TokenPayload) (string, error) {
 	id, err := uuid.NewRandom()
 	if err != nil {
 		return "", domain.ErrTokenCreation
 	}
 
-	payload := &domain.TokenPayload{
-		ID:     id,
-		UserID: user.ID,
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(pt.duration)
+
+	payload.ID = id
+	payload.Issuer = pt.issuer
+	payload.Audience = pt.audience
+	payload.IssuedAt = issuedAt
+	payload.ExpiredAt = expiredAt
+
+	if payload.Subject == "" {
+		payload.Subject = strconv.FormatUint(payload.UserID, 10)
 	}
 
-	err = pt.token.Set("payload", payload)
+	token := paseto.NewToken()
+
+	err = token.Set("payload", payload)
 	if err != nil {
 		return "", domain.ErrTokenCreation
 	}
 
-	issuedAt := time.Now()
-	expiredAt := issuedAt.Add(pt.duration)
+	token.SetJti(id.String())
+	token.SetSubject(payload.Subject)
+	if pt.issuer != "" {
+		token.SetIssuer(pt.issuer)
+	}
+	if pt.audience != "" {
+		token.SetAudience(pt.audience)
+	}
+	token.SetIssuedAt(issuedAt)
+	token.SetNotBefore(issuedAt)
+	token.SetExpiration(expiredAt)
+
+	if pt.keys.purpose == purposePublic {
+		keyID, key := pt.keys.activeSecret()
 
-	pt.token.SetIssuedAt(issuedAt)
-	pt.token.SetNotBefore(issuedAt)
-	pt.token.SetExpiration(expiredAt)
+		err = setFooter(&token, keyID)
+		if err != nil {
+			return "", domain.ErrTokenCreation
+		}
+
+		return token.V4Sign(key, nil), nil
+	}
+
+	keyID, key := pt.keys.activeLocal()
 
-	token := pt.token.V4Encrypt(*pt.key, nil)
+	err = setFooter(&token, keyID)
+	if err != nil {
+		return "", domain.ErrTokenCreation
+	}
 
-	return token, nil
+	return token.V4Encrypt(key, nil), nil
 }
 
-// VerifyToken verifies the paseto token
+// VerifyToken verifies the paseto token and its issuer and audience
 func (pt *PasetoToken) VerifyToken(token string) (*domain.TokenPayload, error) {
 	var payload *domain.TokenPayload
 
-	parsedToken, err := pt.parser.ParseV4Local(*pt.key, token, nil)
+	parsedToken, err := pt.parse(token)
 	if err != nil {
 		if err.Error() == "this token has expired" {
 			return nil, domain.ErrExpiredToken
@@ -82,6 +119,20 @@ func (pt *PasetoToken) VerifyToken(token string) (*domain.TokenPayload, error) {
 		return nil, domain.ErrInvalidToken
 	}
 
+	if pt.issuer != "" {
+		issuer, err := parsedToken.GetIssuer()
+		if err != nil || issuer != pt.issuer {
+			return nil, domain.ErrInvalidTokenIssuer
+		}
+	}
+
+	if pt.audience != "" {
+		audience, err := parsedToken.GetAudience()
+		if err != nil || audience != pt.audience {
+			return nil, domain.ErrInvalidTokenAudience
+		}
+	}
+
 	err = parsedToken.Get("payload", &payload)
 	if err != nil {
 		return nil, domain.ErrInvalidToken
@@ -89,3 +140,67 @@ func (pt *PasetoToken) VerifyToken(token string) (*domain.TokenPayload, error) {
 
 	return payload, nil
 }
+
+// PublicKeys returns the public keys used to verify v4.public tokens,
+// it is empty when the tokens are symmetric encrypted
+func (pt *PasetoToken) PublicKeys() []domain.PublicKey {
+	return pt.keys.publicKeys()
+}
+
+// parse looks up the key identified in the token footer and decrypts or verifies the token with it
+func (pt *PasetoToken) parse(token string) (*paseto.Token, error) {
+	if pt.keys.purpose == purposePublic {
+		keyID, err := pt.keyID(paseto.V4Public, token)
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := pt.keys.getPublic(keyID)
+		if !ok {
+			return nil, domain.ErrInvalidToken
+		}
+
+		return pt.parser.ParseV4Public(key, token, nil)
+	}
+
+	keyID, err := pt.keyID(paseto.V4Local, token)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := pt.keys.getLocal(keyID)
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return pt.parser.ParseV4Local(key, token, nil)
+}
+
+// keyID reads the key ID from the unverified token footer
+func (pt *PasetoToken) keyID(protocol paseto.Protocol, token string) (string, error) {
+	var tokenFooter footer
+
+	rawFooter, err := pt.parser.UnsafeParseFooter(protocol, token)
+	if err != nil {
+		return "", domain.ErrInvalidToken
+	}
+
+	err = json.Unmarshal(rawFooter, &tokenFooter)
+	if err != nil {
+		return "", domain.ErrInvalidToken
+	}
+
+	return tokenFooter.KeyID, nil
+}
+
+// setFooter identifies the key used to create the token in its footer
+func setFooter(token *paseto.Token, keyID string) error {
+	tokenFooter, err := json.Marshal(footer{keyID})
+	if err != nil {
+		return err
+	}
+
+	token.SetFooter(tokenFooter)
+
+	return nil
+}
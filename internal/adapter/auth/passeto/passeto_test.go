@@ -0,0 +1,293 @@
+package paseto_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	paseto "github.com/cidmiranda/go-ws/internal/adapter/auth/passeto"
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldKey   = "707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f"
+	newKey   = "a0a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebf"
+	otherKey = "c0c1c2c3c4c5c6c7c8c9cacbcccdcecfd0d1d2d3d4d5d6d7d8d9dadbdcdddedf"
+)
+
+// newTokenConfig creates a token configuration with the given purpose, keys and active key
+func newTokenConfig(purpose, keys, activeKeyID string) *config.Token {
+	return &config.Token{
+		Duration:    "15m",
+		Purpose:     purpose,
+		Keys:        keys,
+		ActiveKeyID: activeKeyID,
+	}
+}
+
+// footerKeyID decodes the key ID from the footer of a token
+func footerKeyID(t *testing.T, token string) string {
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 4, "Token has no footer")
+
+	rawFooter, err := base64.RawURLEncoding.DecodeString(parts[3])
+	require.NoError(t, err)
+
+	var tokenFooter struct {
+		KeyID string `json:"kid"`
+	}
+	err = json.Unmarshal(rawFooter, &tokenFooter)
+	require.NoError(t, err)
+
+	return tokenFooter.KeyID
+}
+
+func TestPasetoToken_KeyRotation(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		issuer   func(purpose string) *config.Token
+		verifier func(purpose string) *config.Token
+		keyID    string
+		expected error
+	}{
+		{
+			desc: "Success_ActiveKey",
+			issuer: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+oldKey+",new:"+newKey, "new")
+			},
+			verifier: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+oldKey+",new:"+newKey, "new")
+			},
+			keyID:    "new",
+			expected: nil,
+		},
+		{
+			desc: "Success_DefaultActiveKey",
+			issuer: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+oldKey+",new:"+newKey, "")
+			},
+			verifier: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+oldKey+",new:"+newKey, "")
+			},
+			keyID:    "old",
+			expected: nil,
+		},
+		{
+			desc: "Success_RetiredKey",
+			issuer: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+oldKey, "")
+			},
+			verifier: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "new:"+newKey+",old:"+oldKey, "new")
+			},
+			keyID:    "old",
+			expected: nil,
+		},
+		{
+			desc: "Fail_RemovedKey",
+			issuer: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+oldKey, "")
+			},
+			verifier: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "new:"+newKey, "")
+			},
+			keyID:    "old",
+			expected: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_DifferentKeyWithSameID",
+			issuer: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+oldKey, "")
+			},
+			verifier: func(purpose string) *config.Token {
+				return newTokenConfig(purpose, "old:"+otherKey, "")
+			},
+			keyID:    "old",
+			expected: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, purpose := range []string{"local", "public"} {
+		for _, tc := range testCases {
+			purpose, tc := purpose, tc
+			t.Run(purpose+"/"+tc.desc, func(t *testing.T) {
+				t.Parallel()
+
+				issuer, err := paseto.New(tc.issuer(purpose))
+				require.NoError(t, err)
+				verifier, err := paseto.New(tc.verifier(purpose))
+				require.NoError(t, err)
+
+				payload := &domain.TokenPayload{
+					UserID: 1,
+					Role:   domain.Cashier,
+				}
+
+				token, err := issuer.CreateToken(payload)
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(token, "v4."+purpose+"."), "Token purpose mismatch")
+				assert.Equal(t, tc.keyID, footerKeyID(t, token), "Key ID mismatch")
+
+				output, err := verifier.VerifyToken(token)
+				assert.Equal(t, tc.expected, err, "Error mismatch")
+				if tc.expected == nil {
+					assert.Equal(t, payload.ID, output.ID, "Token ID mismatch")
+					assert.Equal(t, payload.UserID, output.UserID, "User mismatch")
+				}
+			})
+		}
+	}
+}
+
+func TestPasetoToken_VerifyToken(t *testing.T) {
+	tokenConfig := newTokenConfig("local", "old:"+oldKey, "")
+	tokenConfig.Issuer = "go-ws"
+	tokenConfig.Audience = "go-ws-api"
+
+	pt, err := paseto.New(tokenConfig)
+	require.NoError(t, err)
+
+	token, err := pt.CreateToken(&domain.TokenPayload{UserID: 1})
+	require.NoError(t, err)
+
+	// withFooter replaces the footer of the token
+	withFooter := func(footer string) string {
+		parts := strings.Split(token, ".")
+		parts[3] = base64.RawURLEncoding.EncodeToString([]byte(footer))
+		return strings.Join(parts, ".")
+	}
+
+	otherIssuerConfig := newTokenConfig("local", "old:"+oldKey, "")
+	otherIssuerConfig.Issuer = "other"
+	otherIssuerConfig.Audience = tokenConfig.Audience
+
+	otherAudienceConfig := newTokenConfig("local", "old:"+oldKey, "")
+	otherAudienceConfig.Issuer = tokenConfig.Issuer
+	otherAudienceConfig.Audience = "other"
+
+	testCases := []struct {
+		desc     string
+		config   *config.Token
+		token    string
+		expected error
+	}{
+		{
+			desc:     "Success",
+			config:   tokenConfig,
+			token:    token,
+			expected: nil,
+		},
+		{
+			desc:     "Fail_UnknownKeyID",
+			config:   tokenConfig,
+			token:    withFooter(`{"kid":"unknown"}`),
+			expected: domain.ErrInvalidToken,
+		},
+		{
+			desc:     "Fail_MalformedFooter",
+			config:   tokenConfig,
+			token:    withFooter("not json"),
+			expected: domain.ErrInvalidToken,
+		},
+		{
+			desc:     "Fail_Issuer",
+			config:   otherIssuerConfig,
+			token:    token,
+			expected: domain.ErrInvalidTokenIssuer,
+		},
+		{
+			desc:     "Fail_Audience",
+			config:   otherAudienceConfig,
+			token:    token,
+			expected: domain.ErrInvalidTokenAudience,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			verifier, err := paseto.New(tc.config)
+			require.NoError(t, err)
+
+			_, err = verifier.VerifyToken(tc.token)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
+
+func TestPasetoToken_PublicKeys(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *config.Token
+		ids    []string
+	}{
+		{
+			desc:   "Public",
+			config: newTokenConfig("public", "new:"+newKey+",old:"+oldKey, "new"),
+			ids:    []string{"new", "old"},
+		},
+		{
+			desc:   "Local",
+			config: newTokenConfig("local", "new:"+newKey, ""),
+			ids:    []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pt, err := paseto.New(tc.config)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, key := range pt.PublicKeys() {
+				assert.Equal(t, "v4.public", key.Algorithm, "Algorithm mismatch")
+				assert.Len(t, key.Key, 32, "Public key size mismatch")
+				ids = append(ids, key.ID)
+			}
+			assert.Equal(t, tc.ids, ids, "Key IDs mismatch")
+		})
+	}
+}
+
+func TestNew_InvalidKeys(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *config.Token
+	}{
+		{
+			desc:   "UnknownActiveKey",
+			config: newTokenConfig("local", "old:"+oldKey, "new"),
+		},
+		{
+			desc:   "DuplicatedKey",
+			config: newTokenConfig("local", "old:"+oldKey+",old:"+newKey, ""),
+		},
+		{
+			desc:   "MalformedKey",
+			config: newTokenConfig("local", "old:not-hex", ""),
+		},
+		{
+			desc:   "UnsupportedPurpose",
+			config: newTokenConfig("secret", "old:"+oldKey, ""),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := paseto.New(tc.config)
+			assert.ErrorIs(t, err, domain.ErrTokenKey, "Error mismatch")
+		})
+	}
+}
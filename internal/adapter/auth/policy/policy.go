@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+// policyFile represents the JSON document of an authorization policy
+type policyFile struct {
+	Version string     `json:"version"`
+	Rules   []ruleFile `json:"rules"`
+}
+
+// ruleFile represents a rule of an authorization policy document
+type ruleFile struct {
+	ID          string          `json:"id"`
+	Description string          `json:"description"`
+	Effect      string          `json:"effect"`
+	Subjects    []string        `json:"subjects"`
+	Actions     []string        `json:"actions"`
+	Resources   []string        `json:"resources"`
+	Conditions  []conditionFile `json:"conditions"`
+}
+
+// conditionFile represents a condition of a rule in an authorization policy document
+type conditionFile struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values"`
+}
+
+/**
+ * PolicyFile implements port.PolicyRepository interface
+ * and reads the authorization policy from a JSON file,
+ * the file is read again on every call so that policies can be reloaded
+ */
+type PolicyFile struct {
+	path string
+}
+
+// New creates a new policy file instance
+func New(config *config.Authz) *PolicyFile {
+	return &PolicyFile{
+		config.PolicyFile,
+	}
+}
+
+// GetPolicy reads and decodes the policy file
+func (pf *PolicyFile) GetPolicy(ctx context.Context) (*domain.Policy, error) {
+	data, err := os.ReadFile(pf.path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPolicy, err)
+	}
+
+	var file policyFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPolicy, err)
+	}
+
+	policy := &domain.Policy{
+		Version: file.Version,
+	}
+
+	for _, rule := range file.Rules {
+		policyRule := domain.PolicyRule{
+			ID:          rule.ID,
+			Description: rule.Description,
+			Effect:      domain.PolicyEffect(rule.Effect),
+			Subjects:    rule.Subjects,
+			Actions:     rule.Actions,
+			Resources:   rule.Resources,
+		}
+
+		for _, condition := range rule.Conditions {
+			policyRule.Conditions = append(policyRule.Conditions, domain.PolicyCondition{
+				Attribute: condition.Attribute,
+				Operator:  domain.ConditionOperator(condition.Operator),
+				Values:    condition.Values,
+			})
+		}
+
+		policy.Rules = append(policy.Rules, policyRule)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,81 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+)
+
+// keySize is the size in bytes of an AES-256 key
+const keySize = 32
+
+/**
+ * AESGCM implements port.SecretCipher interface
+ * and encrypts secrets at rest with AES-256-GCM,
+ * the random nonce is prepended to the ciphertext
+ */
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// New creates a new AES-GCM cipher from the hex encoded encryption key
+func New(config *config.TwoFactor) (*AESGCM, error) {
+	key, err := hex.DecodeString(config.EncryptionKey)
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("encryption key must be %d hex encoded bytes", keySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESGCM{
+		aead,
+	}, nil
+}
+
+// Encrypt encrypts the plaintext and returns it base64 encoded
+func (c *AESGCM) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a base64 encoded ciphertext created by Encrypt
+func (c *AESGCM) Decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,44 @@
+package totp
+
+import (
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/pquerna/otp/totp"
+)
+
+/**
+ * TOTP implements port.TOTPGenerator interface
+ * and provides an access to the otp library,
+ * codes follow RFC 6238 with 6 digits, a 30 second period and one period of clock skew
+ */
+type TOTP struct {
+	issuer string
+}
+
+// New creates a new TOTP instance
+func New(config *config.TwoFactor) *TOTP {
+	return &TOTP{
+		config.Issuer,
+	}
+}
+
+// Generate generates a new secret for the account and its otpauth URI for authenticator apps
+func (t *TOTP) Generate(accountName string) (*domain.TOTPKey, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      t.issuer,
+		AccountName: accountName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPKey{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+	}, nil
+}
+
+// Validate checks the code against the secret at the current time
+func (t *TOTP) Validate(code, secret string) bool {
+	return totp.Validate(code, secret)
+}
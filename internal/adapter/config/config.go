@@ -6,14 +6,21 @@ import (
 	"github.com/joho/godotenv"
 )
 
-// Container contains environment variables for the application, database, cache, token, and http server
+// Container contains environment variables for the application, database, cache, token, authorization, login lockout, two-factor authentication, email, password reset, password policy and hashing, single sign-on, and http server
 type (
 	Container struct {
-		App   *App
-		Token *Token
-		Redis *Redis
-		DB    *DB
-		HTTP  *HTTP
+		App       *App
+		Token     *Token
+		Authz     *Authz
+		Lockout   *Lockout
+		TwoFactor *TwoFactor
+		Mail      *Mail
+		Reset     *PasswordReset
+		Password  *Password
+		OIDC      *OIDC
+		Redis     *Redis
+		DB        *DB
+		HTTP      *HTTP
 	}
 	// App contains all the environment variables for the application
 	App struct {
@@ -22,9 +29,82 @@ type (
 	}
 	// Token contains all the environment variables for the token service
 	Token struct {
+		Type            string
+		Algorithm       string
+		Duration        string
+		RefreshDuration string
+		Issuer          string
+		Audience        string
+		Purpose         string
+		Keys            string
+		KeysFile        string
+		ActiveKeyID     string
+	}
+	// Authz contains all the environment variables for the authorization policy
+	Authz struct {
+		PolicyFile string
+	}
+	// Lockout contains all the environment variables for the login lockout
+	Lockout struct {
+		MaxAttempts      string
+		MaxAttemptsPerIP string
+		AttemptWindow    string
+		Duration         string
+		MaxDuration      string
+	}
+	// TwoFactor contains all the environment variables for the two-factor authentication
+	TwoFactor struct {
+		Issuer        string
+		EncryptionKey string
+	}
+	// Mail contains all the environment variables for sending emails and verifying email addresses
+	Mail struct {
+		Driver          string
+		Host            string
+		Port            string
+		Username        string
+		Password        string
+		From            string
+		OutboxDir       string
+		VerificationKey string
+		VerificationURL string
+		VerificationTTL string
+		RequireVerified string
+	}
+
+	// PasswordReset contains all the environment variables for the password reset
+	PasswordReset struct {
+		URL      string
 		Duration string
 	}
 
+	// Password contains all the environment variables for the password policy and password hashing
+	Password struct {
+		MinLength         string
+		MaxLength         string
+		RequireUpper      string
+		RequireLower      string
+		RequireDigit      string
+		RequireSymbol     string
+		DenyPersonalInfo  string
+		BreachedFile      string
+		HashAlgorithm     string
+		BcryptCost        string
+		Argon2Memory      string
+		Argon2Iterations  string
+		Argon2Parallelism string
+	}
+
+	// OIDC contains all the environment variables for the single sign-on with an OpenID Connect provider
+	OIDC struct {
+		Provider     string
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       string
+	}
+
 	// Redis contains all the environment variables for the cache service
 	Redis struct {
 		Addr     string
@@ -42,10 +122,22 @@ type (
 	}
 	// HTTP contains all the environment variables for the http server
 	HTTP struct {
-		Env            string
-		URL            string
-		Port           string
-		AllowedOrigins string
+		Env                    string
+		URL                    string
+		Port                   string
+		AllowedOrigins         string
+		TrustedProxies         string
+		RateLimitPublic        string
+		RateLimitAuthenticated string
+		RateLimitClient        string
+		TLSCertFile            string
+		TLSKeyFile             string
+		ClientCAFile           string
+		SessionCookie          string
+		CookieDomain           string
+		CookieSameSite         string
+		CSRFMode               string
+		CSRFKey                string
 	}
 )
 
@@ -75,7 +167,77 @@ func New() (*Container, error) {
 	}
 
 	token := &Token{
-		Duration: os.Getenv("TOKEN_DURATION"),
+		Type:            os.Getenv("TOKEN_TYPE"),
+		Algorithm:       os.Getenv("TOKEN_ALGORITHM"),
+		Duration:        os.Getenv("TOKEN_DURATION"),
+		RefreshDuration: os.Getenv("REFRESH_TOKEN_DURATION"),
+		Issuer:          os.Getenv("TOKEN_ISSUER"),
+		Audience:        os.Getenv("TOKEN_AUDIENCE"),
+		Purpose:         os.Getenv("TOKEN_PURPOSE"),
+		Keys:            os.Getenv("TOKEN_KEYS"),
+		KeysFile:        os.Getenv("TOKEN_KEYS_FILE"),
+		ActiveKeyID:     os.Getenv("TOKEN_ACTIVE_KEY_ID"),
+	}
+
+	authz := &Authz{
+		PolicyFile: os.Getenv("AUTHZ_POLICY_FILE"),
+	}
+
+	lockout := &Lockout{
+		MaxAttempts:      os.Getenv("LOCKOUT_MAX_ATTEMPTS"),
+		MaxAttemptsPerIP: os.Getenv("LOCKOUT_MAX_ATTEMPTS_PER_IP"),
+		AttemptWindow:    os.Getenv("LOCKOUT_ATTEMPT_WINDOW"),
+		Duration:         os.Getenv("LOCKOUT_DURATION"),
+		MaxDuration:      os.Getenv("LOCKOUT_MAX_DURATION"),
+	}
+
+	twoFactor := &TwoFactor{
+		Issuer:        os.Getenv("TWO_FACTOR_ISSUER"),
+		EncryptionKey: os.Getenv("TWO_FACTOR_ENCRYPTION_KEY"),
+	}
+
+	mail := &Mail{
+		Driver:          os.Getenv("MAIL_DRIVER"),
+		Host:            os.Getenv("MAIL_HOST"),
+		Port:            os.Getenv("MAIL_PORT"),
+		Username:        os.Getenv("MAIL_USERNAME"),
+		Password:        os.Getenv("MAIL_PASSWORD"),
+		From:            os.Getenv("MAIL_FROM"),
+		OutboxDir:       os.Getenv("MAIL_OUTBOX_DIR"),
+		VerificationKey: os.Getenv("EMAIL_VERIFICATION_KEY"),
+		VerificationURL: os.Getenv("EMAIL_VERIFICATION_URL"),
+		VerificationTTL: os.Getenv("EMAIL_VERIFICATION_DURATION"),
+		RequireVerified: os.Getenv("EMAIL_VERIFICATION_REQUIRED"),
+	}
+
+	reset := &PasswordReset{
+		URL:      os.Getenv("PASSWORD_RESET_URL"),
+		Duration: os.Getenv("PASSWORD_RESET_DURATION"),
+	}
+
+	password := &Password{
+		MinLength:         os.Getenv("PASSWORD_MIN_LENGTH"),
+		MaxLength:         os.Getenv("PASSWORD_MAX_LENGTH"),
+		RequireUpper:      os.Getenv("PASSWORD_REQUIRE_UPPER"),
+		RequireLower:      os.Getenv("PASSWORD_REQUIRE_LOWER"),
+		RequireDigit:      os.Getenv("PASSWORD_REQUIRE_DIGIT"),
+		RequireSymbol:     os.Getenv("PASSWORD_REQUIRE_SYMBOL"),
+		DenyPersonalInfo:  os.Getenv("PASSWORD_DENY_PERSONAL_INFO"),
+		BreachedFile:      os.Getenv("PASSWORD_BREACHED_FILE"),
+		HashAlgorithm:     os.Getenv("PASSWORD_HASH_ALGORITHM"),
+		BcryptCost:        os.Getenv("PASSWORD_BCRYPT_COST"),
+		Argon2Memory:      os.Getenv("PASSWORD_ARGON2_MEMORY"),
+		Argon2Iterations:  os.Getenv("PASSWORD_ARGON2_ITERATIONS"),
+		Argon2Parallelism: os.Getenv("PASSWORD_ARGON2_PARALLELISM"),
+	}
+
+	oidc := &OIDC{
+		Provider:     os.Getenv("OIDC_PROVIDER"),
+		Issuer:       os.Getenv("OIDC_ISSUER"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       os.Getenv("OIDC_SCOPES"),
 	}
 
 	redis := &Redis{
@@ -93,15 +255,34 @@ func New() (*Container, error) {
 	}
 
 	http := &HTTP{
-		Env:            os.Getenv("APP_ENV"),
-		URL:            os.Getenv("HTTP_URL"),
-		Port:           os.Getenv("HTTP_PORT"),
-		AllowedOrigins: os.Getenv("HTTP_ALLOWED_ORIGINS"),
+		Env:                    os.Getenv("APP_ENV"),
+		URL:                    os.Getenv("HTTP_URL"),
+		Port:                   os.Getenv("HTTP_PORT"),
+		AllowedOrigins:         os.Getenv("HTTP_ALLOWED_ORIGINS"),
+		TrustedProxies:         os.Getenv("HTTP_TRUSTED_PROXIES"),
+		RateLimitPublic:        os.Getenv("HTTP_RATE_LIMIT_PUBLIC"),
+		RateLimitAuthenticated: os.Getenv("HTTP_RATE_LIMIT_AUTHENTICATED"),
+		RateLimitClient:        os.Getenv("HTTP_RATE_LIMIT_CLIENT"),
+		TLSCertFile:            os.Getenv("HTTP_TLS_CERT_FILE"),
+		TLSKeyFile:             os.Getenv("HTTP_TLS_KEY_FILE"),
+		ClientCAFile:           os.Getenv("HTTP_CLIENT_CA_FILE"),
+		SessionCookie:          os.Getenv("HTTP_SESSION_COOKIE"),
+		CookieDomain:           os.Getenv("HTTP_COOKIE_DOMAIN"),
+		CookieSameSite:         os.Getenv("HTTP_COOKIE_SAME_SITE"),
+		CSRFMode:               os.Getenv("HTTP_CSRF_MODE"),
+		CSRFKey:                os.Getenv("HTTP_CSRF_KEY"),
 	}
 
 	return &Container{
 		app,
 		token,
+		authz,
+		lockout,
+		twoFactor,
+		mail,
+		reset,
+		password,
+		oidc,
 		redis,
 		db,
 		http,
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler represents the HTTP handler for api key requests
+type APIKeyHandler struct {
+	svc port.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler instance
+func NewAPIKeyHandler(svc port.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		svc,
+	}
+}
+
+// createAPIKeyRequest represents the request body for creating an api key
+type createAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100" example:"Nightly export"`
+	Scopes    []string   `json:"scopes" binding:"omitempty,dive,required" example:"users:update"`
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitempty,gt" example:"2030-01-01T00:00:00Z"`
+}
+
+// CreateAPIKey godoc
+//
+//	@Summary		Create an api key
+//	@Description	Creates an api key for machine clients acting as the authenticated user. The key is only shown once and is sent in the Authorization header as "ApiKey <key>". Scopes, such as users:*, restrict the actions the key may perform. Api keys cannot create other api keys.
+//	@Tags			API keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createAPIKeyRequest		true	"Create api key request body"
+//	@Success		200		{object}	createdAPIKeyResponse	"Api key created"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		403		{object}	errorResponse			"Forbidden error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/api-keys [post]
+//	@Security		BearerAuth
+func (ah *APIKeyHandler) CreateAPIKey(ctx *gin.Context) {
+	var req createAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	key := &domain.APIKey{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	key, plaintext, err := ah.svc.CreateAPIKey(ctx, payload, key)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCreatedAPIKeyResponse(key, plaintext)
+
+	handleSuccess(ctx, rsp)
+}
+
+// ListAPIKeys godoc
+//
+//	@Summary		List api keys
+//	@Description	Lists the api keys of the authenticated user, including expired and revoked ones. The keys themselves are never shown again.
+//	@Tags			API keys
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	[]apiKeyResponse	"Api keys displayed"
+//	@Failure		401	{object}	errorResponse		"Unauthorized error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/v1/api-keys [get]
+//	@Security		BearerAuth
+func (ah *APIKeyHandler) ListAPIKeys(ctx *gin.Context) {
+	payload := getPrincipal(ctx)
+
+	keys, err := ah.svc.ListAPIKeys(ctx, payload.UserID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []apiKeyResponse{}
+	for _, key := range keys {
+		rsp = append(rsp, newAPIKeyResponse(&key))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// revokeAPIKeyRequest represents the request body for revoking an api key
+type revokeAPIKeyRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// RevokeAPIKey godoc
+//
+//	@Summary		Revoke an api key
+//	@Description	Revokes an api key of the authenticated user, it stops working immediately
+//	@Tags			API keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Api key ID"
+//	@Success		200	{object}	response		"Api key revoked"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/api-keys/{id} [delete]
+//	@Security		BearerAuth
+func (ah *APIKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	var req revokeAPIKeyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	err := ah.svc.RevokeAPIKey(ctx, payload.UserID, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
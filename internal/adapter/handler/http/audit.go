@@ -0,0 +1,85 @@
+package http
+
+import (
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler represents the HTTP handler for audit log requests
+type AuditHandler struct {
+	svc port.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler instance
+func NewAuditHandler(svc port.AuditService) *AuditHandler {
+	return &AuditHandler{
+		svc,
+	}
+}
+
+// listAuthEventsRequest represents the request body for listing security events,
+// from and to are RFC 3339 timestamps
+type listAuthEventsRequest struct {
+	UserID uint64    `form:"user_id" binding:"omitempty,min=1" example:"1"`
+	Type   string    `form:"type" binding:"omitempty,oneof=login password_change user_update user_delete" example:"login"`
+	From   time.Time `form:"from" example:"1970-01-01T00:00:00Z"`
+	To     time.Time `form:"to" binding:"omitempty,gtfield=From" example:"1970-01-02T00:00:00Z"`
+	Skip   uint64    `form:"skip" binding:"required,min=0" example:"0"`
+	Limit  uint64    `form:"limit" binding:"required,min=5" example:"5"`
+}
+
+// ListAuthEvents godoc
+//
+//	@Summary		List security events
+//	@Description	List the logins, failed logins, password changes, user updates and deletions recorded in the audit log, newest first. Events match a user if the user is their actor or their target, only admins are allowed
+//	@Tags			Audit
+//	@Accept			json
+//	@Produce		json
+//	@Param			user_id	query		uint64			false	"User ID"
+//	@Param			type	query		string			false	"Event type"	Enums(login, password_change, user_update, user_delete)
+//	@Param			from	query		string			false	"Start of the time range, inclusive (RFC 3339)"
+//	@Param			to		query		string			false	"End of the time range, exclusive (RFC 3339)"
+//	@Param			skip	query		uint64			true	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Security events displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/auth-events [get]
+//	@Security		BearerAuth
+func (ah *AuditHandler) ListAuthEvents(ctx *gin.Context) {
+	var req listAuthEventsRequest
+	var eventsList []authEventResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	events, err := ah.svc.ListEvents(ctx, &domain.AuthEventFilter{
+		UserID: req.UserID,
+		Type:   domain.AuthEventType(req.Type),
+		From:   req.From,
+		To:     req.To,
+		Skip:   req.Skip,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, event := range events {
+		eventsList = append(eventsList, newAuthEventResponse(&event))
+	}
+
+	total := uint64(len(eventsList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	rsp := toMap(meta, eventsList, "events")
+
+	handleSuccess(ctx, rsp)
+}
@@ -0,0 +1,175 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newAuditServer starts a test server whose bearer token authenticates the given principal,
+// the authorizer decides whether the principal may read the audit log
+func newAuditServer(
+	t *testing.T,
+	principal *domain.TokenPayload,
+	authorizer *mock.MockAuthorizer,
+	auditService *mock.MockAuditService,
+) *httptest.Server {
+	ctrl := gomock.NewController(t)
+
+	authService := mock.NewMockAuthService(ctrl)
+	authService.EXPECT().
+		VerifyToken(gomock.Any(), gomock.Eq(userToken)).
+		Return(principal, nil).
+		AnyTimes()
+
+	router, err := handler.NewRouter(
+		&config.HTTP{
+			Env:            "test",
+			AllowedOrigins: "http://localhost:5173",
+		},
+		handler.NewAuthenticatorChain(handler.NewBearerAuthenticator(authService)),
+		authorizer,
+		nil,
+		nil,
+		handler.UserHandler{},
+		handler.AuthHandler{},
+		handler.KeyHandler{},
+		handler.PolicyHandler{},
+		handler.TwoFactorHandler{},
+		handler.PasswordResetHandler{},
+		handler.APIKeyHandler{},
+		handler.OAuthHandler{},
+		handler.OIDCHandler{},
+		handler.SessionHandler{},
+		handler.ImpersonationHandler{},
+		*handler.NewAuditHandler(auditService),
+	)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestAuditHandler_ListAuthEvents(t *testing.T) {
+	admin := &domain.TokenPayload{
+		UserID:    1,
+		Role:      domain.Admin,
+		TwoFactor: true,
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	filter := &domain.AuthEventFilter{
+		UserID: 2,
+		Type:   domain.LoginEvent,
+		From:   from,
+		To:     to,
+		Skip:   1,
+		Limit:  10,
+	}
+	events := []domain.AuthEvent{
+		{
+			ID:        1,
+			Type:      domain.LoginEvent,
+			TargetID:  2,
+			Email:     "test@example.com",
+			IP:        "203.0.113.7",
+			UserAgent: "Mozilla/5.0",
+			Outcome:   domain.FailureOutcome,
+			Reason:    domain.ErrInvalidCredentials.Error(),
+			CreatedAt: from.Add(time.Hour),
+		},
+	}
+	query := "?user_id=2&type=login&from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z&skip=1&limit=10"
+
+	testCases := []struct {
+		desc   string
+		query  string
+		mocks  func(authorizer *mock.MockAuthorizer, auditService *mock.MockAuditService)
+		status int
+		events int
+	}{
+		{
+			desc:  "Success",
+			query: query,
+			mocks: func(authorizer *mock.MockAuthorizer, auditService *mock.MockAuditService) {
+				authorizer.EXPECT().
+					Can(gomock.Any(), gomock.Eq(admin), gomock.Eq("auth_events:read"), gomock.Any()).
+					Return(&domain.PolicyDecision{Allowed: true}, nil)
+				auditService.EXPECT().
+					ListEvents(gomock.Any(), gomock.Eq(filter)).
+					Return(events, nil)
+			},
+			status: http.StatusOK,
+			events: len(events),
+		},
+		{
+			desc:  "Fail_Forbidden",
+			query: query,
+			mocks: func(authorizer *mock.MockAuthorizer, auditService *mock.MockAuditService) {
+				authorizer.EXPECT().
+					Can(gomock.Any(), gomock.Eq(admin), gomock.Eq("auth_events:read"), gomock.Any()).
+					Return(&domain.PolicyDecision{Allowed: false}, nil)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			desc:  "Fail_InvalidType",
+			query: "?type=logout&skip=1&limit=10",
+			mocks: func(authorizer *mock.MockAuthorizer, auditService *mock.MockAuditService) {
+				authorizer.EXPECT().
+					Can(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.PolicyDecision{Allowed: true}, nil)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:  "Fail_InvalidTimeRange",
+			query: "?from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z&skip=1&limit=10",
+			mocks: func(authorizer *mock.MockAuthorizer, auditService *mock.MockAuditService) {
+				authorizer.EXPECT().
+					Can(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.PolicyDecision{Allowed: true}, nil)
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authorizer := mock.NewMockAuthorizer(ctrl)
+			auditService := mock.NewMockAuditService(ctrl)
+			tc.mocks(authorizer, auditService)
+
+			server := newAuditServer(t, admin, authorizer, auditService)
+
+			status, data := doUserRequest(t, http.MethodGet, server.URL+"/v1/auth-events/"+tc.query, "")
+			assert.Equal(t, tc.status, status, "Status mismatch")
+
+			if tc.status == http.StatusOK {
+				list, ok := data["events"].([]any)
+				require.True(t, ok, "Events missing")
+				assert.Len(t, list, tc.events, "Events mismatch")
+
+				event := list[0].(map[string]any)
+				assert.Equal(t, "failure", event["outcome"], "Outcome mismatch")
+				assert.NotContains(t, event, "actor_id", "Unknown actor displayed")
+			}
+		})
+	}
+}
@@ -1,19 +1,25 @@
 package http
 
 import (
+	"errors"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/cidmiranda/go-ws/internal/core/port"
 	"github.com/gin-gonic/gin"
 )
 
-// AuthHandler represents the HTTP handler for authentication-related requests
+// AuthHandler represents the HTTP handler for authentication-related requests,
+// the tokens are also sent as session cookies unless cookies is nil
 type AuthHandler struct {
-	svc port.AuthService
+	svc     port.AuthService
+	cookies *SessionCookies
 }
 
 // NewAuthHandler creates a new AuthHandler instance
-func NewAuthHandler(svc port.AuthService) *AuthHandler {
+func NewAuthHandler(svc port.AuthService, cookies *SessionCookies) *AuthHandler {
 	return &AuthHandler{
 		svc,
+		cookies,
 	}
 }
 
@@ -26,7 +32,7 @@ type loginRequest struct {
 // Login godoc
 //
 //	@Summary		Login and get an access token
-//	@Description	Logs in a registered user and returns an access token if the credentials are valid.
+//	@Description	Logs in a registered user and returns an access token and a refresh token if the credentials are valid. Users with two-factor authentication enabled get a challenge token instead, to be exchanged at /users/login/2fa. When session cookies are enabled, the tokens are only set as HttpOnly cookies and the response has the user and the csrf token that state-changing requests authenticated with the cookies must send in the X-CSRF-Token header.
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -34,8 +40,10 @@ type loginRequest struct {
 //	@Success		200		{object}	authResponse	"Succesfully logged in"
 //	@Failure		400		{object}	errorResponse	"Validation error"
 //	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Email not verified error"
+//	@Failure		429		{object}	errorResponse	"Too many failed attempts, see the Retry-After header"
 //	@Failure		500		{object}	errorResponse	"Internal server error"
-//	@Router			/users/login [post]
+//	@Router			/v1/users/login [post]
 func (ah *AuthHandler) Login(ctx *gin.Context) {
 	var req loginRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -44,12 +52,215 @@ func (ah *AuthHandler) Login(ctx *gin.Context) {
 	}
 
 	token, err := ah.svc.Login(ctx, req.Email, req.Password)
+	if err != nil {
+		var lockedErr *domain.AccountLockedError
+		if errors.As(err, &lockedErr) {
+			setRetryAfter(ctx, lockedErr.RetryAfter)
+		}
+
+		handleError(ctx, err)
+		return
+	}
+
+	respondWithToken(ctx, ah.cookies, token)
+}
+
+// loginTwoFactorRequest represents the request body for completing a login with a two-factor code
+type loginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required" example:"Jm2dT0X8qK6c1hQvZr4yW9bN3sLp7eUa5fGiYoXkR2c"`
+	Code           string `json:"code" binding:"required" example:"123456"`
+}
+
+// LoginTwoFactor godoc
+//
+//	@Summary		Complete a login with a two-factor code
+//	@Description	Exchanges the challenge token returned by the login and a TOTP code or an unused recovery code for an access token and a refresh token. The challenge token expires after 5 minutes.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		loginTwoFactorRequest	true	"Two-factor login request body"
+//	@Success		200		{object}	authResponse			"Succesfully logged in"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		429		{object}	errorResponse			"Too many failed attempts, see the Retry-After header"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/login/2fa [post]
+func (ah *AuthHandler) LoginTwoFactor(ctx *gin.Context) {
+	var req loginTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token, err := ah.svc.LoginTwoFactor(ctx, req.ChallengeToken, req.Code)
+	if err != nil {
+		var lockedErr *domain.AccountLockedError
+		if errors.As(err, &lockedErr) {
+			setRetryAfter(ctx, lockedErr.RetryAfter)
+		}
+
+		handleError(ctx, err)
+		return
+	}
+
+	respondWithToken(ctx, ah.cookies, token)
+}
+
+// refreshRequest represents the request body for refreshing an access token
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"h3bRLy6uUeoRC4A8vXW2C8yd6oFJ9XqRVx5mG4hZ6Tk"`
+}
+
+// Refresh godoc
+//
+//	@Summary		Refresh an access token
+//	@Description	Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can only be used once; reusing one revokes the whole session. Without a request body, the refresh token is read from the session cookie and the csrf token must be sent in the X-CSRF-Token header.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		refreshRequest	true	"Refresh request body"
+//	@Success		200		{object}	authResponse	"Succesfully refreshed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Invalid csrf token"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/refresh [post]
+func (ah *AuthHandler) Refresh(ctx *gin.Context) {
+	var req refreshRequest
+	if refreshToken, ok := ah.cookies.refreshToken(ctx); ok && ctx.Request.ContentLength == 0 {
+		// the browser sends the cookie with cross-site requests too
+		if err := ah.cookies.checkRefreshCSRF(ctx, ah.svc, refreshToken); err != nil {
+			handleError(ctx, err)
+			return
+		}
+		req.RefreshToken = refreshToken
+	} else if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token, err := ah.svc.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	respondWithToken(ctx, ah.cookies, token)
+}
+
+// logoutRequest represents the request body for logging out a user
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"h3bRLy6uUeoRC4A8vXW2C8yd6oFJ9XqRVx5mG4hZ6Tk"`
+	All          bool   `json:"all" example:"false"`
+}
+
+// Logout godoc
+//
+//	@Summary		Logout and revoke tokens
+//	@Description	Revokes the current access token and, if provided, the session of the refresh token. Set "all" to revoke every token issued to the user, which is forbidden while impersonating and with an api key. The session cookies are cleared, and their refresh token is revoked when none is provided.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		logoutRequest	false	"Logout request body"
+//	@Success		200		{object}	response		"Succesfully logged out"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Logging out everywhere while impersonating or with an api key"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/logout [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) Logout(ctx *gin.Context) {
+	var req logoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			validationError(ctx, err)
+			return
+		}
+	}
+
+	if req.RefreshToken == "" {
+		req.RefreshToken, _ = ah.cookies.refreshToken(ctx)
+	}
+
+	payload := getPrincipal(ctx)
+
+	// an impersonator may end the impersonation session, but not the sessions of the impersonated user
+	if req.All && payload.ImpersonatorID != 0 {
+		handleError(ctx, domain.ErrImpersonationForbidden)
+		return
+	}
+
+	// an api key may end its own token, but not the sessions of its owner whatever its scopes
+	if req.All && payload.APIKeyID != 0 {
+		handleError(ctx, domain.ErrAPIKeyForbidden)
+		return
+	}
+
+	var err error
+	if req.All {
+		err = ah.svc.LogoutAll(ctx, payload.UserID)
+	} else {
+		err = ah.svc.Logout(ctx, payload, req.RefreshToken)
+	}
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ah.cookies.clear(ctx)
+
+	handleSuccess(ctx, nil)
+}
+
+// unlockRequest represents the request body for unlocking the logins of a user
+type unlockRequest struct {
+	Email string `json:"email" binding:"required,email" example:"test@example.com"`
+}
+
+// Unlock godoc
+//
+//	@Summary		Unlock a user's logins
+//	@Description	Lifts the lock placed on an email after too many failed login attempts
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		unlockRequest	true	"Unlock request body"
+//	@Success		200		{object}	response		"Succesfully unlocked"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/unlock [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) Unlock(ctx *gin.Context) {
+	var req unlockRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := ah.svc.Unlock(ctx, req.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// respondWithToken sends the tokens of a login or refresh, or only the user and the csrf token when the tokens are kept in the session cookies
+func respondWithToken(ctx *gin.Context, cookies *SessionCookies, token *domain.AuthToken) {
+	csrfToken, err := cookies.set(ctx, token)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
-	rsp := newAuthResponse(token)
+	// scripts must not be able to read the tokens kept in the cookies
+	if csrfToken != "" {
+		handleSuccess(ctx, newSessionAuthResponse(token, csrfToken))
+		return
+	}
 
-	handleSuccess(ctx, rsp)
+	handleSuccess(ctx, newAuthResponse(token))
 }
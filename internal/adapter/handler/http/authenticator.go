@@ -0,0 +1,179 @@
+package http
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// authorizationHeaderKey is the key for authorization header in the request
+	authorizationHeaderKey = "authorization"
+	// authorizationType is the authorization type of access tokens
+	authorizationType = "bearer"
+	// apiKeyAuthorizationType is the authorization type of api keys
+	apiKeyAuthorizationType = "apikey"
+	// basicAuthorizationType is the authorization type of HTTP basic credentials
+	basicAuthorizationType = "basic"
+	// apiKeyHeaderKey is the header internal tools may send their api key in instead of the authorization header
+	apiKeyHeaderKey = "X-API-Key"
+)
+
+// errNoCredentials is returned by an authenticator when the request has no credentials for its scheme,
+// the chain then tries the next authenticator
+var errNoCredentials = errors.New("no credentials for the authentication scheme")
+
+// Authenticator authenticates a request with one authentication scheme.
+// It returns errNoCredentials when the request does not use the scheme,
+// and any other error when the request uses the scheme with invalid credentials
+type Authenticator interface {
+	Authenticate(ctx *gin.Context) (*domain.TokenPayload, error)
+}
+
+// AuthenticatorChain authenticates a request with the first authenticator whose scheme the request uses
+type AuthenticatorChain []Authenticator
+
+// NewAuthenticatorChain creates a new AuthenticatorChain instance, the authenticators are tried in the given order
+func NewAuthenticatorChain(authenticators ...Authenticator) AuthenticatorChain {
+	return AuthenticatorChain(authenticators)
+}
+
+// Authenticate returns the principal of the first authenticator that recognizes the request's credentials,
+// requests without credentials for any scheme fail with an error describing the authorization header
+func (ac AuthenticatorChain) Authenticate(ctx *gin.Context) (*domain.TokenPayload, error) {
+	for _, authenticator := range ac {
+		payload, err := authenticator.Authenticate(ctx)
+		if err == errNoCredentials {
+			continue
+		}
+
+		return payload, err
+	}
+
+	authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+	if len(authorizationHeader) == 0 {
+		return nil, domain.ErrEmptyAuthorizationHeader
+	}
+
+	if len(strings.Fields(authorizationHeader)) != 2 {
+		return nil, domain.ErrInvalidAuthorizationHeader
+	}
+
+	return nil, domain.ErrInvalidAuthorizationType
+}
+
+// authorizationCredentials returns the credentials of the authorization header if it uses the given authorization type
+func authorizationCredentials(ctx *gin.Context, authorizationType string) (string, bool) {
+	fields := strings.Fields(ctx.GetHeader(authorizationHeaderKey))
+	if len(fields) != 2 || strings.ToLower(fields[0]) != authorizationType {
+		return "", false
+	}
+
+	return fields[1], true
+}
+
+// BearerAuthenticator authenticates requests with an access token sent as "Bearer <token>"
+type BearerAuthenticator struct {
+	svc port.AuthService
+}
+
+// NewBearerAuthenticator creates a new BearerAuthenticator instance
+func NewBearerAuthenticator(svc port.AuthService) *BearerAuthenticator {
+	return &BearerAuthenticator{
+		svc,
+	}
+}
+
+// Authenticate verifies the access token of the request
+func (ba *BearerAuthenticator) Authenticate(ctx *gin.Context) (*domain.TokenPayload, error) {
+	token, ok := authorizationCredentials(ctx, authorizationType)
+	if !ok {
+		return nil, errNoCredentials
+	}
+
+	return ba.svc.VerifyToken(ctx, token)
+}
+
+// APIKeyAuthenticator authenticates requests with an api key sent as "ApiKey <key>" or in the X-API-Key header
+type APIKeyAuthenticator struct {
+	svc port.APIKeyService
+}
+
+// NewAPIKeyAuthenticator creates a new APIKeyAuthenticator instance
+func NewAPIKeyAuthenticator(svc port.APIKeyService) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		svc,
+	}
+}
+
+// Authenticate checks the api key of the request
+func (aa *APIKeyAuthenticator) Authenticate(ctx *gin.Context) (*domain.TokenPayload, error) {
+	key, ok := authorizationCredentials(ctx, apiKeyAuthorizationType)
+	if !ok {
+		key = ctx.GetHeader(apiKeyHeaderKey)
+	}
+	if key == "" {
+		return nil, errNoCredentials
+	}
+
+	return aa.svc.Authenticate(ctx, key)
+}
+
+// BasicAuthenticator authenticates internal tools that only support HTTP basic authentication,
+// the password is an api key and the username is ignored
+type BasicAuthenticator struct {
+	svc port.APIKeyService
+}
+
+// NewBasicAuthenticator creates a new BasicAuthenticator instance
+func NewBasicAuthenticator(svc port.APIKeyService) *BasicAuthenticator {
+	return &BasicAuthenticator{
+		svc,
+	}
+}
+
+// Authenticate checks the api key sent as the basic password of the request
+func (ba *BasicAuthenticator) Authenticate(ctx *gin.Context) (*domain.TokenPayload, error) {
+	if _, ok := authorizationCredentials(ctx, basicAuthorizationType); !ok {
+		return nil, errNoCredentials
+	}
+
+	_, password, ok := ctx.Request.BasicAuth()
+	if !ok || password == "" {
+		return nil, domain.ErrInvalidAuthorizationHeader
+	}
+
+	return ba.svc.Authenticate(ctx, password)
+}
+
+// CertificateAuthenticator authenticates requests with a client certificate verified during the TLS handshake,
+// the certificate is issued to the user's email, given as its first email address rather than the common name of its subject
+type CertificateAuthenticator struct {
+	svc port.AuthService
+}
+
+// NewCertificateAuthenticator creates a new CertificateAuthenticator instance
+func NewCertificateAuthenticator(svc port.AuthService) *CertificateAuthenticator {
+	return &CertificateAuthenticator{
+		svc,
+	}
+}
+
+// Authenticate returns the principal of the user the client certificate was issued to
+func (ca *CertificateAuthenticator) Authenticate(ctx *gin.Context) (*domain.TokenPayload, error) {
+	state := ctx.Request.TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, errNoCredentials
+	}
+
+	certificate := state.VerifiedChains[0][0]
+
+	if len(certificate.EmailAddresses) == 0 || certificate.EmailAddresses[0] == "" {
+		return nil, domain.ErrInvalidClientCertificate
+	}
+
+	return ca.svc.AuthenticateCertificate(ctx, certificate.EmailAddresses[0], certificate.NotBefore)
+}
@@ -0,0 +1,222 @@
+package http_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// newAuthenticatorContext creates the gin context of a request to authenticate
+func newAuthenticatorContext(req *http.Request) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = req
+
+	return ctx
+}
+
+func TestAuthenticatorChain_Authenticate(t *testing.T) {
+	apiKey := "gows_lookup_secret"
+	bearerPrincipal := &domain.TokenPayload{UserID: 1, Role: domain.Cashier}
+	apiKeyPrincipal := &domain.TokenPayload{UserID: 1, Role: domain.Cashier, APIKeyID: 7}
+	certificatePrincipal := &domain.TokenPayload{UserID: 2, Role: domain.Manager}
+	certificateIssuedAt := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// verifiedCertificate sets the client certificate the TLS handshake verified
+	verifiedCertificate := func(certificate *x509.Certificate) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{certificate}},
+			}
+		}
+	}
+
+	testCases := []struct {
+		desc  string
+		setup func(req *http.Request)
+		mocks func(
+			authService *mock.MockAuthService,
+			apiKeyService *mock.MockAPIKeyService,
+		)
+		output   *domain.TokenPayload
+		expected error
+	}{
+		{
+			desc: "Success_Bearer",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer access-token")
+			},
+			mocks: func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {
+				authService.EXPECT().
+					VerifyToken(gomock.Any(), gomock.Eq("access-token")).
+					Return(bearerPrincipal, nil)
+			},
+			output:   bearerPrincipal,
+			expected: nil,
+		},
+		{
+			desc: "Success_APIKey",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "ApiKey "+apiKey)
+			},
+			mocks: func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {
+				apiKeyService.EXPECT().
+					Authenticate(gomock.Any(), gomock.Eq(apiKey)).
+					Return(apiKeyPrincipal, nil)
+			},
+			output:   apiKeyPrincipal,
+			expected: nil,
+		},
+		{
+			desc: "Success_APIKeyHeader",
+			setup: func(req *http.Request) {
+				req.Header.Set("X-API-Key", apiKey)
+			},
+			mocks: func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {
+				apiKeyService.EXPECT().
+					Authenticate(gomock.Any(), gomock.Eq(apiKey)).
+					Return(apiKeyPrincipal, nil)
+			},
+			output:   apiKeyPrincipal,
+			expected: nil,
+		},
+		{
+			desc: "Success_Basic",
+			setup: func(req *http.Request) {
+				req.SetBasicAuth("reporting", apiKey)
+			},
+			mocks: func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {
+				apiKeyService.EXPECT().
+					Authenticate(gomock.Any(), gomock.Eq(apiKey)).
+					Return(apiKeyPrincipal, nil)
+			},
+			output:   apiKeyPrincipal,
+			expected: nil,
+		},
+		{
+			desc: "Success_CertificateEmail",
+			setup: verifiedCertificate(&x509.Certificate{
+				Subject:        pkix.Name{CommonName: "reporting"},
+				EmailAddresses: []string{"test@example.com"},
+				NotBefore:      certificateIssuedAt,
+			}),
+			mocks: func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {
+				authService.EXPECT().
+					AuthenticateCertificate(gomock.Any(), gomock.Eq("test@example.com"), gomock.Eq(certificateIssuedAt)).
+					Return(certificatePrincipal, nil)
+			},
+			output:   certificatePrincipal,
+			expected: nil,
+		},
+		{
+			desc: "Fail_CertificateCommonName",
+			setup: verifiedCertificate(&x509.Certificate{
+				Subject: pkix.Name{CommonName: "test@example.com"},
+			}),
+			mocks:    func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {},
+			output:   nil,
+			expected: domain.ErrInvalidClientCertificate,
+		},
+		{
+			desc: "Success_BearerBeforeCertificate",
+			setup: func(req *http.Request) {
+				verifiedCertificate(&x509.Certificate{
+					Subject: pkix.Name{CommonName: "test@example.com"},
+				})(req)
+				req.Header.Set("Authorization", "Bearer access-token")
+			},
+			mocks: func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {
+				authService.EXPECT().
+					VerifyToken(gomock.Any(), gomock.Eq("access-token")).
+					Return(bearerPrincipal, nil)
+			},
+			output:   bearerPrincipal,
+			expected: nil,
+		},
+		{
+			desc: "Fail_InvalidBearer",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer access-token")
+				req.Header.Set("X-API-Key", apiKey)
+			},
+			mocks: func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {
+				authService.EXPECT().
+					VerifyToken(gomock.Any(), gomock.Eq("access-token")).
+					Return(nil, domain.ErrExpiredToken)
+			},
+			output:   nil,
+			expected: domain.ErrExpiredToken,
+		},
+		{
+			desc: "Fail_BasicWithoutPassword",
+			setup: func(req *http.Request) {
+				req.SetBasicAuth("reporting", "")
+			},
+			mocks:    func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {},
+			output:   nil,
+			expected: domain.ErrInvalidAuthorizationHeader,
+		},
+		{
+			desc:     "Fail_NoCredentials",
+			setup:    func(req *http.Request) {},
+			mocks:    func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {},
+			output:   nil,
+			expected: domain.ErrEmptyAuthorizationHeader,
+		},
+		{
+			desc: "Fail_InvalidHeader",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "access-token")
+			},
+			mocks:    func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {},
+			output:   nil,
+			expected: domain.ErrInvalidAuthorizationHeader,
+		},
+		{
+			desc: "Fail_UnsupportedType",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Digest credentials")
+			},
+			mocks:    func(authService *mock.MockAuthService, apiKeyService *mock.MockAPIKeyService) {},
+			output:   nil,
+			expected: domain.ErrInvalidAuthorizationType,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authService := mock.NewMockAuthService(ctrl)
+			apiKeyService := mock.NewMockAPIKeyService(ctrl)
+			tc.mocks(authService, apiKeyService)
+
+			authenticator := handler.NewAuthenticatorChain(
+				handler.NewBearerAuthenticator(authService),
+				handler.NewAPIKeyAuthenticator(apiKeyService),
+				handler.NewBasicAuthenticator(apiKeyService),
+				handler.NewCertificateAuthenticator(authService),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/users/me", nil)
+			tc.setup(req)
+
+			payload, err := authenticator.Authenticate(newAuthenticatorContext(req))
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			assert.Equal(t, tc.output, payload, "Payload mismatch")
+		})
+	}
+}
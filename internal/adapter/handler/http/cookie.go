@@ -0,0 +1,248 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// sessionCookieName is the cookie holding the access token of a browser session
+	sessionCookieName = "session"
+	// refreshCookieName is the cookie holding the refresh token of a browser session
+	refreshCookieName = "refresh_token"
+	// refreshCookiePath limits the refresh token cookie to the refresh and logout routes
+	refreshCookiePath = "/v1/users"
+	// csrfCookieName is the cookie holding the csrf token in the double submit mode, it is readable by scripts
+	csrfCookieName = "csrf_token"
+	// csrfHeaderKey is the header state-changing requests send the csrf token in
+	csrfHeaderKey = "X-CSRF-Token"
+	// csrfModeDoubleSubmit compares the csrf header with a random token kept in a cookie
+	csrfModeDoubleSubmit = "double_submit"
+	// csrfModeSynchronizer compares the csrf header with a token the server derives from the session
+	csrfModeSynchronizer = "synchronizer"
+	// csrfTokenSize is the number of random bytes of a double submit csrf token
+	csrfTokenSize = 32
+	// minCSRFKeySize is the minimum size in bytes of the key synchronizer csrf tokens are derived with
+	minCSRFKeySize = 32
+)
+
+// SessionCookies keeps the tokens of browser sessions in HttpOnly cookies so that scripts cannot read them,
+// and protects the requests authenticated with them against cross-site request forgery
+type SessionCookies struct {
+	domain   string
+	sameSite http.SameSite
+	csrfMode string
+	csrfKey  []byte
+}
+
+// NewSessionCookies creates a new SessionCookies instance from the http configuration,
+// it returns nil when the session cookies are disabled
+func NewSessionCookies(config *config.HTTP) (*SessionCookies, error) {
+	if config.SessionCookie != "true" {
+		return nil, nil
+	}
+
+	sc := &SessionCookies{
+		domain:   config.CookieDomain,
+		csrfMode: config.CSRFMode,
+	}
+
+	switch strings.ToLower(config.CookieSameSite) {
+	case "", "lax":
+		sc.sameSite = http.SameSiteLaxMode
+	case "strict":
+		sc.sameSite = http.SameSiteStrictMode
+	case "none":
+		sc.sameSite = http.SameSiteNoneMode
+	default:
+		return nil, fmt.Errorf("invalid cookie same site %q, expected lax, strict or none", config.CookieSameSite)
+	}
+
+	switch sc.csrfMode {
+	case "":
+		sc.csrfMode = csrfModeDoubleSubmit
+	case csrfModeDoubleSubmit:
+	case csrfModeSynchronizer:
+		key, err := hex.DecodeString(config.CSRFKey)
+		if err != nil || len(key) < minCSRFKeySize {
+			return nil, fmt.Errorf("invalid csrf key, it must be at least %d hex encoded bytes", minCSRFKeySize)
+		}
+		sc.csrfKey = key
+	default:
+		return nil, fmt.Errorf("invalid csrf mode %q, expected %s or %s", config.CSRFMode, csrfModeDoubleSubmit, csrfModeSynchronizer)
+	}
+
+	return sc, nil
+}
+
+// set sends the tokens of a new or refreshed session as cookies and returns the csrf token of the session.
+// Nothing is sent for a login that still needs a second factor
+func (sc *SessionCookies) set(ctx *gin.Context, token *domain.AuthToken) (string, error) {
+	if sc == nil || token.AccessToken == "" {
+		return "", nil
+	}
+
+	maxAge := int(time.Until(token.SessionExpiresAt).Seconds())
+
+	sc.setCookie(ctx, sessionCookieName, token.AccessToken, "/", maxAge, true)
+	sc.setCookie(ctx, refreshCookieName, token.RefreshToken, refreshCookiePath, maxAge, true)
+
+	if sc.csrfMode == csrfModeSynchronizer {
+		return sc.synchronizerToken(token.SessionID.String()), nil
+	}
+
+	csrfToken, err := util.GenerateRandomToken(csrfTokenSize)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	sc.setCookie(ctx, csrfCookieName, csrfToken, "/", maxAge, false)
+
+	return csrfToken, nil
+}
+
+// clear removes the cookies of the session
+func (sc *SessionCookies) clear(ctx *gin.Context) {
+	if sc == nil {
+		return
+	}
+
+	sc.setCookie(ctx, sessionCookieName, "", "/", -1, true)
+	sc.setCookie(ctx, refreshCookieName, "", refreshCookiePath, -1, true)
+	if sc.csrfMode == csrfModeDoubleSubmit {
+		sc.setCookie(ctx, csrfCookieName, "", "/", -1, false)
+	}
+}
+
+// setCookie sends a secure cookie with the configured domain and same site mode
+func (sc *SessionCookies) setCookie(ctx *gin.Context, name, value, path string, maxAge int, httpOnly bool) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   sc.domain,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: httpOnly,
+		SameSite: sc.sameSite,
+	})
+}
+
+// refreshToken returns the refresh token cookie of the request
+func (sc *SessionCookies) refreshToken(ctx *gin.Context) (string, bool) {
+	if sc == nil {
+		return "", false
+	}
+
+	refreshToken, err := ctx.Cookie(refreshCookieName)
+	if err != nil || refreshToken == "" {
+		return "", false
+	}
+
+	return refreshToken, true
+}
+
+// checkCSRF checks the csrf token of a state-changing request authenticated with a cookie of the session
+func (sc *SessionCookies) checkCSRF(ctx *gin.Context, sessionID uuid.UUID) error {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	header := ctx.GetHeader(csrfHeaderKey)
+	if header == "" {
+		return domain.ErrInvalidCSRFToken
+	}
+
+	var expected string
+	if sc.csrfMode == csrfModeSynchronizer {
+		expected = sc.synchronizerToken(sessionID.String())
+	} else {
+		cookie, err := ctx.Cookie(csrfCookieName)
+		if err != nil {
+			return domain.ErrInvalidCSRFToken
+		}
+		expected = cookie
+	}
+
+	if expected == "" || subtle.ConstantTimeCompare([]byte(header), []byte(expected)) != 1 {
+		return domain.ErrInvalidCSRFToken
+	}
+
+	return nil
+}
+
+// checkRefreshCSRF checks the csrf token of a refresh made with the refresh token cookie,
+// the session of the refresh token is only looked up to derive a synchronizer token
+func (sc *SessionCookies) checkRefreshCSRF(ctx *gin.Context, svc port.AuthService, refreshToken string) error {
+	var sessionID uuid.UUID
+	if sc.csrfMode == csrfModeSynchronizer {
+		var err error
+		sessionID, err = svc.RefreshTokenSession(ctx, refreshToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return sc.checkCSRF(ctx, sessionID)
+}
+
+// synchronizerToken derives the csrf token of a session
+func (sc *SessionCookies) synchronizerToken(sessionID string) string {
+	mac := hmac.New(sha256.New, sc.csrfKey)
+	mac.Write([]byte(sessionID))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CookieAuthenticator authenticates browser requests with the access token of the session cookie,
+// state-changing requests must also send the session's csrf token in the X-CSRF-Token header
+type CookieAuthenticator struct {
+	svc     port.AuthService
+	cookies *SessionCookies
+}
+
+// NewCookieAuthenticator creates a new CookieAuthenticator instance, it authenticates nothing when the cookies are nil
+func NewCookieAuthenticator(svc port.AuthService, cookies *SessionCookies) *CookieAuthenticator {
+	return &CookieAuthenticator{
+		svc,
+		cookies,
+	}
+}
+
+// Authenticate verifies the access token of the session cookie and the csrf token of the request
+func (ca *CookieAuthenticator) Authenticate(ctx *gin.Context) (*domain.TokenPayload, error) {
+	if ca.cookies == nil {
+		return nil, errNoCredentials
+	}
+
+	token, err := ctx.Cookie(sessionCookieName)
+	if err != nil || token == "" {
+		return nil, errNoCredentials
+	}
+
+	payload, err := ca.svc.VerifyToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ca.cookies.checkCSRF(ctx, payload.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,303 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newCookieServer starts a test server that sends the tokens of logins as session cookies
+func newCookieServer(
+	t *testing.T,
+	httpConfig *config.HTTP,
+	authService *mock.MockAuthService,
+	userService *mock.MockUserService,
+) *httptest.Server {
+	cookies, err := handler.NewSessionCookies(httpConfig)
+	require.NoError(t, err)
+
+	router, err := handler.NewRouter(
+		httpConfig,
+		handler.NewAuthenticatorChain(
+			handler.NewBearerAuthenticator(authService),
+			handler.NewCookieAuthenticator(authService, cookies),
+		),
+		nil,
+		nil,
+		nil,
+		*handler.NewUserHandler(userService, nil),
+		*handler.NewAuthHandler(authService, cookies),
+		handler.KeyHandler{},
+		handler.PolicyHandler{},
+		handler.TwoFactorHandler{},
+		handler.PasswordResetHandler{},
+		handler.APIKeyHandler{},
+		handler.OAuthHandler{},
+		handler.OIDCHandler{},
+		handler.SessionHandler{},
+		handler.ImpersonationHandler{},
+		handler.AuditHandler{},
+	)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// doCookieRequest sends a request with the given cookies and headers and returns the response and its data
+func doCookieRequest(
+	t *testing.T,
+	method, url, body string,
+	cookies []*http.Cookie,
+	headers map[string]string,
+) (*http.Response, map[string]any) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var rsp struct {
+		Data map[string]any `json:"data"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&rsp)
+	require.NoError(t, err)
+
+	return res, rsp.Data
+}
+
+// findCookie returns the cookie with the given name set by the response
+func findCookie(res *http.Response, name string) *http.Cookie {
+	for _, cookie := range res.Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return nil
+}
+
+func TestSessionCookies(t *testing.T) {
+	user := &domain.User{
+		ID:    1,
+		Name:  "John Doe",
+		Email: "test@example.com",
+		Role:  domain.Cashier,
+	}
+	authToken := &domain.AuthToken{
+		User:             user,
+		AccessToken:      "access-token",
+		RefreshToken:     "refresh-token",
+		SessionID:        uuid.New(),
+		SessionExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+	principal := &domain.TokenPayload{
+		UserID:    1,
+		Role:      domain.Cashier,
+		SessionID: authToken.SessionID,
+	}
+	loginBody := `{"email":"test@example.com","password":"Correct4HorseBattery"}`
+
+	// newConfig enables the session cookies with the given csrf mode
+	newConfig := func(csrfMode string) *config.HTTP {
+		return &config.HTTP{
+			Env:            "test",
+			AllowedOrigins: "http://localhost:5173",
+			SessionCookie:  "true",
+			CookieSameSite: "strict",
+			CSRFMode:       csrfMode,
+			CSRFKey:        "3a7f1c9e5b2d8f4a6c0e2b4d6f8a1c3e5b7d9f2a4c6e8b0d1f3a5c7e9b2d4f6a",
+		}
+	}
+
+	// newMocks expects a login and any number of requests authenticated with its access token
+	newMocks := func(t *testing.T) (*mock.MockAuthService, *mock.MockUserService) {
+		ctrl := gomock.NewController(t)
+
+		authService := mock.NewMockAuthService(ctrl)
+		authService.EXPECT().
+			Login(gomock.Any(), gomock.Eq(user.Email), gomock.Any()).
+			Return(authToken, nil)
+		authService.EXPECT().
+			VerifyToken(gomock.Any(), gomock.Eq(authToken.AccessToken)).
+			Return(principal, nil).
+			AnyTimes()
+
+		return authService, mock.NewMockUserService(ctrl)
+	}
+
+	t.Run("DoubleSubmit", func(t *testing.T) {
+		authService, userService := newMocks(t)
+		userService.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(user.ID)).
+			Return(user, nil)
+		userService.EXPECT().
+			UpdateUser(gomock.Any(), gomock.Eq(principal), gomock.Any()).
+			Return(user, nil)
+		server := newCookieServer(t, newConfig("double_submit"), authService, userService)
+
+		res, data := doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/login", loginBody, nil, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "Status mismatch")
+
+		session := findCookie(res, "session")
+		require.NotNil(t, session, "Session cookie missing")
+		assert.Equal(t, authToken.AccessToken, session.Value, "Session cookie mismatch")
+		assert.True(t, session.HttpOnly, "Session cookie readable by scripts")
+		assert.True(t, session.Secure, "Session cookie not secure")
+		assert.Equal(t, http.SameSiteStrictMode, session.SameSite, "Same site mismatch")
+
+		refresh := findCookie(res, "refresh_token")
+		require.NotNil(t, refresh, "Refresh cookie missing")
+		assert.True(t, refresh.HttpOnly, "Refresh cookie readable by scripts")
+		assert.Equal(t, "/v1/users", refresh.Path, "Refresh cookie path mismatch")
+
+		csrf := findCookie(res, "csrf_token")
+		require.NotNil(t, csrf, "CSRF cookie missing")
+		assert.False(t, csrf.HttpOnly, "CSRF cookie not readable by scripts")
+		assert.Equal(t, csrf.Value, data["csrf_token"], "CSRF token mismatch")
+
+		assert.NotContains(t, data, "token", "Access token readable by scripts")
+		assert.NotContains(t, data, "refresh_token", "Refresh token readable by scripts")
+		loggedIn, ok := data["user"].(map[string]any)
+		require.True(t, ok, "User missing")
+		assert.Equal(t, user.Email, loggedIn["email"], "User mismatch")
+
+		cookies := []*http.Cookie{session, csrf}
+
+		res, _ = doCookieRequest(t, http.MethodGet, server.URL+"/v1/users/me", "", cookies, nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode, "Safe request without csrf token rejected")
+
+		res, _ = doCookieRequest(t, http.MethodPatch, server.URL+"/v1/users/me", `{"name":"Jane Doe"}`, cookies, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, "State-changing request without csrf token accepted")
+
+		res, _ = doCookieRequest(t, http.MethodPatch, server.URL+"/v1/users/me", `{"name":"Jane Doe"}`, cookies, map[string]string{
+			"X-CSRF-Token": "forged",
+		})
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, "State-changing request with forged csrf token accepted")
+
+		res, _ = doCookieRequest(t, http.MethodPatch, server.URL+"/v1/users/me", `{"name":"Jane Doe"}`, cookies, map[string]string{
+			"X-CSRF-Token": csrf.Value,
+		})
+		assert.Equal(t, http.StatusOK, res.StatusCode, "State-changing request with csrf token rejected")
+	})
+
+	t.Run("Synchronizer", func(t *testing.T) {
+		authService, userService := newMocks(t)
+		userService.EXPECT().
+			UpdateUser(gomock.Any(), gomock.Eq(principal), gomock.Any()).
+			Return(user, nil)
+		server := newCookieServer(t, newConfig("synchronizer"), authService, userService)
+
+		res, data := doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/login", loginBody, nil, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "Status mismatch")
+		assert.Nil(t, findCookie(res, "csrf_token"), "CSRF cookie set")
+
+		csrfToken, ok := data["csrf_token"].(string)
+		require.True(t, ok, "CSRF token missing")
+
+		cookies := []*http.Cookie{findCookie(res, "session")}
+
+		res, _ = doCookieRequest(t, http.MethodPatch, server.URL+"/v1/users/me", `{"name":"Jane Doe"}`, cookies, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, "State-changing request without csrf token accepted")
+
+		res, _ = doCookieRequest(t, http.MethodPatch, server.URL+"/v1/users/me", `{"name":"Jane Doe"}`, cookies, map[string]string{
+			"X-CSRF-Token": csrfToken,
+		})
+		assert.Equal(t, http.StatusOK, res.StatusCode, "State-changing request with csrf token rejected")
+	})
+
+	t.Run("RefreshAndLogout", func(t *testing.T) {
+		authService, userService := newMocks(t)
+		authService.EXPECT().
+			Refresh(gomock.Any(), gomock.Eq(authToken.RefreshToken)).
+			Return(authToken, nil)
+		authService.EXPECT().
+			Logout(gomock.Any(), gomock.Eq(principal), gomock.Eq(authToken.RefreshToken)).
+			Return(nil)
+		server := newCookieServer(t, newConfig("double_submit"), authService, userService)
+
+		res, data := doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/login", loginBody, nil, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "Status mismatch")
+		cookies := res.Cookies()
+
+		res, _ = doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/refresh", "", cookies, nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, "Refresh with cookie and without csrf token accepted")
+
+		res, _ = doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/refresh", "", cookies, map[string]string{
+			"X-CSRF-Token": data["csrf_token"].(string),
+		})
+		assert.Equal(t, http.StatusOK, res.StatusCode, "Refresh with cookie rejected")
+
+		res, _ = doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/logout", "", cookies, map[string]string{
+			"X-CSRF-Token": data["csrf_token"].(string),
+		})
+		assert.Equal(t, http.StatusOK, res.StatusCode, "Logout rejected")
+
+		session := findCookie(res, "session")
+		require.NotNil(t, session, "Session cookie not cleared")
+		assert.Empty(t, session.Value, "Session cookie not cleared")
+		assert.Less(t, session.MaxAge, 0, "Session cookie not expired")
+	})
+
+	t.Run("SynchronizerRefresh", func(t *testing.T) {
+		authService, userService := newMocks(t)
+		authService.EXPECT().
+			RefreshTokenSession(gomock.Any(), gomock.Eq(authToken.RefreshToken)).
+			Return(authToken.SessionID, nil).
+			Times(2)
+		authService.EXPECT().
+			Refresh(gomock.Any(), gomock.Eq(authToken.RefreshToken)).
+			Return(authToken, nil)
+		server := newCookieServer(t, newConfig("synchronizer"), authService, userService)
+
+		res, data := doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/login", loginBody, nil, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "Status mismatch")
+		cookies := res.Cookies()
+
+		res, _ = doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/refresh", "", cookies, map[string]string{
+			"X-CSRF-Token": "forged",
+		})
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, "Refresh with cookie and forged csrf token accepted")
+
+		res, _ = doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/refresh", "", cookies, map[string]string{
+			"X-CSRF-Token": data["csrf_token"].(string),
+		})
+		assert.Equal(t, http.StatusOK, res.StatusCode, "Refresh with cookie rejected")
+	})
+
+	t.Run("Fail_InvalidConfig", func(t *testing.T) {
+		httpConfig := newConfig("synchronizer")
+		httpConfig.CSRFKey = "short"
+
+		_, err := handler.NewSessionCookies(httpConfig)
+		assert.Error(t, err, "Error mismatch")
+
+		httpConfig = newConfig("double_submit")
+		httpConfig.CookieSameSite = "sometimes"
+
+		_, err = handler.NewSessionCookies(httpConfig)
+		assert.Error(t, err, "Error mismatch")
+	})
+}
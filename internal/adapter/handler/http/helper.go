@@ -1,7 +1,14 @@
 package http
 
 import (
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/gin-gonic/gin"
 )
 
 // stringToUint64 is a helper function to convert a string to uint64
@@ -18,3 +25,40 @@ func toMap(m meta, data any, key string) map[string]any {
 		key:    data,
 	}
 }
+
+// setRetryAfter is a helper function to tell the client how many seconds to wait before retrying
+func setRetryAfter(ctx *gin.Context, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	ctx.Header("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+}
+
+// parseRateLimit is a helper function to parse a rate limit written as requests/window, such as 100/1m.
+// An empty value disables the rate limit
+func parseRateLimit(name, value string) (domain.RateLimit, error) {
+	limit := domain.RateLimit{
+		Name: name,
+	}
+
+	if value == "" {
+		return limit, nil
+	}
+
+	requests, window, ok := strings.Cut(value, "/")
+	if !ok {
+		return limit, fmt.Errorf("invalid %s rate limit %q, expected requests/window", name, value)
+	}
+
+	var err error
+
+	limit.Requests, err = strconv.ParseInt(requests, 10, 64)
+	if err != nil || limit.Requests <= 0 {
+		return limit, fmt.Errorf("invalid %s rate limit requests %q", name, requests)
+	}
+
+	limit.Window, err = time.ParseDuration(window)
+	if err != nil || limit.Window < time.Second {
+		return limit, fmt.Errorf("invalid %s rate limit window %q", name, window)
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler represents the HTTP handler for impersonation requests
+type ImpersonationHandler struct {
+	svc port.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new ImpersonationHandler instance
+func NewImpersonationHandler(svc port.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		svc,
+	}
+}
+
+// impersonateRequest represents the request body for impersonating a user
+type impersonateRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"Support ticket #1234"`
+}
+
+// Impersonate godoc
+//
+//	@Summary		Impersonate a user
+//	@Description	Issues an access token that acts as the user, for admins troubleshooting a user's account. The token cannot change the user's email, password or other credentials, has no refresh token, and every request made with it is recorded along with the admin and the reason given.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		uint64					true	"User ID"
+//	@Param			request	body		impersonateRequest		true	"Impersonate request body"
+//	@Success		200		{object}	impersonationResponse	"Impersonation started"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		403		{object}	errorResponse			"Forbidden error"
+//	@Failure		404		{object}	errorResponse			"Data not found error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/{id}/impersonate [post]
+//	@Security		BearerAuth
+func (ih *ImpersonationHandler) Impersonate(ctx *gin.Context) {
+	var req impersonateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	idStr := ctx.Param("id")
+	id, err := stringToUint64(idStr)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	impersonation, accessToken, err := ih.svc.Impersonate(ctx, payload, id, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newImpersonationResponse(impersonation, accessToken)
+
+	handleSuccess(ctx, rsp)
+}
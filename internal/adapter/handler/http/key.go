@@ -0,0 +1,152 @@
+package http
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// publicKeysCacheControl lets downstream verifiers cache the public keys for a while
+const publicKeysCacheControl = "public, max-age=300"
+
+// KeyHandler represents the HTTP handler for public key discovery requests
+type KeyHandler struct {
+	provider port.PublicKeyProvider
+}
+
+// NewKeyHandler creates a new KeyHandler instance
+func NewKeyHandler(provider port.PublicKeyProvider) *KeyHandler {
+	return &KeyHandler{
+		provider,
+	}
+}
+
+// pasetoKeyResponse represents a paseto public key response body
+type pasetoKeyResponse struct {
+	ID        string `json:"kid" example:"2025-01"`
+	Version   string `json:"version" example:"v4"`
+	Purpose   string `json:"purpose" example:"public"`
+	PublicKey string `json:"public_key" example:"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2"`
+	Paserk    string `json:"paserk" example:"k4.public.Hrnbu7wEfAP9cGBOAHHwmH4Wsot1ciXBHwBBXQ4gsaI"`
+}
+
+// pasetoKeySetResponse represents the set of paseto public keys response body
+type pasetoKeySetResponse struct {
+	Keys []pasetoKeyResponse `json:"keys"`
+}
+
+// newPasetoKeySetResponse is a helper function to create a response body for handling paseto public keys
+func newPasetoKeySetResponse(keys []domain.PublicKey) pasetoKeySetResponse {
+	rsp := pasetoKeySetResponse{
+		Keys: []pasetoKeyResponse{},
+	}
+
+	for _, key := range keys {
+		publicKey, ok := key.Key.(ed25519.PublicKey)
+		if !ok || key.Algorithm != "v4.public" {
+			continue
+		}
+
+		rsp.Keys = append(rsp.Keys, pasetoKeyResponse{
+			ID:        key.ID,
+			Version:   "v4",
+			Purpose:   "public",
+			PublicKey: hex.EncodeToString(publicKey),
+			Paserk:    "k4.public." + base64.RawURLEncoding.EncodeToString(publicKey),
+		})
+	}
+
+	return rsp
+}
+
+// jwkResponse represents a JSON Web Key response body
+type jwkResponse struct {
+	KeyType   string `json:"kty" example:"OKP"`
+	ID        string `json:"kid" example:"2025-01"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"EdDSA"`
+	Curve     string `json:"crv,omitempty" example:"Ed25519"`
+	X         string `json:"x,omitempty" example:"Hrnbu7wEfAP9cGBOAHHwmH4Wsot1ciXBHwBBXQ4gsaI"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty" example:"AQAB"`
+}
+
+// jwkSetResponse represents a JSON Web Key Set response body
+type jwkSetResponse struct {
+	Keys []jwkResponse `json:"keys"`
+}
+
+// newJWKSetResponse is a helper function to create a response body for handling jwt public keys
+func newJWKSetResponse(keys []domain.PublicKey) jwkSetResponse {
+	rsp := jwkSetResponse{
+		Keys: []jwkResponse{},
+	}
+
+	for _, key := range keys {
+		jwk := jwkResponse{
+			ID:        key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+		}
+
+		switch publicKey := key.Key.(type) {
+		case *rsa.PublicKey:
+			if key.Algorithm != "RS256" {
+				continue
+			}
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			if key.Algorithm != "EdDSA" {
+				continue
+			}
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+
+		rsp.Keys = append(rsp.Keys, jwk)
+	}
+
+	return rsp
+}
+
+// PasetoKeys godoc
+//
+//	@Summary		List paseto public keys
+//	@Description	Lists the public keys currently accepted to verify v4.public access tokens, identified by the "kid" of the token footer. The list is empty when tokens are symmetric encrypted
+//	@Tags			Keys
+//	@Produce		json
+//	@Success		200	{object}	pasetoKeySetResponse	"Public keys displayed"
+//	@Router			/.well-known/paseto-keys [get]
+func (kh *KeyHandler) PasetoKeys(ctx *gin.Context) {
+	rsp := newPasetoKeySetResponse(kh.provider.PublicKeys())
+
+	ctx.Header("Cache-Control", publicKeysCacheControl)
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// JWKS godoc
+//
+//	@Summary		List jwt public keys
+//	@Description	Lists the public keys currently accepted to verify RS256 and EdDSA access tokens as a JSON Web Key Set, identified by the "kid" of the token header. The set is empty when tokens are signed with a shared secret
+//	@Tags			Keys
+//	@Produce		json
+//	@Success		200	{object}	jwkSetResponse	"Public keys displayed"
+//	@Router			/.well-known/jwks.json [get]
+func (kh *KeyHandler) JWKS(ctx *gin.Context) {
+	rsp := newJWKSetResponse(kh.provider.PublicKeys())
+
+	ctx.Header("Cache-Control", publicKeysCacheControl)
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,108 @@
+package http_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestKeyHandler_JWKS(t *testing.T) {
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		keys     []domain.PublicKey
+		expected []map[string]any
+	}{
+		{
+			desc: "EdDSA",
+			keys: []domain.PublicKey{
+				{ID: "k1", Algorithm: "EdDSA", Key: edPublicKey},
+			},
+			expected: []map[string]any{
+				{
+					"kty": "OKP",
+					"kid": "k1",
+					"use": "sig",
+					"alg": "EdDSA",
+					"crv": "Ed25519",
+					"x":   base64.RawURLEncoding.EncodeToString(edPublicKey),
+				},
+			},
+		},
+		{
+			desc: "RS256",
+			keys: []domain.PublicKey{
+				{ID: "k1", Algorithm: "RS256", Key: &rsaKey.PublicKey},
+			},
+			expected: []map[string]any{
+				{
+					"kty": "RSA",
+					"kid": "k1",
+					"use": "sig",
+					"alg": "RS256",
+					"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+				},
+			},
+		},
+		{
+			desc: "SkipsPasetoKeys",
+			keys: []domain.PublicKey{
+				{ID: "k1", Algorithm: "v4.public", Key: edPublicKey},
+			},
+			expected: []map[string]any{},
+		},
+		{
+			desc:     "SharedSecret",
+			keys:     []domain.PublicKey{},
+			expected: []map[string]any{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			provider := mock.NewMockPublicKeyProvider(ctrl)
+			provider.EXPECT().
+				PublicKeys().
+				Return(tc.keys)
+
+			router := gin.New()
+			router.GET("/.well-known/jwks.json", handler.NewKeyHandler(provider).JWKS)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+			assert.Equal(t, http.StatusOK, rec.Code, "Status mismatch")
+			assert.Equal(t, "public, max-age=300", rec.Header().Get("Cache-Control"), "Cache control mismatch")
+
+			var rsp struct {
+				Keys []map[string]any `json:"keys"`
+			}
+			err := json.Unmarshal(rec.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rsp.Keys, "Keys mismatch")
+		})
+	}
+}
@@ -1,57 +1,230 @@
 package http
 
 import (
-	"strings"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
 
 	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	// authorizationHeaderKey is the key for authorization header in the request
-	authorizationHeaderKey = "authorization"
-	// authorizationType is the accepted authorization type
-	authorizationType = "bearer"
 	// authorizationPayloadKey is the key for authorization payload in the context
 	authorizationPayloadKey = "authorization_payload"
 )
 
-// authMiddleware is a middleware to check if the user is authenticated
-func authMiddleware(token port.TokenService) gin.HandlerFunc {
+// clientInfoMiddleware is a middleware to carry the client IP and user agent in the request context
+func clientInfoMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		info := &domain.ClientInfo{
+			IP:        ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+		}
+
+		ctx.Request = ctx.Request.WithContext(util.ContextWithClientInfo(ctx.Request.Context(), info))
+		ctx.Next()
+	}
+}
 
-		isEmpty := len(authorizationHeader) == 0
-		if isEmpty {
-			err := domain.ErrEmptyAuthorizationHeader
+// authMiddleware is a middleware to check if the request is authenticated by one of the authenticator's schemes.
+// The principal is available to handlers through getPrincipal and to services through util.PrincipalFromContext
+func authMiddleware(authenticator Authenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, err := authenticator.Authenticate(ctx)
+		if err != nil {
 			handleAbort(ctx, err)
 			return
 		}
 
-		fields := strings.Fields(authorizationHeader)
-		isValid := len(fields) == 2
-		if !isValid {
-			err := domain.ErrInvalidAuthorizationHeader
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Request = ctx.Request.WithContext(util.ContextWithPrincipal(ctx.Request.Context(), payload))
+		ctx.Next()
+	}
+}
+
+// getPrincipal returns the principal authenticated by authMiddleware,
+// it panics like gin's MustGet when called on a route without authentication
+func getPrincipal(ctx *gin.Context) *domain.TokenPayload {
+	return ctx.MustGet(authorizationPayloadKey).(*domain.TokenPayload)
+}
+
+// impersonationAuditMiddleware is a middleware to record every request made with an impersonation token,
+// the request is recorded once it is handled so that its status is known
+func impersonationAuditMiddleware(svc port.ImpersonationService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getPrincipal(ctx)
+		if payload.ImpersonatorID == 0 {
+			ctx.Next()
+			return
+		}
+
+		ctx.Next()
+
+		info := util.ClientInfoFromContext(ctx)
+		request := &domain.ImpersonatedRequest{
+			ImpersonationID: payload.ID,
+			Method:          ctx.Request.Method,
+			Path:            ctx.Request.URL.Path,
+			Status:          ctx.Writer.Status(),
+			IP:              info.IP,
+			UserAgent:       info.UserAgent,
+		}
+
+		err := svc.RecordRequest(ctx, request)
+		if err != nil {
+			slog.Error("Error recording impersonated request", "impersonation_id", payload.ID, "path", request.Path, "error", err)
+		}
+	}
+}
+
+// denyImpersonation is a middleware to reject requests made with an impersonation token,
+// it guards routes that manage the user's credentials
+func denyImpersonation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getPrincipal(ctx)
+
+		if payload.ImpersonatorID != 0 {
+			err := domain.ErrImpersonationForbidden
 			handleAbort(ctx, err)
 			return
 		}
 
-		currentAuthorizationType := strings.ToLower(fields[0])
-		if currentAuthorizationType != authorizationType {
-			err := domain.ErrInvalidAuthorizationType
+		ctx.Next()
+	}
+}
+
+// requireUser is a middleware to reject principals that are a client acting on its own behalf,
+// such as client credentials tokens, it guards routes that act on the authenticated user
+func requireUser() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getPrincipal(ctx)
+
+		if payload.UserID == 0 && payload.ClientID != "" {
+			err := domain.ErrClientForbidden
 			handleAbort(ctx, err)
 			return
 		}
 
-		accessToken := fields[1]
-		payload, err := token.VerifyToken(accessToken)
+		ctx.Next()
+	}
+}
+
+// requireRole is a middleware to check if the authenticated user has one of the given roles
+func requireRole(roles ...domain.UserRole) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getPrincipal(ctx)
+
+		for _, role := range roles {
+			if payload.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		err := domain.ErrForbidden
+		handleAbort(ctx, err)
+	}
+}
+
+// requireScope is a middleware to check if the scopes of the authenticated principal cover the action,
+// it guards routes whose access is not decided by the authorization policy
+func requireScope(action string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getPrincipal(ctx)
+
+		if !util.ScopesAllow(payload.Scopes, action) {
+			err := domain.ErrForbidden
+			handleAbort(ctx, err)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// authorize is a middleware to check if the authenticated user may perform the action on the route's resource,
+// the resource ID is taken from the id path parameter when the route has one
+func authorize(authz port.Authorizer, action, resourceType string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getPrincipal(ctx)
+		resource := &domain.PolicyResource{
+			Type: resourceType,
+			ID:   ctx.Param("id"),
+		}
+
+		decision, err := authz.Can(ctx, payload, action, resource)
 		if err != nil {
 			handleAbort(ctx, err)
 			return
 		}
 
-		ctx.Set(authorizationPayloadKey, payload)
+		if !decision.Allowed {
+			err := domain.ErrForbidden
+			handleAbort(ctx, err)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// rateLimitKeyFunc extracts the key identifying the client to rate limit
+type rateLimitKeyFunc func(ctx *gin.Context) string
+
+// rateLimitByIP identifies clients by their IP
+func rateLimitByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// rateLimitByUser identifies authenticated clients by their user ID, or by their api key so that
+// machine clients do not use up the limit of their owner, and other clients by their IP
+func rateLimitByUser(ctx *gin.Context) string {
+	value, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		return rateLimitByIP(ctx)
+	}
+
+	payload := value.(*domain.TokenPayload)
+	if payload.APIKeyID != 0 {
+		return "api_key:" + strconv.FormatUint(payload.APIKeyID, 10)
+	}
+
+	return "user:" + strconv.FormatUint(payload.UserID, 10)
+}
+
+// rateLimitMiddleware is a middleware to limit the number of requests of a client,
+// requests are let through if the limiter is unavailable so that an outage of the cache does not take the API down
+func rateLimitMiddleware(limiter port.RateLimiter, limit domain.RateLimit, keyFunc rateLimitKeyFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if limit.Requests <= 0 {
+			ctx.Next()
+			return
+		}
+
+		result, err := limiter.Allow(ctx, keyFunc(ctx), limit)
+		if err != nil {
+			slog.Warn("Rate limiter unavailable, letting the request through", "limit", limit.Name, "error", err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int64(limit.Window.Seconds())))
+		ctx.Header("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		ctx.Header("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		ctx.Header("RateLimit-Reset", strconv.FormatInt(int64(math.Ceil(result.Reset.Seconds())), 10))
+
+		if !result.Allowed {
+			setRetryAfter(ctx, result.RetryAfter)
+
+			err := domain.ErrRateLimited
+			handleAbort(ctx, err)
+			return
+		}
+
 		ctx.Next()
 	}
 }
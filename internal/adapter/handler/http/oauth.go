@@ -0,0 +1,361 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// OAuthHandler represents the HTTP handler for oauth requests
+type OAuthHandler struct {
+	svc port.OAuthService
+}
+
+// NewOAuthHandler creates a new OAuthHandler instance
+func NewOAuthHandler(svc port.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		svc,
+	}
+}
+
+// registerOAuthClientRequest represents the request body for registering an oauth client
+type registerOAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required,max=100" example:"Reporting tool"`
+	RedirectURIs []string `json:"redirect_uris" binding:"omitempty,dive,url" example:"https://reports.example.com/callback"`
+	Scopes       []string `json:"scopes" binding:"required,min=1,dive,required" example:"users:read"`
+	Confidential bool     `json:"confidential" example:"true"`
+}
+
+// RegisterClient godoc
+//
+//	@Summary		Register an oauth client
+//	@Description	Registers a third-party application as an oauth client. Confidential clients get a secret, which is only shown once, and may use the client credentials grant. Public clients, such as single-page and mobile apps, have no secret and must register at least one redirect uri. Scopes, such as users:read, limit what the client may ask for.
+//	@Tags			OAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		registerOAuthClientRequest		true	"Register oauth client request body"
+//	@Success		200		{object}	registeredOAuthClientResponse	"Oauth client registered"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		403		{object}	errorResponse					"Forbidden error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/v1/oauth/clients [post]
+//	@Security		BearerAuth
+func (oh *OAuthHandler) RegisterClient(ctx *gin.Context) {
+	var req registerOAuthClientRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	client := &domain.OAuthClient{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		Scopes:       req.Scopes,
+		Confidential: req.Confidential,
+	}
+
+	client, secret, err := oh.svc.RegisterClient(ctx, payload, client)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newRegisteredOAuthClientResponse(client, secret)
+
+	handleSuccess(ctx, rsp)
+}
+
+// ListClients godoc
+//
+//	@Summary		List oauth clients
+//	@Description	Lists every registered oauth client, their secrets are never shown again
+//	@Tags			OAuth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	[]oauthClientResponse	"Oauth clients displayed"
+//	@Failure		401	{object}	errorResponse			"Unauthorized error"
+//	@Failure		403	{object}	errorResponse			"Forbidden error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/v1/oauth/clients [get]
+//	@Security		BearerAuth
+func (oh *OAuthHandler) ListClients(ctx *gin.Context) {
+	clients, err := oh.svc.ListClients(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []oauthClientResponse{}
+	for _, client := range clients {
+		rsp = append(rsp, newOAuthClientResponse(&client))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// deleteOAuthClientRequest represents the request body for deleting an oauth client
+type deleteOAuthClientRequest struct {
+	ClientID string `uri:"client_id" binding:"required" example:"yN3sLp7eUa5fGiYoXkR2cA"`
+}
+
+// DeleteClient godoc
+//
+//	@Summary		Delete an oauth client
+//	@Description	Deletes an oauth client with the consents given to it. The access tokens already issued to the client stop working immediately.
+//	@Tags			OAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string			true	"Oauth client ID"
+//	@Success		200			{object}	response		"Oauth client deleted"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		401			{object}	errorResponse	"Unauthorized error"
+//	@Failure		403			{object}	errorResponse	"Forbidden error"
+//	@Failure		404			{object}	errorResponse	"Data not found error"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/v1/oauth/clients/{client_id} [delete]
+//	@Security		BearerAuth
+func (oh *OAuthHandler) DeleteClient(ctx *gin.Context) {
+	var req deleteOAuthClientRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := oh.svc.DeleteClient(ctx, req.ClientID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// authorizationRequest represents the parameters of an oauth authorization request
+type authorizationRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required,eq=code" example:"code"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required" example:"yN3sLp7eUa5fGiYoXkR2cA"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"omitempty,url" example:"https://reports.example.com/callback"`
+	Scope               string `form:"scope" json:"scope" example:"users:read"`
+	State               string `form:"state" json:"state" example:"af0ifjsldkj"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge" binding:"required" example:"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method" binding:"required,eq=S256" example:"S256"`
+}
+
+// toDomain converts the parameters to an authorization request, the scope is space separated
+func (req *authorizationRequest) toDomain() *domain.OAuthAuthorizationRequest {
+	return &domain.OAuthAuthorizationRequest{
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scopes:              strings.Fields(req.Scope),
+		State:               req.State,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}
+}
+
+// PrepareAuthorization godoc
+//
+//	@Summary		Show an oauth consent screen
+//	@Description	Validates an authorization code request with PKCE and returns the client and scopes for the consent screen. The frontend shows it to the signed in user, unless consent_required is false because the user already granted every scope, and submits the decision to POST /oauth/authorize.
+//	@Tags			OAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	query		authorizationRequest	true	"Authorization request"
+//	@Success		200		{object}	oauthConsentResponse	"Consent screen displayed"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		403		{object}	errorResponse			"Forbidden error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/oauth/authorize [get]
+//	@Security		BearerAuth
+func (oh *OAuthHandler) PrepareAuthorization(ctx *gin.Context) {
+	var req authorizationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	prompt, err := oh.svc.PrepareAuthorization(ctx, payload, req.toDomain())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newOAuthConsentResponse(prompt)
+
+	handleSuccess(ctx, rsp)
+}
+
+// authorizeRequest represents the request body for approving or denying an oauth authorization request
+type authorizeRequest struct {
+	authorizationRequest
+	Approved bool `json:"approved" example:"true"`
+}
+
+// Authorize godoc
+//
+//	@Summary		Approve or deny an oauth authorization request
+//	@Description	Records the decision of the signed in user and returns the redirect uri of the client to send the user to. An approval adds an authorization code, valid once for 5 minutes, and a denial adds the access_denied error. Api keys and oauth tokens cannot approve requests.
+//	@Tags			OAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authorizeRequest		true	"Authorize request body"
+//	@Success		200		{object}	oauthRedirectResponse	"Authorization decided"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		403		{object}	errorResponse			"Forbidden error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/oauth/authorize [post]
+//	@Security		BearerAuth
+func (oh *OAuthHandler) Authorize(ctx *gin.Context) {
+	var req authorizeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	redirectURI, err := oh.svc.Authorize(ctx, payload, req.toDomain(), req.Approved)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := oauthRedirectResponse{
+		RedirectURI: redirectURI,
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// tokenRequest represents the form of an oauth token request
+type tokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// Token godoc
+//
+//	@Summary		Get an oauth access token
+//	@Description	Issues an access token for the authorization_code grant, with the PKCE code verifier, or the client_credentials grant of confidential clients. Clients authenticate with HTTP Basic or the client_id and client_secret fields. Follows RFC 6749, errors have the error and error_description fields.
+//	@Tags			OAuth
+//	@Accept			x-www-form-urlencoded
+//	@Produce		json
+//	@Param			grant_type		formData	string					true	"Grant type"	Enums(authorization_code, client_credentials)
+//	@Param			code			formData	string					false	"Authorization code"
+//	@Param			redirect_uri	formData	string					false	"Redirect uri of the authorization request"
+//	@Param			code_verifier	formData	string					false	"PKCE code verifier"
+//	@Param			scope			formData	string					false	"Space separated scopes of the client credentials grant"
+//	@Param			client_id		formData	string					false	"Client ID"
+//	@Param			client_secret	formData	string					false	"Client secret"
+//	@Success		200				{object}	oauthTokenResponse		"Access token issued"
+//	@Failure		400				{object}	oauthErrorResponse		"Invalid request"
+//	@Failure		401				{object}	oauthErrorResponse		"Client authentication failed"
+//	@Failure		500				{object}	oauthErrorResponse		"Internal server error"
+//	@Router			/v1/oauth/token [post]
+func (oh *OAuthHandler) Token(ctx *gin.Context) {
+	var req tokenRequest
+	if err := ctx.ShouldBindWith(&req, binding.Form); err != nil {
+		oauthValidationError(ctx, err)
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(ctx, req.ClientID, req.ClientSecret)
+
+	token, err := oh.svc.Token(ctx, &domain.OAuthTokenRequest{
+		GrantType:    domain.OAuthGrantType(req.GrantType),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         req.Code,
+		RedirectURI:  req.RedirectURI,
+		CodeVerifier: req.CodeVerifier,
+		Scopes:       strings.Fields(req.Scope),
+	})
+	if err != nil {
+		handleOAuthError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+	ctx.JSON(http.StatusOK, newOAuthTokenResponse(token))
+}
+
+// revokeTokenRequest represents the form of an oauth token revocation request
+type revokeTokenRequest struct {
+	Token         string `form:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// Revoke godoc
+//
+//	@Summary		Revoke an oauth access token
+//	@Description	Revokes an access token issued to the authenticated client. Follows RFC 7009, unknown tokens and tokens of other clients are accepted without effect.
+//	@Tags			OAuth
+//	@Accept			x-www-form-urlencoded
+//	@Produce		json
+//	@Param			token			formData	string				true	"Access token"
+//	@Param			token_type_hint	formData	string				false	"Token type hint"
+//	@Param			client_id		formData	string				false	"Client ID"
+//	@Param			client_secret	formData	string				false	"Client secret"
+//	@Success		200				"Token revoked"
+//	@Failure		400				{object}	oauthErrorResponse	"Invalid request"
+//	@Failure		401				{object}	oauthErrorResponse	"Client authentication failed"
+//	@Failure		500				{object}	oauthErrorResponse	"Internal server error"
+//	@Router			/v1/oauth/revoke [post]
+func (oh *OAuthHandler) Revoke(ctx *gin.Context) {
+	var req revokeTokenRequest
+	if err := ctx.ShouldBindWith(&req, binding.Form); err != nil {
+		oauthValidationError(ctx, err)
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(ctx, req.ClientID, req.ClientSecret)
+
+	err := oh.svc.Revoke(ctx, clientID, clientSecret, req.Token)
+	if err != nil {
+		handleOAuthError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// clientCredentials returns the client credentials of the HTTP Basic authorization header,
+// falling back to the credentials sent in the form
+func clientCredentials(ctx *gin.Context, clientID, clientSecret string) (string, string) {
+	username, password, ok := ctx.Request.BasicAuth()
+	if !ok {
+		return clientID, clientSecret
+	}
+
+	username, err := url.QueryUnescape(username)
+	if err != nil {
+		return "", ""
+	}
+
+	password, err = url.QueryUnescape(password)
+	if err != nil {
+		return "", ""
+	}
+
+	return username, password
+}
@@ -0,0 +1,424 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	paseto "github.com/cidmiranda/go-ws/internal/adapter/auth/passeto"
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	// codeVerifier and codeChallenge are the PKCE example of RFC 7636
+	codeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	codeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	redirectURI   = "https://reports.example.com/callback"
+	clientSecret  = "client-secret"
+)
+
+// memoryCache is an in-memory port.CacheRepository, expired entries are not evicted
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (mc *memoryCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.values[key] = value
+	return nil
+}
+
+func (mc *memoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	value, ok := mc.values[key]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return value, nil
+}
+
+func (mc *memoryCache) Delete(_ context.Context, key string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	delete(mc.values, key)
+	return nil
+}
+
+func (mc *memoryCache) DeleteByPrefix(_ context.Context, prefix string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	for key := range mc.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(mc.values, key)
+		}
+	}
+	return nil
+}
+
+func (mc *memoryCache) Increment(_ context.Context, key string, _ time.Duration) (int64, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	count := int64(len(mc.values[key])) + 1
+	mc.values[key] = make([]byte, count)
+	return count, nil
+}
+
+func (mc *memoryCache) Close() error {
+	return nil
+}
+
+// oauthServer serves the oauth routes of the router with mocked repositories
+type oauthServer struct {
+	*httptest.Server
+	userToken string
+}
+
+// newOAuthServer starts a test server with a public and a confidential client registered for a signed in cashier
+func newOAuthServer(t *testing.T) *oauthServer {
+	ctrl := gomock.NewController(t)
+	user := &domain.User{
+		ID:   1,
+		Name: "John Doe",
+		Role: domain.Cashier,
+	}
+	clients := map[string]*domain.OAuthClient{
+		"public-client": {
+			ID:           3,
+			ClientID:     "public-client",
+			Name:         "Reporting tool",
+			RedirectURIs: []string{redirectURI},
+			Scopes:       []string{"users:read", "users:update"},
+		},
+		"confidential-client": {
+			ID:           4,
+			ClientID:     "confidential-client",
+			SecretHash:   util.HashToken(clientSecret),
+			Name:         "Nightly export",
+			Scopes:       []string{"users:read"},
+			Confidential: true,
+		},
+	}
+
+	clientRepo := mock.NewMockOAuthClientRepository(ctrl)
+	clientRepo.EXPECT().
+		GetOAuthClientByClientID(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, clientID string) (*domain.OAuthClient, error) {
+			client, ok := clients[clientID]
+			if !ok {
+				return nil, domain.ErrDataNotFound
+			}
+			return client, nil
+		}).
+		AnyTimes()
+	clientRepo.EXPECT().
+		GetOAuthConsent(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, domain.ErrDataNotFound).
+		AnyTimes()
+	clientRepo.EXPECT().
+		SaveOAuthConsent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, consent *domain.OAuthConsent) (*domain.OAuthConsent, error) {
+			return consent, nil
+		}).
+		AnyTimes()
+
+	userRepo := mock.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+		Return(user, nil).
+		AnyTimes()
+
+	token, err := paseto.New(&config.Token{
+		Duration: "15m",
+	})
+	require.NoError(t, err)
+
+	cache := &memoryCache{
+		values: map[string][]byte{},
+	}
+
+	authService := service.NewAuthService(userRepo, token, nil, nil, cache, nil, nil, nil, nil, time.Hour, false)
+	oauthService := service.NewOAuthService(clientRepo, userRepo, token, cache)
+
+	router, err := handler.NewRouter(
+		&config.HTTP{
+			Env:            "test",
+			AllowedOrigins: "http://localhost:5173",
+		},
+		handler.NewAuthenticatorChain(handler.NewBearerAuthenticator(authService)),
+		nil,
+		nil,
+		nil,
+		handler.UserHandler{},
+		handler.AuthHandler{},
+		handler.KeyHandler{},
+		handler.PolicyHandler{},
+		handler.TwoFactorHandler{},
+		handler.PasswordResetHandler{},
+		handler.APIKeyHandler{},
+		*handler.NewOAuthHandler(oauthService),
+		handler.OIDCHandler{},
+		handler.SessionHandler{},
+		handler.ImpersonationHandler{},
+		handler.AuditHandler{},
+	)
+	require.NoError(t, err)
+
+	userToken, err := token.CreateToken(&domain.TokenPayload{
+		UserID: user.ID,
+		Role:   user.Role,
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return &oauthServer{
+		server,
+		userToken,
+	}
+}
+
+// do sends a request with an optional bearer token and decodes the JSON response body
+func (s *oauthServer) do(t *testing.T, req *http.Request, bearer string, body any) int {
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	rsp, err := s.Client().Do(req)
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+
+	if body != nil {
+		require.NoError(t, json.NewDecoder(rsp.Body).Decode(body))
+	}
+
+	return rsp.StatusCode
+}
+
+// postForm sends a form to the token or revocation endpoint
+func (s *oauthServer) postForm(t *testing.T, path string, form url.Values, body any) int {
+	req, err := http.NewRequest(http.MethodPost, s.URL+path, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return s.do(t, req, "", body)
+}
+
+type tokenBody struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func TestOAuth_AuthorizationCodeFlow(t *testing.T) {
+	server := newOAuthServer(t)
+
+	authorization := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"public-client"},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {"users:read"},
+		"state":                 {"xyz"},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	// The consent screen shows the client and the requested scopes
+	var consent struct {
+		Data struct {
+			Client struct {
+				Name string `json:"name"`
+			} `json:"client"`
+			Scopes          []string `json:"scopes"`
+			ConsentRequired bool     `json:"consent_required"`
+		} `json:"data"`
+	}
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/oauth/authorize?"+authorization.Encode(), nil)
+	require.NoError(t, err)
+	status := server.do(t, req, server.userToken, &consent)
+	require.Equal(t, http.StatusOK, status, "Consent status mismatch")
+	assert.Equal(t, "Reporting tool", consent.Data.Client.Name, "Client mismatch")
+	assert.Equal(t, []string{"users:read"}, consent.Data.Scopes, "Scopes mismatch")
+	assert.True(t, consent.Data.ConsentRequired, "Consent mismatch")
+
+	// Approving redirects back to the client with a code
+	decision := map[string]any{"approved": true}
+	for key := range authorization {
+		decision[key] = authorization.Get(key)
+	}
+	decisionBody, err := json.Marshal(decision)
+	require.NoError(t, err)
+
+	var redirect struct {
+		Data struct {
+			RedirectURI string `json:"redirect_uri"`
+		} `json:"data"`
+	}
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/v1/oauth/authorize", strings.NewReader(string(decisionBody)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	status = server.do(t, req, server.userToken, &redirect)
+	require.Equal(t, http.StatusOK, status, "Authorize status mismatch")
+
+	link, err := url.Parse(redirect.Data.RedirectURI)
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", link.Query().Get("state"), "State mismatch")
+	code := link.Query().Get("code")
+	require.NotEmpty(t, code, "Code mismatch")
+
+	exchange := url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {"public-client"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {"wrong-verifier-wrong-verifier-wrong-verifier"},
+	}
+
+	// A wrong verifier uses up the code
+	var failed tokenBody
+	status = server.postForm(t, "/v1/oauth/token", exchange, &failed)
+	assert.Equal(t, http.StatusBadRequest, status, "Wrong verifier status mismatch")
+	assert.Equal(t, "invalid_grant", failed.Error, "Wrong verifier error mismatch")
+
+	// so the flow is started again and the code exchanged with the right verifier
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/v1/oauth/authorize", strings.NewReader(string(decisionBody)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	status = server.do(t, req, server.userToken, &redirect)
+	require.Equal(t, http.StatusOK, status, "Authorize status mismatch")
+	link, err = url.Parse(redirect.Data.RedirectURI)
+	require.NoError(t, err)
+
+	exchange.Set("code", link.Query().Get("code"))
+	exchange.Set("code_verifier", codeVerifier)
+
+	var issued tokenBody
+	status = server.postForm(t, "/v1/oauth/token", exchange, &issued)
+	require.Equal(t, http.StatusOK, status, "Token status mismatch: %s", issued.ErrorDescription)
+	assert.Equal(t, "Bearer", issued.TokenType, "Token type mismatch")
+	assert.Equal(t, "users:read", issued.Scope, "Scope mismatch")
+	assert.InDelta(t, 900, issued.ExpiresIn, 1, "Expiry mismatch")
+
+	// The code cannot be exchanged twice
+	status = server.postForm(t, "/v1/oauth/token", exchange, &failed)
+	assert.Equal(t, http.StatusBadRequest, status, "Reused code status mismatch")
+	assert.Equal(t, "invalid_grant", failed.Error, "Reused code error mismatch")
+
+	// The access token authenticates, but cannot approve authorizations on behalf of the user
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/v1/oauth/authorize?"+authorization.Encode(), nil)
+	require.NoError(t, err)
+	status = server.do(t, req, issued.AccessToken, nil)
+	assert.Equal(t, http.StatusForbidden, status, "Delegated status mismatch")
+
+	// Once revoked the access token no longer authenticates
+	status = server.postForm(t, "/v1/oauth/revoke", url.Values{
+		"client_id": {"public-client"},
+		"token":     {issued.AccessToken},
+	}, nil)
+	assert.Equal(t, http.StatusOK, status, "Revoke status mismatch")
+
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/v1/oauth/authorize?"+authorization.Encode(), nil)
+	require.NoError(t, err)
+	status = server.do(t, req, issued.AccessToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, status, "Revoked status mismatch")
+}
+
+func TestOAuth_ClientCredentials(t *testing.T) {
+	server := newOAuthServer(t)
+
+	testCases := []struct {
+		desc     string
+		clientID string
+		secret   string
+		form     url.Values
+		status   int
+		error    string
+	}{
+		{
+			desc:     "Success",
+			clientID: "confidential-client",
+			secret:   clientSecret,
+			form:     url.Values{"grant_type": {"client_credentials"}},
+			status:   http.StatusOK,
+		},
+		{
+			desc:     "Fail_WrongSecret",
+			clientID: "confidential-client",
+			secret:   "wrong-secret",
+			form:     url.Values{"grant_type": {"client_credentials"}},
+			status:   http.StatusUnauthorized,
+			error:    "invalid_client",
+		},
+		{
+			desc:     "Fail_PublicClient",
+			clientID: "public-client",
+			form:     url.Values{"grant_type": {"client_credentials"}},
+			status:   http.StatusBadRequest,
+			error:    "unauthorized_client",
+		},
+		{
+			desc:     "Fail_ScopeNotAllowed",
+			clientID: "confidential-client",
+			secret:   clientSecret,
+			form:     url.Values{"grant_type": {"client_credentials"}, "scope": {"users:delete"}},
+			status:   http.StatusBadRequest,
+			error:    "invalid_scope",
+		},
+		{
+			desc:     "Fail_UnsupportedGrantType",
+			clientID: "confidential-client",
+			secret:   clientSecret,
+			form:     url.Values{"grant_type": {"password"}},
+			status:   http.StatusBadRequest,
+			error:    "unsupported_grant_type",
+		},
+		{
+			desc:   "Fail_MissingGrantType",
+			form:   url.Values{},
+			status: http.StatusBadRequest,
+			error:  "invalid_request",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/oauth/token", strings.NewReader(tc.form.Encode()))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.clientID != "" {
+				req.SetBasicAuth(url.QueryEscape(tc.clientID), url.QueryEscape(tc.secret))
+			}
+
+			var body tokenBody
+			status := server.do(t, req, "", &body)
+			assert.Equal(t, tc.status, status, "Status mismatch")
+			assert.Equal(t, tc.error, body.Error, "Error mismatch")
+
+			if tc.status == http.StatusOK {
+				assert.NotEmpty(t, body.AccessToken, "Token mismatch")
+				assert.Equal(t, "users:read", body.Scope, "Scope mismatch")
+			}
+		})
+	}
+}
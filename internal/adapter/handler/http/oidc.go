@@ -0,0 +1,126 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// oidcStateCookieName is the cookie binding a single sign-on to the browser that started it
+	oidcStateCookieName = "oidc_state"
+	// oidcStateCookiePath limits the state cookie to the single sign-on routes
+	oidcStateCookiePath = "/v1/users/login/oidc"
+	// oidcStateCookieDuration is how long the state cookie is kept, it matches the lifetime of the state
+	oidcStateCookieDuration = 10 * time.Minute
+)
+
+// OIDCHandler represents the HTTP handler for single sign-on requests,
+// the tokens are also sent as session cookies unless cookies is nil
+type OIDCHandler struct {
+	svc     port.OIDCService
+	cookies *SessionCookies
+}
+
+// NewOIDCHandler creates a new OIDCHandler instance
+func NewOIDCHandler(svc port.OIDCService, cookies *SessionCookies) *OIDCHandler {
+	return &OIDCHandler{
+		svc,
+		cookies,
+	}
+}
+
+// Begin godoc
+//
+//	@Summary		Start a single sign-on
+//	@Description	Returns the url of the identity provider to send the user to, and binds the sign in to the browser with an HttpOnly oidc_state cookie. The user comes back to the configured redirect url with a state and a code, to be exchanged at /users/login/oidc/callback by the same browser within 10 minutes.
+//	@Tags			Users
+//	@Produce		json
+//	@Success		200	{object}	oidcAuthorizationResponse	"Identity provider url"
+//	@Failure		404	{object}	errorResponse				"Single sign-on is not configured"
+//	@Failure		500	{object}	errorResponse				"Internal server error"
+//	@Router			/v1/users/login/oidc [get]
+func (oh *OIDCHandler) Begin(ctx *gin.Context) {
+	authorizationURL, state, err := oh.svc.Begin(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	oh.setStateCookie(ctx, state, int(oidcStateCookieDuration.Seconds()))
+
+	rsp := oidcAuthorizationResponse{
+		AuthorizationURL: authorizationURL,
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// oidcCallbackRequest represents the request body for completing a single sign-on
+type oidcCallbackRequest struct {
+	State string `json:"state" binding:"required" example:"Jm2dT0X8qK6c1hQvZr4yW9bN3sLp7eUa5fGiYoXkR2c"`
+	Code  string `json:"code" binding:"required" example:"SplxlOBeZQQYbYS6WxSbIA"`
+}
+
+// Callback godoc
+//
+//	@Summary		Complete a single sign-on
+//	@Description	Exchanges the state and code the identity provider sent back for an access token and a refresh token. The state must match the oidc_state cookie set when the sign in started. A new identity is linked to the user with the same email, or to a new cashier, only if the identity provider verified the email. Users with two-factor authentication enabled get a challenge token instead, to be exchanged at /users/login/2fa.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		oidcCallbackRequest	true	"Single sign-on callback body"
+//	@Success		200		{object}	authResponse		"Succesfully logged in"
+//	@Failure		400		{object}	errorResponse		"Validation error, invalid state or state not started by this browser"
+//	@Failure		401		{object}	errorResponse		"Identity provider did not confirm the sign in"
+//	@Failure		403		{object}	errorResponse		"Email not verified by the identity provider"
+//	@Failure		404		{object}	errorResponse		"Single sign-on is not configured"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/v1/users/login/oidc/callback [post]
+func (oh *OIDCHandler) Callback(ctx *gin.Context) {
+	var req oidcCallbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	// the state cookie ties the callback to the browser that started the sign in,
+	// so that an attacker cannot log a victim in with the attacker's own state and code
+	cookie, err := ctx.Cookie(oidcStateCookieName)
+	oh.setStateCookie(ctx, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+		handleError(ctx, domain.ErrInvalidSingleSignOnState)
+		return
+	}
+
+	token, err := oh.svc.Complete(ctx, req.State, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	respondWithToken(ctx, oh.cookies, token)
+}
+
+// setStateCookie sends the state cookie with the domain and same site mode of the session cookies, if enabled
+func (oh *OIDCHandler) setStateCookie(ctx *gin.Context, state string, maxAge int) {
+	cookie := &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     oidcStateCookiePath,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if oh.cookies != nil {
+		cookie.Domain = oh.cookies.domain
+		cookie.SameSite = oh.cookies.sameSite
+	}
+
+	http.SetCookie(ctx.Writer, cookie)
+}
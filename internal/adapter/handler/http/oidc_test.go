@@ -0,0 +1,112 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newOIDCServer starts a test server with the single sign-on routes
+func newOIDCServer(t *testing.T, oidcService *mock.MockOIDCService) *httptest.Server {
+	oidcHandler := handler.NewOIDCHandler(oidcService, nil)
+
+	router := gin.New()
+	router.GET("/v1/users/login/oidc", oidcHandler.Begin)
+	router.POST("/v1/users/login/oidc/callback", oidcHandler.Callback)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestOIDCHandler_StateCookie(t *testing.T) {
+	state := "Jm2dT0X8qK6c1hQvZr4yW9bN3sLp7eUa5fGiYoXkR2c"
+	callbackBody := `{"state":"` + state + `","code":"SplxlOBeZQQYbYS6WxSbIA"}`
+	authToken := &domain.AuthToken{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+	}
+
+	t.Run("Begin", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		oidcService := mock.NewMockOIDCService(ctrl)
+		oidcService.EXPECT().
+			Begin(gomock.Any()).
+			Return("https://login.example.com/authorize?state="+state, state, nil)
+		server := newOIDCServer(t, oidcService)
+
+		res, data := doCookieRequest(t, http.MethodGet, server.URL+"/v1/users/login/oidc", "", nil, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "Status mismatch")
+		assert.Equal(t, "https://login.example.com/authorize?state="+state, data["authorization_url"], "URL mismatch")
+
+		cookie := findCookie(res, "oidc_state")
+		require.NotNil(t, cookie, "State cookie missing")
+		assert.Equal(t, state, cookie.Value, "State cookie mismatch")
+		assert.True(t, cookie.HttpOnly, "State cookie readable by scripts")
+		assert.True(t, cookie.Secure, "State cookie not secure")
+		assert.Equal(t, "/v1/users/login/oidc", cookie.Path, "State cookie path mismatch")
+		assert.Equal(t, 600, cookie.MaxAge, "State cookie lifetime mismatch")
+	})
+
+	testCases := []struct {
+		desc    string
+		cookies []*http.Cookie
+		mocks   func(oidcService *mock.MockOIDCService)
+		status  int
+	}{
+		{
+			desc:    "Callback_Success",
+			cookies: []*http.Cookie{{Name: "oidc_state", Value: state}},
+			mocks: func(oidcService *mock.MockOIDCService) {
+				oidcService.EXPECT().
+					Complete(gomock.Any(), gomock.Eq(state), gomock.Eq("SplxlOBeZQQYbYS6WxSbIA")).
+					Return(authToken, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			desc:    "Callback_Fail_MissingCookie",
+			cookies: nil,
+			mocks:   func(oidcService *mock.MockOIDCService) {},
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "Callback_Fail_OtherState",
+			cookies: []*http.Cookie{{Name: "oidc_state", Value: "attacker-state"}},
+			mocks:   func(oidcService *mock.MockOIDCService) {},
+			status:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			oidcService := mock.NewMockOIDCService(ctrl)
+			tc.mocks(oidcService)
+			server := newOIDCServer(t, oidcService)
+
+			res, _ := doCookieRequest(t, http.MethodPost, server.URL+"/v1/users/login/oidc/callback", callbackBody, tc.cookies, nil)
+			assert.Equal(t, tc.status, res.StatusCode, "Status mismatch")
+
+			cookie := findCookie(res, "oidc_state")
+			require.NotNil(t, cookie, "State cookie not cleared")
+			assert.Equal(t, -1, cookie.MaxAge, "State cookie not cleared")
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordResetHandler represents the HTTP handler for password reset requests
+type PasswordResetHandler struct {
+	svc port.PasswordResetService
+}
+
+// NewPasswordResetHandler creates a new PasswordResetHandler instance
+func NewPasswordResetHandler(svc port.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		svc,
+	}
+}
+
+// requestResetRequest represents the request body for requesting a password reset
+type requestResetRequest struct {
+	Email string `json:"email" binding:"required,email" example:"test@example.com"`
+}
+
+// RequestReset godoc
+//
+//	@Summary		Request a password reset
+//	@Description	Emails a single-use password reset link if the email belongs to a user. The response is the same whether or not the email is registered.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requestResetRequest	true	"Request reset body"
+//	@Success		200		{object}	response			"Reset link sent if the email is registered"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/v1/users/password/forgot [post]
+func (ph *PasswordResetHandler) RequestReset(ctx *gin.Context) {
+	var req requestResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := ph.svc.RequestReset(ctx, req.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// completeResetRequest represents the request body for completing a password reset
+type completeResetRequest struct {
+	Token    string `json:"token" binding:"required" example:"h3bRLy6uUeoRC4A8vXW2C8yd6oFJ9XqRVx5mG4hZ6Tk"`
+	Password string `json:"password" binding:"required" example:"Correct4HorseBattery"`
+}
+
+// CompleteReset godoc
+//
+//	@Summary		Complete a password reset
+//	@Description	Sets a new password with the token of a reset link. Every access token, refresh token and session of the user is revoked.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		completeResetRequest	true	"Complete reset body"
+//	@Success		200		{object}	response				"Password reset"
+//	@Failure		400		{object}	errorResponse			"Validation error or invalid link"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/password/reset [post]
+func (ph *PasswordResetHandler) CompleteReset(ctx *gin.Context) {
+	var req completeResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := ph.svc.CompleteReset(ctx, req.Token, req.Password)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
@@ -0,0 +1,93 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPasswordResetHandler_RequestReset(t *testing.T) {
+	user := &domain.User{
+		ID:    1,
+		Name:  "John Doe",
+		Email: "test@example.com",
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the mailer is held until the response is received, so a response that waits for the delivery never comes
+	release := make(chan struct{})
+	delivered := make(chan struct{})
+
+	userRepo := mock.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+		Return(user, nil)
+	resetRepo := mock.NewMockPasswordResetRepository(ctrl)
+	resetRepo.EXPECT().
+		CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+			return token, nil
+		})
+	mailer := mock.NewMockMailer(ctrl)
+	mailer.EXPECT().
+		Send(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *domain.Email) error {
+			defer close(delivered)
+			<-release
+			assert.NoError(t, ctx.Err(), "Delivery canceled with the request")
+			return nil
+		})
+
+	passwordResetService := service.NewPasswordResetService(
+		userRepo,
+		resetRepo,
+		mock.NewMockCacheRepository(ctrl),
+		mailer,
+		mock.NewMockAuthService(ctrl),
+		mock.NewMockPasswordPolicyService(ctrl),
+		mock.NewMockPasswordHasher(ctrl),
+		"http://localhost:5173/reset-password",
+		time.Hour,
+	)
+	passwordResetHandler := handler.NewPasswordResetHandler(passwordResetService)
+
+	router := gin.New()
+	router.POST("/v1/users/password/forgot", passwordResetHandler.RequestReset)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	statuses := make(chan int, 1)
+	go func() {
+		res, err := http.Post(server.URL+"/v1/users/password/forgot", "application/json", strings.NewReader(`{"email":"`+user.Email+`"}`))
+		if err != nil {
+			statuses <- 0
+			return
+		}
+		res.Body.Close()
+		statuses <- res.StatusCode
+	}()
+
+	select {
+	case status := <-statuses:
+		assert.Equal(t, http.StatusOK, status, "Status mismatch")
+	case <-time.After(time.Second):
+		t.Error("Response waited for the mailer")
+	}
+
+	close(release)
+	<-delivered
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// PolicyHandler represents the HTTP handler for authorization policy requests
+type PolicyHandler struct {
+	authz port.Authorizer
+}
+
+// NewPolicyHandler creates a new PolicyHandler instance
+func NewPolicyHandler(authz port.Authorizer) *PolicyHandler {
+	return &PolicyHandler{
+		authz,
+	}
+}
+
+// Reload godoc
+//
+//	@Summary		Reload the authorization policy
+//	@Description	Reloads the authorization policy, the current policy is kept if the new one is invalid
+//	@Tags			Policy
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response		"Policy reloaded"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		422	{object}	errorResponse	"Invalid policy error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/policy/reload [post]
+//	@Security		BearerAuth
+func (ph *PolicyHandler) Reload(ctx *gin.Context) {
+	err := ph.authz.Reload(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// explainResourceRequest represents the resource of a policy dry run
+type explainResourceRequest struct {
+	Type       string            `json:"type" binding:"required" example:"users"`
+	ID         string            `json:"id" example:"3"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// explainRequest represents the request body for a policy dry run
+type explainRequest struct {
+	UserID   uint64                 `json:"user_id" binding:"required" example:"2"`
+	Role     domain.UserRole        `json:"role" binding:"required,user_role" example:"manager"`
+	Scopes   []string               `json:"scopes"`
+	Action   string                 `json:"action" binding:"required" example:"users:update"`
+	Resource explainResourceRequest `json:"resource" binding:"required"`
+}
+
+// Explain godoc
+//
+//	@Summary		Explain an authorization decision
+//	@Description	Evaluates the authorization policy for the given principal, action and resource without performing the action, and explains the outcome of every rule
+//	@Tags			Policy
+//	@Accept			json
+//	@Produce		json
+//	@Param			explainRequest	body		explainRequest		true	"Explain request"
+//	@Success		200				{object}	decisionResponse	"Decision explained"
+//	@Failure		400				{object}	errorResponse		"Validation error"
+//	@Failure		401				{object}	errorResponse		"Unauthorized error"
+//	@Failure		403				{object}	errorResponse		"Forbidden error"
+//	@Failure		500				{object}	errorResponse		"Internal server error"
+//	@Router			/v1/policy/explain [post]
+//	@Security		BearerAuth
+func (ph *PolicyHandler) Explain(ctx *gin.Context) {
+	var req explainRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	principal := &domain.TokenPayload{
+		UserID: req.UserID,
+		Role:   req.Role,
+		Scopes: req.Scopes,
+	}
+	resource := &domain.PolicyResource{
+		Type:       req.Resource.Type,
+		ID:         req.Resource.ID,
+		Attributes: req.Resource.Attributes,
+	}
+
+	decision, err := ph.authz.Can(ctx, principal, req.Action, resource)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newDecisionResponse(decision)
+
+	handleSuccess(ctx, rsp)
+}
@@ -3,11 +3,13 @@ package http
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
 // response represents a response body format
@@ -42,35 +44,327 @@ func newMeta(total, limit, skip uint64) meta {
 	}
 }
 
-// authResponse represents an authentication response body
+// authResponse represents an authentication response body,
+// users with two-factor authentication only get a challenge token on login.
+// When the tokens are sent as session cookies, the body only has the user and the csrf token instead
 type authResponse struct {
-	AccessToken string `json:"token" example:"v2.local.Gdh5kiOTyyaQ3_bNykYDeYHO21Jg2..."`
+	AccessToken       string        `json:"token,omitempty" example:"v2.local.Gdh5kiOTyyaQ3_bNykYDeYHO21Jg2..."`
+	RefreshToken      string        `json:"refresh_token,omitempty" example:"h3bRLy6uUeoRC4A8vXW2C8yd6oFJ9XqRVx5mG4hZ6Tk"`
+	TwoFactorRequired bool          `json:"two_factor_required,omitempty" example:"false"`
+	ChallengeToken    string        `json:"challenge_token,omitempty" example:"Jm2dT0X8qK6c1hQvZr4yW9bN3sLp7eUa5fGiYoXkR2c"`
+	CSRFToken         string        `json:"csrf_token,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	User              *userResponse `json:"user,omitempty"`
 }
 
 // newAuthResponse is a helper function to create a response body for handling authentication data
-func newAuthResponse(token string) authResponse {
+func newAuthResponse(token *domain.AuthToken) authResponse {
 	return authResponse{
-		AccessToken: token,
+		AccessToken:       token.AccessToken,
+		RefreshToken:      token.RefreshToken,
+		TwoFactorRequired: token.ChallengeToken != "",
+		ChallengeToken:    token.ChallengeToken,
+	}
+}
+
+// newSessionAuthResponse is a helper function to create a response body for a session kept in cookies
+func newSessionAuthResponse(token *domain.AuthToken, csrfToken string) authResponse {
+	rsp := authResponse{
+		CSRFToken: csrfToken,
+	}
+	if token.User != nil {
+		user := newUserResponse(token.User)
+		rsp.User = &user
+	}
+
+	return rsp
+}
+
+// totpKeyResponse represents a TOTP enrollment response body
+type totpKeyResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" example:"otpauth://totp/go-ws:test@example.com?algorithm=SHA1&digits=6&issuer=go-ws&period=30&secret=JBSWY3DPEHPK3PXP"`
+}
+
+// newTOTPKeyResponse is a helper function to create a response body for handling TOTP enrollment data
+func newTOTPKeyResponse(key *domain.TOTPKey) totpKeyResponse {
+	return totpKeyResponse{
+		Secret: key.Secret,
+		URI:    key.URI,
+	}
+}
+
+// recoveryCodesResponse represents a recovery codes response body
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3j5f-9xq2m"`
+}
+
+// apiKeyResponse represents an api key response body
+type apiKeyResponse struct {
+	ID         uint64     `json:"id" example:"1"`
+	Name       string     `json:"name" example:"Nightly export"`
+	Prefix     string     `json:"prefix" example:"3f9a1c2b7d4e"`
+	Scopes     []string   `json:"scopes" example:"users:update"`
+	ExpiresAt  *time.Time `json:"expires_at" example:"2030-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at" example:"1970-01-01T00:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at" example:"1970-01-01T00:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newAPIKeyResponse is a helper function to create a response body for handling api key data
+func newAPIKeyResponse(key *domain.APIKey) apiKeyResponse {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return apiKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// createdAPIKeyResponse represents a created api key response body, the key is only shown once
+type createdAPIKeyResponse struct {
+	apiKeyResponse
+	Key string `json:"key" example:"gows_3f9a1c2b7d4e_Jm2dT0X8qK6c1hQvZr4yW9bN3sLp7eUa5fGiYoXkR2c"`
+}
+
+// newCreatedAPIKeyResponse is a helper function to create a response body for handling a created api key
+func newCreatedAPIKeyResponse(key *domain.APIKey, plaintext string) createdAPIKeyResponse {
+	return createdAPIKeyResponse{
+		apiKeyResponse: newAPIKeyResponse(key),
+		Key:            plaintext,
+	}
+}
+
+// sessionResponse represents a session response body
+type sessionResponse struct {
+	ID         uuid.UUID `json:"id" example:"5f0c8f0e-8a3c-4d7e-9f61-2b8f4c1d9e3a"`
+	IP         string    `json:"ip" example:"203.0.113.7"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0"`
+	Current    bool      `json:"current" example:"true"`
+	CreatedAt  time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	LastSeenAt time.Time `json:"last_seen_at" example:"1970-01-01T00:00:00Z"`
+	ExpiresAt  time.Time `json:"expires_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newSessionResponse is a helper function to create a response body for handling session data,
+// current marks the session the request was made from
+func newSessionResponse(session *domain.Session, currentID uuid.UUID) sessionResponse {
+	return sessionResponse{
+		ID:         session.ID,
+		IP:         session.IP,
+		UserAgent:  session.UserAgent,
+		Current:    session.ID == currentID,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
+
+// impersonationResponse represents an impersonation response body
+type impersonationResponse struct {
+	ID             uuid.UUID `json:"id" example:"5f0c8f0e-8a3c-4d7e-9f61-2b8f4c1d9e3a"`
+	UserID         uint64    `json:"user_id" example:"2"`
+	ImpersonatorID uint64    `json:"impersonator_id" example:"1"`
+	Reason         string    `json:"reason" example:"Support ticket #1234"`
+	AccessToken    string    `json:"access_token" example:"v4.local.Gdh5kiOTyyaQ3_bNykYDeYHO21Jg2..."`
+	ExpiresAt      time.Time `json:"expires_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newImpersonationResponse is a helper function to create a response body for handling impersonation data
+func newImpersonationResponse(impersonation *domain.Impersonation, accessToken string) impersonationResponse {
+	return impersonationResponse{
+		ID:             impersonation.ID,
+		UserID:         impersonation.UserID,
+		ImpersonatorID: impersonation.ImpersonatorID,
+		Reason:         impersonation.Reason,
+		AccessToken:    accessToken,
+		ExpiresAt:      impersonation.ExpiresAt,
+	}
+}
+
+// authEventResponse represents a security event response body,
+// the actor and target are omitted when unknown
+type authEventResponse struct {
+	ID        uint64                  `json:"id" example:"1"`
+	Type      domain.AuthEventType    `json:"type" example:"login"`
+	ActorID   uint64                  `json:"actor_id,omitempty" example:"1"`
+	TargetID  uint64                  `json:"target_id,omitempty" example:"1"`
+	Email     string                  `json:"email,omitempty" example:"test@example.com"`
+	IP        string                  `json:"ip" example:"203.0.113.7"`
+	UserAgent string                  `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0"`
+	Outcome   domain.AuthEventOutcome `json:"outcome" example:"failure"`
+	Reason    string                  `json:"reason,omitempty" example:"invalid email or password"`
+	CreatedAt time.Time               `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newAuthEventResponse is a helper function to create a response body for handling security event data
+func newAuthEventResponse(event *domain.AuthEvent) authEventResponse {
+	return authEventResponse{
+		ID:        event.ID,
+		Type:      event.Type,
+		ActorID:   event.ActorID,
+		TargetID:  event.TargetID,
+		Email:     event.Email,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// oauthClientResponse represents an oauth client response body
+type oauthClientResponse struct {
+	ClientID     string    `json:"client_id" example:"yN3sLp7eUa5fGiYoXkR2cA"`
+	Name         string    `json:"name" example:"Reporting tool"`
+	RedirectURIs []string  `json:"redirect_uris" example:"https://reports.example.com/callback"`
+	Scopes       []string  `json:"scopes" example:"users:read"`
+	Confidential bool      `json:"confidential" example:"true"`
+	CreatedAt    time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newOAuthClientResponse is a helper function to create a response body for handling oauth client data
+func newOAuthClientResponse(client *domain.OAuthClient) oauthClientResponse {
+	redirectURIs := client.RedirectURIs
+	if redirectURIs == nil {
+		redirectURIs = []string{}
+	}
+
+	return oauthClientResponse{
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: redirectURIs,
+		Scopes:       client.Scopes,
+		Confidential: client.Confidential,
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+// registeredOAuthClientResponse represents a registered oauth client response body, the secret is only shown once
+type registeredOAuthClientResponse struct {
+	oauthClientResponse
+	ClientSecret string `json:"client_secret,omitempty" example:"Jm2dT0X8qK6c1hQvZr4yW9bN3sLp7eUa5fGiYoXkR2c"`
+}
+
+// newRegisteredOAuthClientResponse is a helper function to create a response body for handling a registered oauth client
+func newRegisteredOAuthClientResponse(client *domain.OAuthClient, secret string) registeredOAuthClientResponse {
+	return registeredOAuthClientResponse{
+		oauthClientResponse: newOAuthClientResponse(client),
+		ClientSecret:        secret,
+	}
+}
+
+// oauthConsentResponse represents an oauth consent screen response body
+type oauthConsentResponse struct {
+	Client          oauthClientResponse `json:"client"`
+	RedirectURI     string              `json:"redirect_uri" example:"https://reports.example.com/callback"`
+	Scopes          []string            `json:"scopes" example:"users:read"`
+	ConsentRequired bool                `json:"consent_required" example:"true"`
+}
+
+// newOAuthConsentResponse is a helper function to create a response body for handling an oauth consent screen
+func newOAuthConsentResponse(prompt *domain.OAuthConsentPrompt) oauthConsentResponse {
+	return oauthConsentResponse{
+		Client:          newOAuthClientResponse(prompt.Client),
+		RedirectURI:     prompt.RedirectURI,
+		Scopes:          prompt.Scopes,
+		ConsentRequired: prompt.ConsentRequired,
+	}
+}
+
+// oauthRedirectResponse represents the redirect uri the user is sent back to the oauth client with
+type oauthRedirectResponse struct {
+	RedirectURI string `json:"redirect_uri" example:"https://reports.example.com/callback?code=h3bRLy6uUeoRC4A8vXW2C8yd6oFJ9XqRVx5mG4hZ6Tk&state=af0ifjsldkj"`
+}
+
+// oidcAuthorizationResponse represents the url of the identity provider a single sign-on starts at
+type oidcAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url" example:"https://login.example.com/authorize?client_id=go-ws&response_type=code&state=Jm2dT0X8qK6c1hQvZr4yW9bN3sLp7eUa5fGiYoXkR2c"`
+}
+
+// oauthTokenResponse represents an oauth token response body as defined by RFC 6749
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token" example:"v4.local.Gdh5kiOTyyaQ3_bNykYDeYHO21Jg2..."`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int64  `json:"expires_in" example:"900"`
+	Scope       string `json:"scope" example:"users:read"`
+}
+
+// newOAuthTokenResponse is a helper function to create a response body for handling an oauth access token
+func newOAuthTokenResponse(token *domain.OAuthToken) oauthTokenResponse {
+	return oauthTokenResponse{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		ExpiresIn:   token.ExpiresIn,
+		Scope:       strings.Join(token.Scopes, " "),
 	}
 }
 
 // userResponse represents a user response body
 type userResponse struct {
-	ID        uint64    `json:"id" example:"1"`
-	Name      string    `json:"name" example:"John Doe"`
-	Email     string    `json:"email" example:"test@example.com"`
-	CreatedAt time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+	ID              uint64          `json:"id" example:"1"`
+	Name            string          `json:"name" example:"John Doe"`
+	Email           string          `json:"email" example:"test@example.com"`
+	Role            domain.UserRole `json:"role" example:"cashier"`
+	EmailVerifiedAt *time.Time      `json:"email_verified_at" example:"1970-01-01T00:00:00Z"`
+	CreatedAt       time.Time       `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	UpdatedAt       time.Time       `json:"updated_at" example:"1970-01-01T00:00:00Z"`
 }
 
 // newUserResponse is a helper function to create a response body for handling user data
 func newUserResponse(user *domain.User) userResponse {
 	return userResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		Name:            user.Name,
+		Email:           user.Email,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+}
+
+// evaluationResponse represents the outcome of a policy rule
+type evaluationResponse struct {
+	RuleID  string              `json:"rule_id" example:"manager-edit-cashiers"`
+	Effect  domain.PolicyEffect `json:"effect" example:"allow"`
+	Matched bool                `json:"matched" example:"true"`
+	Reason  string              `json:"reason" example:"matched"`
+}
+
+// decisionResponse represents an authorization decision response body
+type decisionResponse struct {
+	Allowed     bool                 `json:"allowed" example:"true"`
+	RuleID      string               `json:"rule_id,omitempty" example:"manager-edit-cashiers"`
+	Reason      string               `json:"reason" example:"allowed by rule \"manager-edit-cashiers\""`
+	Evaluations []evaluationResponse `json:"evaluations"`
+}
+
+// newDecisionResponse is a helper function to create a response body for handling authorization decisions
+func newDecisionResponse(decision *domain.PolicyDecision) decisionResponse {
+	evaluations := []evaluationResponse{}
+	for _, evaluation := range decision.Evaluations {
+		evaluations = append(evaluations, evaluationResponse{
+			RuleID:  evaluation.RuleID,
+			Effect:  evaluation.Effect,
+			Matched: evaluation.Matched,
+			Reason:  evaluation.Reason,
+		})
+	}
+
+	return decisionResponse{
+		Allowed:     decision.Allowed,
+		RuleID:      decision.RuleID,
+		Reason:      decision.Reason,
+		Evaluations: evaluations,
 	}
 }
 
@@ -80,13 +374,48 @@ var errorStatusMap = map[error]int{
 	domain.ErrDataNotFound:               http.StatusNotFound,
 	domain.ErrConflictingData:            http.StatusConflict,
 	domain.ErrInvalidCredentials:         http.StatusUnauthorized,
+	domain.ErrInvalidAPIKey:              http.StatusUnauthorized,
+	domain.ErrWeakPassword:               http.StatusBadRequest,
+	domain.ErrInvalidOAuthClient:         http.StatusUnauthorized,
+	domain.ErrUnauthorizedOAuthClient:    http.StatusBadRequest,
+	domain.ErrUnsupportedGrantType:       http.StatusBadRequest,
+	domain.ErrInvalidGrant:               http.StatusBadRequest,
+	domain.ErrInvalidScope:               http.StatusBadRequest,
+	domain.ErrInvalidRedirectURI:         http.StatusBadRequest,
+	domain.ErrInvalidCodeChallenge:       http.StatusBadRequest,
 	domain.ErrUnauthorized:               http.StatusUnauthorized,
 	domain.ErrEmptyAuthorizationHeader:   http.StatusUnauthorized,
 	domain.ErrInvalidAuthorizationHeader: http.StatusUnauthorized,
 	domain.ErrInvalidAuthorizationType:   http.StatusUnauthorized,
+	domain.ErrInvalidClientCertificate:   http.StatusUnauthorized,
+	domain.ErrInvalidCSRFToken:           http.StatusForbidden,
 	domain.ErrInvalidToken:               http.StatusUnauthorized,
 	domain.ErrExpiredToken:               http.StatusUnauthorized,
+	domain.ErrRevokedToken:               http.StatusUnauthorized,
+	domain.ErrInvalidTokenIssuer:         http.StatusUnauthorized,
+	domain.ErrInvalidTokenAudience:       http.StatusUnauthorized,
+	domain.ErrInvalidRefreshToken:        http.StatusUnauthorized,
+	domain.ErrExpiredRefreshToken:        http.StatusUnauthorized,
+	domain.ErrRefreshTokenReused:         http.StatusUnauthorized,
+	domain.ErrInvalidTwoFactorCode:       http.StatusUnauthorized,
+	domain.ErrInvalidLoginChallenge:      http.StatusUnauthorized,
+	domain.ErrTwoFactorEnabled:           http.StatusConflict,
+	domain.ErrTwoFactorNotEnabled:        http.StatusConflict,
+	domain.ErrEmailNotVerified:           http.StatusForbidden,
+	domain.ErrInvalidVerificationToken:   http.StatusBadRequest,
+	domain.ErrInvalidPasswordResetToken:  http.StatusBadRequest,
+	domain.ErrSingleSignOnDisabled:       http.StatusNotFound,
+	domain.ErrInvalidSingleSignOnState:   http.StatusBadRequest,
+	domain.ErrInvalidExternalIdentity:    http.StatusUnauthorized,
+	domain.ErrExternalEmailNotVerified:   http.StatusForbidden,
+	domain.ErrAccountLocked:              http.StatusTooManyRequests,
+	domain.ErrRateLimited:                http.StatusTooManyRequests,
 	domain.ErrForbidden:                  http.StatusForbidden,
+	domain.ErrImpersonationForbidden:     http.StatusForbidden,
+	domain.ErrClientForbidden:            http.StatusForbidden,
+	domain.ErrAPIKeyForbidden:            http.StatusForbidden,
+	domain.ErrInvalidPolicy:              http.StatusUnprocessableEntity,
+	domain.ErrPolicyNotLoaded:            http.StatusServiceUnavailable,
 	domain.ErrNoUpdatedData:              http.StatusBadRequest,
 	domain.ErrInsufficientStock:          http.StatusBadRequest,
 	domain.ErrInsufficientPayment:        http.StatusBadRequest,
@@ -99,13 +428,26 @@ func validationError(ctx *gin.Context, err error) {
 	ctx.JSON(http.StatusBadRequest, errRsp)
 }
 
-// handleError determines the status code of an error and returns a JSON response with the error message and status code
-func handleError(ctx *gin.Context, err error) {
+// errorStatusCode determines the status code of an error, wrapped errors get the status code of the error they wrap
+func errorStatusCode(err error) int {
 	statusCode, ok := errorStatusMap[err]
-	if !ok {
-		statusCode = http.StatusInternalServerError
+	if ok {
+		return statusCode
+	}
+
+	for mappedErr, statusCode := range errorStatusMap {
+		if errors.Is(err, mappedErr) {
+			return statusCode
+		}
 	}
 
+	return http.StatusInternalServerError
+}
+
+// handleError determines the status code of an error and returns a JSON response with the error message and status code
+func handleError(ctx *gin.Context, err error) {
+	statusCode := errorStatusCode(err)
+
 	errMsg := parseError(err)
 	errRsp := newErrorResponse(errMsg)
 	ctx.JSON(statusCode, errRsp)
@@ -113,10 +455,7 @@ func handleError(ctx *gin.Context, err error) {
 
 // handleAbort sends an error response and aborts the request with the specified status code and error message
 func handleAbort(ctx *gin.Context, err error) {
-	statusCode, ok := errorStatusMap[err]
-	if !ok {
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := errorStatusCode(err)
 
 	errMsg := parseError(err)
 	errRsp := newErrorResponse(errMsg)
@@ -126,11 +465,14 @@ func handleAbort(ctx *gin.Context, err error) {
 // parseError parses error messages from the error object and returns a slice of error messages
 func parseError(err error) []string {
 	var errMsgs []string
+	var policyErr *domain.PasswordPolicyError
 
 	if errors.As(err, &validator.ValidationErrors{}) {
 		for _, err := range err.(validator.ValidationErrors) {
 			errMsgs = append(errMsgs, err.Error())
 		}
+	} else if errors.As(err, &policyErr) {
+		errMsgs = append(errMsgs, policyErr.Violations...)
 	} else {
 		errMsgs = append(errMsgs, err.Error())
 	}
@@ -152,6 +494,53 @@ func newErrorResponse(errMsgs []string) errorResponse {
 	}
 }
 
+// oauthErrorCodeMap is a map of defined error messages and their corresponding oauth error codes
+var oauthErrorCodeMap = map[error]string{
+	domain.ErrInvalidOAuthClient:      "invalid_client",
+	domain.ErrUnauthorizedOAuthClient: "unauthorized_client",
+	domain.ErrUnsupportedGrantType:    "unsupported_grant_type",
+	domain.ErrInvalidGrant:            "invalid_grant",
+	domain.ErrInvalidScope:            "invalid_scope",
+	domain.ErrInvalidCodeChallenge:    "invalid_request",
+}
+
+// oauthErrorResponse represents an oauth error response body as defined by RFC 6749
+type oauthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_grant"`
+	ErrorDescription string `json:"error_description" example:"authorization code is invalid or has expired"`
+}
+
+// oauthValidationError sends an oauth error response for a malformed token or revocation request
+func oauthValidationError(ctx *gin.Context, err error) {
+	ctx.JSON(http.StatusBadRequest, oauthErrorResponse{
+		Error:            "invalid_request",
+		ErrorDescription: strings.Join(parseError(err), ", "),
+	})
+}
+
+// handleOAuthError sends an oauth error response, clients that failed to authenticate are asked for HTTP Basic credentials
+func handleOAuthError(ctx *gin.Context, err error) {
+	code, ok := oauthErrorCodeMap[err]
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse{
+			Error:            "server_error",
+			ErrorDescription: err.Error(),
+		})
+		return
+	}
+
+	statusCode := http.StatusBadRequest
+	if err == domain.ErrInvalidOAuthClient {
+		statusCode = http.StatusUnauthorized
+		ctx.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+
+	ctx.JSON(statusCode, oauthErrorResponse{
+		Error:            code,
+		ErrorDescription: err.Error(),
+	})
+}
+
 // handleSuccess sends a success response with the specified status code and optional data
 func handleSuccess(ctx *gin.Context, data any) {
 	rsp := newResponse(true, "Success", data)
@@ -1,13 +1,21 @@
 package http
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"os"
 	"strings"
 
 	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/cidmiranda/go-ws/internal/core/port"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	sloggin "github.com/samber/slog-gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -16,14 +24,28 @@ import (
 // Router is a wrapper for HTTP router
 type Router struct {
 	*gin.Engine
+	config *config.HTTP
 }
 
 // NewRouter creates a new HTTP router
 func NewRouter(
 	config *config.HTTP,
-	token port.TokenService,
+	authenticator Authenticator,
+	authorizer port.Authorizer,
+	rateLimiter port.RateLimiter,
+	impersonationService port.ImpersonationService,
 	userHandler UserHandler,
 	authHandler AuthHandler,
+	keyHandler KeyHandler,
+	policyHandler PolicyHandler,
+	twoFactorHandler TwoFactorHandler,
+	passwordResetHandler PasswordResetHandler,
+	apiKeyHandler APIKeyHandler,
+	oauthHandler OAuthHandler,
+	oidcHandler OIDCHandler,
+	sessionHandler SessionHandler,
+	impersonationHandler ImpersonationHandler,
+	auditHandler AuditHandler,
 ) (*Router, error) {
 	// Disable debug mode in production
 	if config.Env == "production" {
@@ -35,36 +57,200 @@ func NewRouter(
 	allowedOrigins := config.AllowedOrigins
 	originsList := strings.Split(allowedOrigins, ",")
 	ginConfig.AllowOrigins = originsList
+	ginConfig.ExposeHeaders = []string{
+		"RateLimit-Policy",
+		"RateLimit-Limit",
+		"RateLimit-Remaining",
+		"RateLimit-Reset",
+		"Retry-After",
+	}
+
+	// Browsers only send the session cookies and the csrf header cross-origin when credentials are allowed
+	if config.SessionCookie == "true" {
+		ginConfig.AllowCredentials = true
+		ginConfig.AddAllowHeaders(csrfHeaderKey)
+	}
 
 	router := gin.New()
-	router.Use(sloggin.New(slog.Default()), gin.Recovery(), cors.New(ginConfig))
+	router.Use(sloggin.New(slog.Default()), gin.Recovery(), cors.New(ginConfig), clientInfoMiddleware())
+
+	// Let handlers pass the gin context to services while keeping the request context values
+	router.ContextWithFallback = true
+
+	// Only trust the client IP forwarded by the configured proxies
+	var trustedProxies []string
+	if config.TrustedProxies != "" {
+		trustedProxies = strings.Split(config.TrustedProxies, ",")
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
+	// Custom validators
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if ok {
+		if err := v.RegisterValidation("user_role", userRoleValidator); err != nil {
+			return nil, err
+		}
+	}
+
+	// Rate limits
+	publicLimit, err := parseRateLimit("public", config.RateLimitPublic)
+	if err != nil {
+		return nil, err
+	}
+	authenticatedLimit, err := parseRateLimit("authenticated", config.RateLimitAuthenticated)
+	if err != nil {
+		return nil, err
+	}
+	clientLimit, err := parseRateLimit("client", config.RateLimitClient)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := authMiddleware(authenticator)
+	audit := impersonationAuditMiddleware(impersonationService)
+	noImpersonation := denyImpersonation()
+	userOnly := requireUser()
+	publicRateLimit := rateLimitMiddleware(rateLimiter, publicLimit, rateLimitByIP)
+	authenticatedRateLimit := rateLimitMiddleware(rateLimiter, authenticatedLimit, rateLimitByUser)
+	// Limits every client IP before authenticating, so that floods of invalid credentials are limited too
+	clientRateLimit := rateLimitMiddleware(rateLimiter, clientLimit, rateLimitByIP)
 
 	// Swagger
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Public key discovery
+	router.GET("/.well-known/paseto-keys", keyHandler.PasetoKeys)
+	router.GET("/.well-known/jwks.json", keyHandler.JWKS)
+
 	v1 := router.Group("/v1")
 	{
 		user := v1.Group("/users")
 		{
-			user.POST("/", userHandler.Register)
-			user.POST("/login", authHandler.Login)
+			user.POST("/", publicRateLimit, userHandler.Register)
+			user.POST("/login", publicRateLimit, authHandler.Login)
+			user.POST("/login/2fa", publicRateLimit, authHandler.LoginTwoFactor)
+			user.GET("/login/oidc", publicRateLimit, oidcHandler.Begin)
+			user.POST("/login/oidc/callback", publicRateLimit, oidcHandler.Callback)
+			user.POST("/refresh", publicRateLimit, authHandler.Refresh)
+			user.GET("/verify", publicRateLimit, userHandler.VerifyEmail)
+			user.POST("/verify/resend", publicRateLimit, userHandler.ResendVerification)
+			user.POST("/password/forgot", publicRateLimit, passwordResetHandler.RequestReset)
+			user.POST("/password/reset", publicRateLimit, passwordResetHandler.CompleteReset)
 
-			authUser := user.Group("/").Use(authMiddleware(token))
+			authUser := user.Group("/").Use(clientRateLimit, auth, audit, authenticatedRateLimit, userOnly)
 			{
-				authUser.GET("/", userHandler.ListUsers)
-				authUser.GET("/:id", userHandler.GetUser)
+				authUser.POST("/logout", authHandler.Logout)
+				authUser.GET("/me", requireScope("users:read"), userHandler.GetMe)
+				authUser.PATCH("/me", userHandler.UpdateMe)
+				authUser.DELETE("/me", userHandler.DeleteMe)
+				authUser.GET("/me/sessions", requireScope("sessions:read"), sessionHandler.ListSessions)
+				authUser.DELETE("/me/sessions/:id", noImpersonation, requireScope("sessions:manage"), sessionHandler.RevokeSession)
+				authUser.POST("/2fa/enroll", noImpersonation, requireScope("two_factor:manage"), twoFactorHandler.Enroll)
+				authUser.POST("/2fa/confirm", noImpersonation, requireScope("two_factor:manage"), twoFactorHandler.Confirm)
+				authUser.POST("/2fa/disable", noImpersonation, requireScope("two_factor:manage"), twoFactorHandler.Disable)
+				authUser.POST("/2fa/recovery-codes", noImpersonation, requireScope("two_factor:manage"), twoFactorHandler.RegenerateRecoveryCodes)
+				authUser.POST("/unlock", authorize(authorizer, "users:unlock", "users"), authHandler.Unlock)
+				authUser.GET("/", requireRole(domain.Admin, domain.Manager), requireScope("users:read"), userHandler.ListUsers)
+				authUser.GET("/:id", requireRole(domain.Admin, domain.Manager), requireScope("users:read"), userHandler.GetUser)
+				authUser.POST("/:id/impersonate", authorize(authorizer, "users:impersonate", "users"), impersonationHandler.Impersonate)
 				authUser.PUT("/:id", userHandler.UpdateUser)
 				authUser.DELETE("/:id", userHandler.DeleteUser)
 			}
 		}
+		apiKey := v1.Group("/api-keys").Use(clientRateLimit, auth, audit, authenticatedRateLimit, userOnly, noImpersonation, requireScope("api_keys:manage"))
+		{
+			apiKey.POST("/", apiKeyHandler.CreateAPIKey)
+			apiKey.GET("/", apiKeyHandler.ListAPIKeys)
+			apiKey.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+		}
+		oauth := v1.Group("/oauth")
+		{
+			oauth.POST("/token", publicRateLimit, oauthHandler.Token)
+			oauth.POST("/revoke", publicRateLimit, oauthHandler.Revoke)
+
+			authOAuth := oauth.Group("/").Use(clientRateLimit, auth, audit, authenticatedRateLimit, userOnly)
+			{
+				authOAuth.GET("/authorize", noImpersonation, oauthHandler.PrepareAuthorization)
+				authOAuth.POST("/authorize", noImpersonation, oauthHandler.Authorize)
+			}
+
+			client := oauth.Group("/clients").Use(
+				clientRateLimit,
+				auth,
+				audit,
+				authenticatedRateLimit,
+				userOnly,
+				noImpersonation,
+				authorize(authorizer, "oauth_clients:manage", "oauth_clients"),
+			)
+			{
+				client.POST("/", oauthHandler.RegisterClient)
+				client.GET("/", oauthHandler.ListClients)
+				client.DELETE("/:client_id", oauthHandler.DeleteClient)
+			}
+		}
+		authEvent := v1.Group("/auth-events").Use(
+			clientRateLimit,
+			auth,
+			audit,
+			authenticatedRateLimit,
+			authorize(authorizer, "auth_events:read", "auth_events"),
+		)
+		{
+			authEvent.GET("/", auditHandler.ListAuthEvents)
+		}
+		policy := v1.Group("/policy").Use(
+			clientRateLimit,
+			auth,
+			audit,
+			authenticatedRateLimit,
+			authorize(authorizer, "policy:manage", "policy"),
+		)
+		{
+			policy.POST("/reload", policyHandler.Reload)
+			policy.POST("/explain", policyHandler.Explain)
+		}
 	}
 
 	return &Router{
 		router,
+		config,
 	}, nil
 }
 
-// Serve starts the HTTP server
+// Serve starts the HTTP server, over TLS when a certificate is configured.
+// Clients may then authenticate with a certificate issued by the configured client CA
 func (r *Router) Serve(listenAddr string) error {
-	return r.Run(listenAddr)
+	if r.config.TLSCertFile == "" {
+		return r.Run(listenAddr)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.config.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	server := &http.Server{
+		Addr:      listenAddr,
+		Handler:   r.Engine,
+		TLSConfig: tlsConfig,
+	}
+
+	return server.ListenAndServeTLS(r.config.TLSCertFile, r.config.TLSKeyFile)
 }
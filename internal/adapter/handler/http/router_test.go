@@ -0,0 +1,94 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRouter_ClientRateLimit(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		mocks func(
+			authService *mock.MockAuthService,
+			rateLimiter *mock.MockRateLimiter,
+		)
+		status int
+	}{
+		{
+			desc: "Allowed",
+			mocks: func(authService *mock.MockAuthService, rateLimiter *mock.MockRateLimiter) {
+				rateLimiter.EXPECT().
+					Allow(gomock.Any(), gomock.Eq("ip:192.0.2.1"), gomock.Any()).
+					Return(&domain.RateLimitResult{Allowed: true, Limit: 1, Remaining: 0}, nil)
+				authService.EXPECT().
+					VerifyToken(gomock.Any(), gomock.Eq("invalid-token")).
+					Return(nil, domain.ErrInvalidToken)
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc: "Limited_BeforeAuthentication",
+			mocks: func(authService *mock.MockAuthService, rateLimiter *mock.MockRateLimiter) {
+				rateLimiter.EXPECT().
+					Allow(gomock.Any(), gomock.Eq("ip:192.0.2.1"), gomock.Any()).
+					Return(&domain.RateLimitResult{Allowed: false, Limit: 1, RetryAfter: time.Minute}, nil)
+			},
+			status: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authService := mock.NewMockAuthService(ctrl)
+			rateLimiter := mock.NewMockRateLimiter(ctrl)
+			tc.mocks(authService, rateLimiter)
+
+			router, err := handler.NewRouter(
+				&config.HTTP{
+					Env:             "test",
+					AllowedOrigins:  "http://localhost:5173",
+					RateLimitClient: "1/1m",
+				},
+				handler.NewAuthenticatorChain(handler.NewBearerAuthenticator(authService)),
+				nil,
+				rateLimiter,
+				nil,
+				handler.UserHandler{},
+				handler.AuthHandler{},
+				handler.KeyHandler{},
+				handler.PolicyHandler{},
+				handler.TwoFactorHandler{},
+				handler.PasswordResetHandler{},
+				handler.APIKeyHandler{},
+				handler.OAuthHandler{},
+				handler.OIDCHandler{},
+				handler.SessionHandler{},
+				handler.ImpersonationHandler{},
+				handler.AuditHandler{},
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/users/me", nil)
+			req.Header.Set("Authorization", "Bearer invalid-token")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tc.status, rec.Code, "Status mismatch")
+		})
+	}
+}
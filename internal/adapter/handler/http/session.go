@@ -0,0 +1,88 @@
+package http
+
+import (
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SessionHandler represents the HTTP handler for session-related requests
+type SessionHandler struct {
+	svc port.SessionService
+}
+
+// NewSessionHandler creates a new SessionHandler instance
+func NewSessionHandler(svc port.SessionService) *SessionHandler {
+	return &SessionHandler{
+		svc,
+	}
+}
+
+// ListSessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	Lists where the authenticated user is logged in, most recently seen first. A session starts with a login and stays active while its refresh token is refreshed, the last seen time is the last request made with the session, recorded at most once a minute.
+//	@Tags			Sessions
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	[]sessionResponse	"Sessions displayed"
+//	@Failure		401	{object}	errorResponse		"Unauthorized error"
+//	@Failure		403	{object}	errorResponse		"Forbidden error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/v1/users/me/sessions [get]
+//	@Security		BearerAuth
+func (sh *SessionHandler) ListSessions(ctx *gin.Context) {
+	payload := getPrincipal(ctx)
+
+	sessions, err := sh.svc.ListSessions(ctx, payload.UserID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := []sessionResponse{}
+	for _, session := range sessions {
+		rsp = append(rsp, newSessionResponse(&session, payload.SessionID))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// revokeSessionRequest represents the request body for revoking a session
+type revokeSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid" example:"5f0c8f0e-8a3c-4d7e-9f61-2b8f4c1d9e3a"`
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revoke a session
+//	@Description	Logs the authenticated user out of one of their sessions. Its refresh token stops working and its access tokens are rejected immediately.
+//	@Tags			Sessions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Session ID"
+//	@Success		200	{object}	response		"Session revoked"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me/sessions/{id} [delete]
+//	@Security		BearerAuth
+func (sh *SessionHandler) RevokeSession(ctx *gin.Context) {
+	var req revokeSessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	err := sh.svc.RevokeSession(ctx, payload.UserID, uuid.MustParse(req.ID))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler represents the HTTP handler for two-factor authentication requests
+type TwoFactorHandler struct {
+	svc port.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new TwoFactorHandler instance
+func NewTwoFactorHandler(svc port.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		svc,
+	}
+}
+
+// twoFactorCodeRequest represents the request body carrying a two-factor code
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// Enroll godoc
+//
+//	@Summary		Enroll in two-factor authentication
+//	@Description	Generates a new TOTP secret and its otpauth URI to be added to an authenticator app. Two-factor authentication is enabled once the secret is confirmed.
+//	@Tags			Two-factor
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	totpKeyResponse	"TOTP secret generated"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		409	{object}	errorResponse	"Two-factor authentication already enabled"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/2fa/enroll [post]
+//	@Security		BearerAuth
+func (th *TwoFactorHandler) Enroll(ctx *gin.Context) {
+	payload := getPrincipal(ctx)
+
+	key, err := th.svc.Enroll(ctx, payload.UserID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newTOTPKeyResponse(key)
+
+	handleSuccess(ctx, rsp)
+}
+
+// Confirm godoc
+//
+//	@Summary		Confirm two-factor authentication
+//	@Description	Enables two-factor authentication with a code of the enrolled secret and returns one-time recovery codes, which are only shown once
+//	@Tags			Two-factor
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		twoFactorCodeRequest	true	"Confirm request body"
+//	@Success		200		{object}	recoveryCodesResponse	"Two-factor authentication enabled"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		409		{object}	errorResponse			"Two-factor authentication not enrolled or already enabled"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/2fa/confirm [post]
+//	@Security		BearerAuth
+func (th *TwoFactorHandler) Confirm(ctx *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	codes, err := th.svc.Confirm(ctx, payload.UserID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := recoveryCodesResponse{codes}
+
+	handleSuccess(ctx, rsp)
+}
+
+// Disable godoc
+//
+//	@Summary		Disable two-factor authentication
+//	@Description	Disables two-factor authentication with a TOTP code or an unused recovery code
+//	@Tags			Two-factor
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		twoFactorCodeRequest	true	"Disable request body"
+//	@Success		200		{object}	response				"Two-factor authentication disabled"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		409		{object}	errorResponse			"Two-factor authentication not enabled"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/2fa/disable [post]
+//	@Security		BearerAuth
+func (th *TwoFactorHandler) Disable(ctx *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	err := th.svc.Disable(ctx, payload.UserID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// RegenerateRecoveryCodes godoc
+//
+//	@Summary		Regenerate recovery codes
+//	@Description	Replaces the recovery codes with new ones after verifying a TOTP code or an unused recovery code
+//	@Tags			Two-factor
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		twoFactorCodeRequest	true	"Regenerate request body"
+//	@Success		200		{object}	recoveryCodesResponse	"Recovery codes regenerated"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		409		{object}	errorResponse			"Two-factor authentication not enabled"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/2fa/recovery-codes [post]
+//	@Security		BearerAuth
+func (th *TwoFactorHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getPrincipal(ctx)
+
+	codes, err := th.svc.RegenerateRecoveryCodes(ctx, payload.UserID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := recoveryCodesResponse{codes}
+
+	handleSuccess(ctx, rsp)
+}
@@ -8,13 +8,15 @@ import (
 
 // UserHandler represents the HTTP handler for user-related requests
 type UserHandler struct {
-	svc port.UserService
+	svc      port.UserService
+	verifier port.VerificationService
 }
 
 // NewUserHandler creates a new UserHandler instance
-func NewUserHandler(svc port.UserService) *UserHandler {
+func NewUserHandler(svc port.UserService, verifier port.VerificationService) *UserHandler {
 	return &UserHandler{
 		svc,
+		verifier,
 	}
 }
 
@@ -22,13 +24,13 @@ func NewUserHandler(svc port.UserService) *UserHandler {
 type registerRequest struct {
 	Name     string `json:"name" binding:"required" example:"John Doe"`
 	Email    string `json:"email" binding:"required,email" example:"test@example.com"`
-	Password string `json:"password" binding:"required,min=8" example:"12345678"`
+	Password string `json:"password" binding:"required" example:"Correct4HorseBattery"`
 }
 
 // Register godoc
 //
 //	@Summary		Register a new user
-//	@Description	create a new user account with default role "cashier"
+//	@Description	create a new user account with default role "cashier" and send a link to verify the email address
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -39,7 +41,7 @@ type registerRequest struct {
 //	@Failure		404				{object}	errorResponse	"Data not found error"
 //	@Failure		409				{object}	errorResponse	"Data conflict error"
 //	@Failure		500				{object}	errorResponse	"Internal server error"
-//	@Router			/users [post]
+//	@Router			/v1/users [post]
 func (uh *UserHandler) Register(ctx *gin.Context) {
 	var req registerRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -64,6 +66,72 @@ func (uh *UserHandler) Register(ctx *gin.Context) {
 	handleSuccess(ctx, rsp)
 }
 
+// verifyEmailRequest represents the request query for verifying an email address
+type verifyEmailRequest struct {
+	Token string `form:"token" binding:"required" example:"MTo0ZXN0QGV4YW1wbGUuY29tOjE3MDAwMDAwMDA.c2lnbmF0dXJl"`
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Verify an email address
+//	@Description	Verifies the email address of a user with the signed token of the link sent on registration
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	query		string			true	"Verification token"
+//	@Success		200		{object}	response		"Email verified"
+//	@Failure		400		{object}	errorResponse	"Invalid or expired link error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/verify [get]
+func (uh *UserHandler) VerifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := uh.verifier.Verify(ctx, req.Token)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// resendVerificationRequest represents the request body for resending an email verification link
+type resendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"test@example.com"`
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Resend the email verification link
+//	@Description	Sends a new verification link if the email belongs to an unverified user. The response is the same whether or not the email is registered.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		resendVerificationRequest	true	"Resend verification request body"
+//	@Success		200		{object}	response					"Verification link sent if needed"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/v1/users/verify/resend [post]
+func (uh *UserHandler) ResendVerification(ctx *gin.Context) {
+	var req resendVerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := uh.verifier.ResendVerification(ctx, req.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
 // listUsersRequest represents the request body for listing users
 type listUsersRequest struct {
 	Skip  uint64 `form:"skip" binding:"required,min=0" example:"0"`
@@ -73,7 +141,7 @@ type listUsersRequest struct {
 // ListUsers godoc
 //
 //	@Summary		List users
-//	@Description	List users with pagination
+//	@Description	List users with pagination, only admins and managers are allowed
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -81,8 +149,10 @@ type listUsersRequest struct {
 //	@Param			limit	query		uint64			true	"Limit"
 //	@Success		200		{object}	meta			"Users displayed"
 //	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
 //	@Failure		500		{object}	errorResponse	"Internal server error"
-//	@Router			/users [get]
+//	@Router			/v1/users [get]
 //	@Security		BearerAuth
 func (uh *UserHandler) ListUsers(ctx *gin.Context) {
 	var req listUsersRequest
@@ -118,16 +188,18 @@ type getUserRequest struct {
 // GetUser godoc
 //
 //	@Summary		Get a user
-//	@Description	Get a user by id
+//	@Description	Get a user by id, only admins and managers are allowed
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		uint64			true	"User ID"
 //	@Success		200	{object}	userResponse	"User displayed"
 //	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
 //	@Failure		404	{object}	errorResponse	"Data not found error"
 //	@Failure		500	{object}	errorResponse	"Internal server error"
-//	@Router			/users/{id} [get]
+//	@Router			/v1/users/{id} [get]
 //	@Security		BearerAuth
 func (uh *UserHandler) GetUser(ctx *gin.Context) {
 	var req getUserRequest
@@ -149,15 +221,16 @@ func (uh *UserHandler) GetUser(ctx *gin.Context) {
 
 // updateUserRequest represents the request body for updating a user
 type updateUserRequest struct {
-	Name     string `json:"name" binding:"omitempty,required" example:"John Doe"`
-	Email    string `json:"email" binding:"omitempty,required,email" example:"test@example.com"`
-	Password string `json:"password" binding:"omitempty,required,min=8" example:"12345678"`
+	Name     string          `json:"name" binding:"omitempty,required" example:"John Doe"`
+	Email    string          `json:"email" binding:"omitempty,required,email" example:"test@example.com"`
+	Password string          `json:"password" binding:"omitempty,required" example:"Correct4HorseBattery"`
+	Role     domain.UserRole `json:"role" binding:"omitempty,required,user_role" example:"manager"`
 }
 
 // UpdateUser godoc
 //
 //	@Summary		Update a user
-//	@Description	Update a user's name, email, password, or role by id
+//	@Description	Update a user's name, email, password, or role by id, users may only update themselves unless they are admins
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -169,7 +242,7 @@ type updateUserRequest struct {
 //	@Failure		403					{object}	errorResponse		"Forbidden error"
 //	@Failure		404					{object}	errorResponse		"Data not found error"
 //	@Failure		500					{object}	errorResponse		"Internal server error"
-//	@Router			/users/{id} [put]
+//	@Router			/v1/users/{id} [put]
 //	@Security		BearerAuth
 func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 	var req updateUserRequest
@@ -190,9 +263,12 @@ func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
+		Role:     req.Role,
 	}
 
-	_, err = uh.svc.UpdateUser(ctx, &user)
+	authPayload := getPrincipal(ctx)
+
+	_, err = uh.svc.UpdateUser(ctx, authPayload, &user)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -211,7 +287,7 @@ type deleteUserRequest struct {
 // DeleteUser godoc
 //
 //	@Summary		Delete a user
-//	@Description	Delete a user by id
+//	@Description	Delete a user by id, users may only delete themselves unless they are admins
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -222,7 +298,7 @@ type deleteUserRequest struct {
 //	@Failure		403	{object}	errorResponse	"Forbidden error"
 //	@Failure		404	{object}	errorResponse	"Data not found error"
 //	@Failure		500	{object}	errorResponse	"Internal server error"
-//	@Router			/users/{id} [delete]
+//	@Router			/v1/users/{id} [delete]
 //	@Security		BearerAuth
 func (uh *UserHandler) DeleteUser(ctx *gin.Context) {
 	var req deleteUserRequest
@@ -231,7 +307,113 @@ func (uh *UserHandler) DeleteUser(ctx *gin.Context) {
 		return
 	}
 
-	err := uh.svc.DeleteUser(ctx, req.ID)
+	authPayload := getPrincipal(ctx)
+
+	err := uh.svc.DeleteUser(ctx, authPayload, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// GetMe godoc
+//
+//	@Summary		Get the authenticated user
+//	@Description	Get the user the access token or api key belongs to
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	userResponse	"User displayed"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me [get]
+//	@Security		BearerAuth
+func (uh *UserHandler) GetMe(ctx *gin.Context) {
+	principal := getPrincipal(ctx)
+
+	user, err := uh.svc.GetUser(ctx, principal.UserID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newUserResponse(user)
+
+	handleSuccess(ctx, rsp)
+}
+
+// updateMeRequest represents the request body for updating the authenticated user
+type updateMeRequest struct {
+	Name     string `json:"name" binding:"omitempty,required" example:"John Doe"`
+	Email    string `json:"email" binding:"omitempty,required,email" example:"test@example.com"`
+	Password string `json:"password" binding:"omitempty,required" example:"Correct4HorseBattery"`
+}
+
+// UpdateMe godoc
+//
+//	@Summary		Update the authenticated user
+//	@Description	Update the name, email, or password of the user the access token or api key belongs to
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			updateMeRequest	body		updateMeRequest	true	"Update me request"
+//	@Success		200				{object}	userResponse	"User updated"
+//	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		401				{object}	errorResponse	"Unauthorized error"
+//	@Failure		403				{object}	errorResponse	"Forbidden error"
+//	@Failure		404				{object}	errorResponse	"Data not found error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me [patch]
+//	@Security		BearerAuth
+func (uh *UserHandler) UpdateMe(ctx *gin.Context) {
+	var req updateMeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	principal := getPrincipal(ctx)
+
+	user := domain.User{
+		ID:       principal.UserID,
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+	}
+
+	updatedUser, err := uh.svc.UpdateUser(ctx, principal, &user)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newUserResponse(updatedUser)
+
+	handleSuccess(ctx, rsp)
+}
+
+// DeleteMe godoc
+//
+//	@Summary		Delete the authenticated user
+//	@Description	Delete the user the access token or api key belongs to
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response		"User deleted"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me [delete]
+//	@Security		BearerAuth
+func (uh *UserHandler) DeleteMe(ctx *gin.Context) {
+	principal := getPrincipal(ctx)
+
+	err := uh.svc.DeleteUser(ctx, principal, principal.UserID)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -0,0 +1,264 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	handler "github.com/cidmiranda/go-ws/internal/adapter/handler/http"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const userToken = "user-token"
+
+// newUserServer starts a test server whose bearer token authenticates the given principal,
+// requests made while impersonating are recorded with the impersonation service
+func newUserServer(
+	t *testing.T,
+	principal *domain.TokenPayload,
+	userService *mock.MockUserService,
+	impersonationService *mock.MockImpersonationService,
+) *httptest.Server {
+	ctrl := gomock.NewController(t)
+
+	authService := mock.NewMockAuthService(ctrl)
+	authService.EXPECT().
+		VerifyToken(gomock.Any(), gomock.Eq(userToken)).
+		Return(principal, nil).
+		AnyTimes()
+
+	router, err := handler.NewRouter(
+		&config.HTTP{
+			Env:            "test",
+			AllowedOrigins: "http://localhost:5173",
+		},
+		handler.NewAuthenticatorChain(handler.NewBearerAuthenticator(authService)),
+		nil,
+		nil,
+		impersonationService,
+		*handler.NewUserHandler(userService, nil),
+		handler.AuthHandler{},
+		handler.KeyHandler{},
+		handler.PolicyHandler{},
+		handler.TwoFactorHandler{},
+		handler.PasswordResetHandler{},
+		handler.APIKeyHandler{},
+		handler.OAuthHandler{},
+		handler.OIDCHandler{},
+		handler.SessionHandler{},
+		handler.ImpersonationHandler{},
+		handler.AuditHandler{},
+	)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// doUserRequest sends a request authenticated with the user token and decodes the response data
+func doUserRequest(t *testing.T, method, url, body string) (int, map[string]any) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+userToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var rsp struct {
+		Data map[string]any `json:"data"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&rsp)
+	require.NoError(t, err)
+
+	return res.StatusCode, rsp.Data
+}
+
+func TestUserHandler_Me(t *testing.T) {
+	principal := &domain.TokenPayload{
+		UserID: 1,
+		Role:   domain.Cashier,
+	}
+	user := &domain.User{
+		ID:    1,
+		Name:  "John Doe",
+		Email: "test@example.com",
+		Role:  domain.Cashier,
+	}
+
+	// expectPrincipal asserts that the service is called with the principal in its context
+	expectPrincipal := func(ctx context.Context) {
+		contextPrincipal, ok := util.PrincipalFromContext(ctx)
+		assert.True(t, ok, "Principal missing from context")
+		assert.Equal(t, principal, contextPrincipal, "Principal mismatch")
+	}
+
+	t.Run("GetMe", func(t *testing.T) {
+		userService := mock.NewMockUserService(gomock.NewController(t))
+		userService.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(principal.UserID)).
+			DoAndReturn(func(ctx context.Context, _ uint64) (*domain.User, error) {
+				expectPrincipal(ctx)
+				return user, nil
+			})
+		server := newUserServer(t, principal, userService, nil)
+
+		status, data := doUserRequest(t, http.MethodGet, server.URL+"/v1/users/me", "")
+		assert.Equal(t, http.StatusOK, status, "Status mismatch")
+		assert.Equal(t, float64(user.ID), data["id"], "User mismatch")
+	})
+
+	t.Run("UpdateMe", func(t *testing.T) {
+		userService := mock.NewMockUserService(gomock.NewController(t))
+		userService.EXPECT().
+			UpdateUser(gomock.Any(), gomock.Eq(principal), gomock.Eq(&domain.User{ID: principal.UserID, Name: "Jane Doe"})).
+			DoAndReturn(func(ctx context.Context, _ *domain.TokenPayload, _ *domain.User) (*domain.User, error) {
+				expectPrincipal(ctx)
+				return &domain.User{ID: user.ID, Name: "Jane Doe", Email: user.Email, Role: user.Role}, nil
+			})
+		server := newUserServer(t, principal, userService, nil)
+
+		status, data := doUserRequest(t, http.MethodPatch, server.URL+"/v1/users/me", `{"name":"Jane Doe"}`)
+		assert.Equal(t, http.StatusOK, status, "Status mismatch")
+		assert.Equal(t, "Jane Doe", data["name"], "Name mismatch")
+		assert.Equal(t, user.Email, data["email"], "Email mismatch")
+	})
+
+	t.Run("DeleteMe", func(t *testing.T) {
+		userService := mock.NewMockUserService(gomock.NewController(t))
+		userService.EXPECT().
+			DeleteUser(gomock.Any(), gomock.Eq(principal), gomock.Eq(principal.UserID)).
+			DoAndReturn(func(ctx context.Context, _ *domain.TokenPayload, _ uint64) error {
+				expectPrincipal(ctx)
+				return nil
+			})
+		server := newUserServer(t, principal, userService, nil)
+
+		status, _ := doUserRequest(t, http.MethodDelete, server.URL+"/v1/users/me", "")
+		assert.Equal(t, http.StatusOK, status, "Status mismatch")
+	})
+
+	t.Run("Fail_ClientCredentials", func(t *testing.T) {
+		clientPrincipal := &domain.TokenPayload{
+			Subject:  "client:reporting",
+			Scopes:   []string{"users:read"},
+			ClientID: "reporting",
+		}
+		server := newUserServer(t, clientPrincipal, mock.NewMockUserService(gomock.NewController(t)), nil)
+
+		status, _ := doUserRequest(t, http.MethodGet, server.URL+"/v1/users/me", "")
+		assert.Equal(t, http.StatusForbidden, status, "Status mismatch")
+
+		status, _ = doUserRequest(t, http.MethodPost, server.URL+"/v1/users/logout", `{"all":true}`)
+		assert.Equal(t, http.StatusForbidden, status, "Status mismatch")
+	})
+
+	t.Run("Fail_Unauthenticated", func(t *testing.T) {
+		server := newUserServer(t, principal, mock.NewMockUserService(gomock.NewController(t)), nil)
+
+		res, err := http.Get(server.URL + "/v1/users/me")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "Status mismatch")
+	})
+}
+
+func TestUserHandler_Impersonating(t *testing.T) {
+	principal := &domain.TokenPayload{
+		ID:             uuid.New(),
+		UserID:         2,
+		Role:           domain.Cashier,
+		ImpersonatorID: 1,
+	}
+	user := &domain.User{
+		ID:    2,
+		Name:  "John Doe",
+		Email: "test@example.com",
+		Role:  domain.Cashier,
+	}
+
+	t.Run("RecordsRequest", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userService := mock.NewMockUserService(ctrl)
+		userService.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(principal.UserID)).
+			Return(user, nil)
+		impersonationService := mock.NewMockImpersonationService(ctrl)
+		impersonationService.EXPECT().
+			RecordRequest(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, request *domain.ImpersonatedRequest) error {
+				assert.Equal(t, principal.ID, request.ImpersonationID, "Impersonation mismatch")
+				assert.Equal(t, http.MethodGet, request.Method, "Method mismatch")
+				assert.Equal(t, "/v1/users/me", request.Path, "Path mismatch")
+				assert.Equal(t, http.StatusOK, request.Status, "Status mismatch")
+				return nil
+			})
+		server := newUserServer(t, principal, userService, impersonationService)
+
+		status, data := doUserRequest(t, http.MethodGet, server.URL+"/v1/users/me", "")
+		assert.Equal(t, http.StatusOK, status, "Status mismatch")
+		assert.Equal(t, float64(user.ID), data["id"], "User mismatch")
+	})
+
+	t.Run("Fail_CredentialChange", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		impersonationService := mock.NewMockImpersonationService(ctrl)
+		impersonationService.EXPECT().
+			RecordRequest(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, request *domain.ImpersonatedRequest) error {
+				assert.Equal(t, "/v1/users/2fa/disable", request.Path, "Path mismatch")
+				assert.Equal(t, http.StatusForbidden, request.Status, "Status mismatch")
+				return nil
+			})
+		server := newUserServer(t, principal, mock.NewMockUserService(ctrl), impersonationService)
+
+		status, _ := doUserRequest(t, http.MethodPost, server.URL+"/v1/users/2fa/disable", `{"code":"123456"}`)
+		assert.Equal(t, http.StatusForbidden, status, "Status mismatch")
+	})
+
+	t.Run("Fail_LogoutAll", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		impersonationService := mock.NewMockImpersonationService(ctrl)
+		impersonationService.EXPECT().
+			RecordRequest(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, request *domain.ImpersonatedRequest) error {
+				assert.Equal(t, "/v1/users/logout", request.Path, "Path mismatch")
+				assert.Equal(t, http.StatusForbidden, request.Status, "Status mismatch")
+				return nil
+			})
+		server := newUserServer(t, principal, mock.NewMockUserService(ctrl), impersonationService)
+
+		status, _ := doUserRequest(t, http.MethodPost, server.URL+"/v1/users/logout", `{"all":true}`)
+		assert.Equal(t, http.StatusForbidden, status, "Status mismatch")
+	})
+}
+
+func TestUserHandler_APIKey(t *testing.T) {
+	principal := &domain.TokenPayload{
+		ID:       uuid.New(),
+		UserID:   1,
+		Role:     domain.Cashier,
+		Scopes:   []string{"users:read"},
+		APIKeyID: 7,
+	}
+
+	t.Run("Fail_LogoutAll", func(t *testing.T) {
+		server := newUserServer(t, principal, mock.NewMockUserService(gomock.NewController(t)), nil)
+
+		status, _ := doUserRequest(t, http.MethodPost, server.URL+"/v1/users/logout", `{"all":true}`)
+		assert.Equal(t, http.StatusForbidden, status, "Status mismatch")
+	})
+}
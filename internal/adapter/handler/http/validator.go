@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/go-playground/validator/v10"
+)
+
+// userRoleValidator is a custom validator for validating user roles
+var userRoleValidator validator.Func = func(fl validator.FieldLevel) bool {
+	userRole := fl.Field().Interface().(domain.UserRole)
+
+	switch userRole {
+	case domain.Admin, domain.Manager, domain.Cashier:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+// headerSanitizer removes line breaks so that values cannot inject headers
+var headerSanitizer = strings.NewReplacer("\r", "", "\n", "")
+
+// Message formats the email as an RFC 5322 plain text message
+func Message(from string, email *domain.Email) []byte {
+	var msg bytes.Buffer
+
+	fmt.Fprintf(&msg, "From: %s\r\n", headerSanitizer.Replace(from))
+	fmt.Fprintf(&msg, "To: %s\r\n", headerSanitizer.Replace(email.To))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", headerSanitizer.Replace(email.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+
+	return msg.Bytes()
+}
@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/adapter/mailer"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+/**
+ * Outbox implements port.Mailer interface
+ * and writes every email as an .eml file to a directory instead of sending it,
+ * it is meant for local development and tests
+ */
+type Outbox struct {
+	dir  string
+	from string
+}
+
+// New creates a new outbox mailer instance, creating the directory if needed
+func New(config *config.Mail) (*Outbox, error) {
+	err := os.MkdirAll(config.OutboxDir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Outbox{
+		config.OutboxDir,
+		config.From,
+	}, nil
+}
+
+// Send writes the email to a new file of the outbox directory
+func (o *Outbox) Send(ctx context.Context, email *domain.Email) error {
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString())
+
+	return os.WriteFile(filepath.Join(o.dir, name), mailer.Message(o.from, email), 0o644)
+}
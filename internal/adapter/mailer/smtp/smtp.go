@@ -0,0 +1,49 @@
+package smtp
+
+import (
+	"context"
+	"net"
+	"net/mail"
+	"net/smtp"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/adapter/mailer"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+/**
+ * SMTP implements port.Mailer interface
+ * and sends emails through an SMTP server,
+ * STARTTLS is used whenever the server supports it
+ */
+type SMTP struct {
+	addr   string
+	auth   smtp.Auth
+	from   string
+	sender string
+}
+
+// New creates a new SMTP mailer instance, it authenticates only if a username is configured
+func New(config *config.Mail) (*SMTP, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	return &SMTP{
+		net.JoinHostPort(config.Host, config.Port),
+		auth,
+		config.From,
+		from.Address,
+	}, nil
+}
+
+// Send sends the email through the SMTP server, the bare sender address is used for the envelope
+func (s *SMTP) Send(ctx context.Context, email *domain.Email) error {
+	return smtp.SendMail(s.addr, s.auth, s.sender, []string{email.To}, mailer.Message(s.from, email))
+}
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS "refresh_tokens";
+
+CREATE TABLE "refresh_tokens" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "family_id" uuid NOT NULL,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "token_hash" varchar NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+
+CREATE INDEX "refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
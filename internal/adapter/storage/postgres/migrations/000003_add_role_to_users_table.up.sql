@@ -0,0 +1,4 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "role";
+
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'cashier'
+    CHECK ("role" IN ('admin', 'manager', 'cashier'));
@@ -0,0 +1,23 @@
+DROP TABLE IF EXISTS "recovery_codes";
+DROP TABLE IF EXISTS "user_totp";
+
+CREATE TABLE "user_totp" (
+    "user_id" bigint PRIMARY KEY REFERENCES "users" ("id") ON DELETE CASCADE,
+    "secret" varchar NOT NULL,
+    "confirmed_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "recovery_codes" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "code_hash" varchar NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "recovery_codes_user_id" ON "recovery_codes" ("user_id");
+
+ALTER TABLE "refresh_tokens" DROP COLUMN IF EXISTS "two_factor";
+
+ALTER TABLE "refresh_tokens" ADD COLUMN "two_factor" boolean NOT NULL DEFAULT false;
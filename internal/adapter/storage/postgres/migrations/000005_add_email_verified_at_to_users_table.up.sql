@@ -0,0 +1,6 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_verified_at";
+
+ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamptz;
+
+-- accounts created before email verification existed are trusted
+UPDATE "users" SET "email_verified_at" = "created_at";
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS "password_reset_tokens";
+
+CREATE TABLE "password_reset_tokens" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "token_hash" varchar NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "password_reset_tokens_token_hash" ON "password_reset_tokens" ("token_hash");
+
+CREATE INDEX "password_reset_tokens_user_id" ON "password_reset_tokens" ("user_id");
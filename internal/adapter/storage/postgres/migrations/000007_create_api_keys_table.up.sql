@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS "api_keys";
+
+CREATE TABLE "api_keys" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "name" varchar NOT NULL,
+    "prefix" varchar NOT NULL,
+    "key_hash" varchar NOT NULL,
+    "scopes" varchar[] NOT NULL DEFAULT '{}',
+    "expires_at" timestamptz,
+    "last_used_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "api_keys_prefix" ON "api_keys" ("prefix");
+
+CREATE INDEX "api_keys_user_id" ON "api_keys" ("user_id");
@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS "oauth_consents";
+DROP TABLE IF EXISTS "oauth_clients";
+
+CREATE TABLE "oauth_clients" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "client_id" varchar NOT NULL,
+    "secret_hash" varchar NOT NULL DEFAULT '',
+    "name" varchar NOT NULL,
+    "redirect_uris" varchar[] NOT NULL DEFAULT '{}',
+    "scopes" varchar[] NOT NULL DEFAULT '{}',
+    "confidential" boolean NOT NULL DEFAULT false,
+    "created_by" bigint REFERENCES "users" ("id") ON DELETE SET NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "oauth_clients_client_id" ON "oauth_clients" ("client_id");
+
+CREATE TABLE "oauth_consents" (
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "client_id" bigint NOT NULL REFERENCES "oauth_clients" ("id") ON DELETE CASCADE,
+    "scopes" varchar[] NOT NULL DEFAULT '{}',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY ("user_id", "client_id")
+);
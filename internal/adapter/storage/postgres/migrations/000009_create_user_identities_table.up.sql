@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS "user_identities";
+
+CREATE TABLE "user_identities" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "provider" varchar NOT NULL,
+    "subject" varchar NOT NULL,
+    "email" varchar NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "user_identities_provider_subject" ON "user_identities" ("provider", "subject");
+
+CREATE INDEX "user_identities_user_id" ON "user_identities" ("user_id");
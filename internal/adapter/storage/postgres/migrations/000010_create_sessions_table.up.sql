@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS "sessions";
+
+CREATE TABLE "sessions" (
+    "id" uuid PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "token_id" uuid NOT NULL,
+    "ip" varchar NOT NULL DEFAULT '',
+    "user_agent" varchar NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "last_seen_at" timestamptz NOT NULL DEFAULT (now()),
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz
+);
+
+CREATE INDEX "sessions_user_id" ON "sessions" ("user_id");
@@ -0,0 +1,28 @@
+DROP TABLE IF EXISTS "impersonated_requests";
+DROP TABLE IF EXISTS "impersonations";
+
+CREATE TABLE "impersonations" (
+    "id" uuid PRIMARY KEY,
+    "impersonator_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "reason" varchar NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "expires_at" timestamptz NOT NULL
+);
+
+CREATE INDEX "impersonations_impersonator_id" ON "impersonations" ("impersonator_id");
+
+CREATE INDEX "impersonations_user_id" ON "impersonations" ("user_id");
+
+CREATE TABLE "impersonated_requests" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "impersonation_id" uuid NOT NULL REFERENCES "impersonations" ("id") ON DELETE CASCADE,
+    "method" varchar NOT NULL,
+    "path" varchar NOT NULL,
+    "status" integer NOT NULL,
+    "ip" varchar NOT NULL DEFAULT '',
+    "user_agent" varchar NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "impersonated_requests_impersonation_id" ON "impersonated_requests" ("impersonation_id");
@@ -0,0 +1,31 @@
+DROP TABLE IF EXISTS "auth_events";
+DROP FUNCTION IF EXISTS "reject_auth_events_change";
+
+CREATE TABLE "auth_events" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "type" varchar NOT NULL,
+    "actor_id" bigint NOT NULL DEFAULT 0,
+    "target_id" bigint NOT NULL DEFAULT 0,
+    "email" varchar NOT NULL DEFAULT '',
+    "ip" varchar NOT NULL DEFAULT '',
+    "user_agent" varchar NOT NULL DEFAULT '',
+    "outcome" varchar NOT NULL,
+    "reason" varchar NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "auth_events_actor_id" ON "auth_events" ("actor_id");
+
+CREATE INDEX "auth_events_target_id" ON "auth_events" ("target_id");
+
+CREATE INDEX "auth_events_type_created_at" ON "auth_events" ("type", "created_at");
+
+CREATE FUNCTION "reject_auth_events_change"() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'auth_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "auth_events_append_only"
+    BEFORE UPDATE OR DELETE ON "auth_events"
+    FOR EACH ROW EXECUTE FUNCTION "reject_auth_events_change"();
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * APIKeyRepository implements port.APIKeyRepository interface
+ * and provides an access to the postgres database
+ */
+type APIKeyRepository struct {
+	db *postgres.DB
+}
+
+// NewAPIKeyRepository creates a new api key repository instance
+func NewAPIKeyRepository(db *postgres.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db,
+	}
+}
+
+// CreateAPIKey creates a new api key in the database
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	query := ar.db.QueryBuilder.Insert("api_keys").
+		Columns("user_id", "name", "prefix", "key_hash", "scopes", "expires_at").
+		Values(key.UserID, key.Name, key.Prefix, key.KeyHash, scopes, key.ExpiresAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ar.db.QueryRow(ctx, sql, args...).Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		if errCode := ar.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByPrefix gets an api key by its lookup prefix from the database
+func (ar *APIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	query := ar.db.QueryBuilder.Select("*").
+		From("api_keys").
+		Where(sq.Eq{"prefix": prefix}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ar.db.QueryRow(ctx, sql, args...).Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// ListAPIKeysByUserID lists the api keys of a user from the database, newest first
+func (ar *APIKeyRepository) ListAPIKeysByUserID(ctx context.Context, userID uint64) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+
+	query := ar.db.QueryBuilder.Select("*").
+		From("api_keys").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("id DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ar.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key domain.APIKey
+
+		err := rows.Scan(
+			&key.ID,
+			&key.UserID,
+			&key.Name,
+			&key.Prefix,
+			&key.KeyHash,
+			&key.Scopes,
+			&key.ExpiresAt,
+			&key.LastUsedAt,
+			&key.RevokedAt,
+			&key.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey revokes an api key of a user in the database.
+// It returns domain.ErrDataNotFound if the user has no such key or it was already revoked
+func (ar *APIKeyRepository) RevokeAPIKey(ctx context.Context, userID, id uint64) error {
+	query := ar.db.QueryBuilder.Update("api_keys").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{
+			"id":         id,
+			"user_id":    userID,
+			"revoked_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := ar.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// TouchAPIKey records when an api key was last used in the database
+func (ar *APIKeyRepository) TouchAPIKey(ctx context.Context, id uint64, usedAt time.Time) error {
+	query := ar.db.QueryBuilder.Update("api_keys").
+		Set("last_used_at", usedAt).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = ar.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
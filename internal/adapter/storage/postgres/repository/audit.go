@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+/**
+ * AuditRepository implements port.AuditRepository interface
+ * and provides an access to the postgres database.
+ * The auth_events table is append-only, events are never updated or deleted
+ */
+type AuditRepository struct {
+	db *postgres.DB
+}
+
+// NewAuditRepository creates a new audit repository instance
+func NewAuditRepository(db *postgres.DB) *AuditRepository {
+	return &AuditRepository{
+		db,
+	}
+}
+
+// CreateAuthEvent records a new security event in the database
+func (ar *AuditRepository) CreateAuthEvent(ctx context.Context, event *domain.AuthEvent) (*domain.AuthEvent, error) {
+	query := ar.db.QueryBuilder.Insert("auth_events").
+		Columns("type", "actor_id", "target_id", "email", "ip", "user_agent", "outcome", "reason").
+		Values(event.Type, event.ActorID, event.TargetID, event.Email, event.IP, event.UserAgent, event.Outcome, event.Reason).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ar.db.QueryRow(ctx, sql, args...).Scan(
+		&event.ID,
+		&event.Type,
+		&event.ActorID,
+		&event.TargetID,
+		&event.Email,
+		&event.IP,
+		&event.UserAgent,
+		&event.Outcome,
+		&event.Reason,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// ListAuthEvents lists the security events matching the filter from the database, newest first.
+// Events match a user if the user is their actor or their target
+func (ar *AuditRepository) ListAuthEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	var event domain.AuthEvent
+	var events []domain.AuthEvent
+
+	query := ar.db.QueryBuilder.Select("*").
+		From("auth_events").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Skip - 1) * filter.Limit)
+
+	if filter.UserID != 0 {
+		query = query.Where(sq.Or{
+			sq.Eq{"actor_id": filter.UserID},
+			sq.Eq{"target_id": filter.UserID},
+		})
+	}
+	if filter.Type != "" {
+		query = query.Where(sq.Eq{"type": filter.Type})
+	}
+	if !filter.From.IsZero() {
+		query = query.Where(sq.GtOrEq{"created_at": filter.From})
+	}
+	if !filter.To.IsZero() {
+		query = query.Where(sq.Lt{"created_at": filter.To})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ar.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.ActorID,
+			&event.TargetID,
+			&event.Email,
+			&event.IP,
+			&event.UserAgent,
+			&event.Outcome,
+			&event.Reason,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+/**
+ * ImpersonationRepository implements port.ImpersonationRepository interface
+ * and provides an access to the postgres database
+ */
+type ImpersonationRepository struct {
+	db *postgres.DB
+}
+
+// NewImpersonationRepository creates a new impersonation repository instance
+func NewImpersonationRepository(db *postgres.DB) *ImpersonationRepository {
+	return &ImpersonationRepository{
+		db,
+	}
+}
+
+// CreateImpersonation records a new impersonation in the database
+func (ir *ImpersonationRepository) CreateImpersonation(ctx context.Context, impersonation *domain.Impersonation) (*domain.Impersonation, error) {
+	query := ir.db.QueryBuilder.Insert("impersonations").
+		Columns("id", "impersonator_id", "user_id", "reason", "expires_at").
+		Values(impersonation.ID, impersonation.ImpersonatorID, impersonation.UserID, impersonation.Reason, impersonation.ExpiresAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&impersonation.ID,
+		&impersonation.ImpersonatorID,
+		&impersonation.UserID,
+		&impersonation.Reason,
+		&impersonation.CreatedAt,
+		&impersonation.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return impersonation, nil
+}
+
+// CreateImpersonatedRequest records a request made with an impersonation token in the database
+func (ir *ImpersonationRepository) CreateImpersonatedRequest(ctx context.Context, request *domain.ImpersonatedRequest) (*domain.ImpersonatedRequest, error) {
+	query := ir.db.QueryBuilder.Insert("impersonated_requests").
+		Columns("impersonation_id", "method", "path", "status", "ip", "user_agent").
+		Values(request.ImpersonationID, request.Method, request.Path, request.Status, request.IP, request.UserAgent).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&request.ID,
+		&request.ImpersonationID,
+		&request.Method,
+		&request.Path,
+		&request.Status,
+		&request.IP,
+		&request.UserAgent,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * OAuthClientRepository implements port.OAuthClientRepository interface
+ * and provides an access to the postgres database
+ */
+type OAuthClientRepository struct {
+	db *postgres.DB
+}
+
+// NewOAuthClientRepository creates a new oauth client repository instance
+func NewOAuthClientRepository(db *postgres.DB) *OAuthClientRepository {
+	return &OAuthClientRepository{
+		db,
+	}
+}
+
+// CreateOAuthClient creates a new oauth client in the database
+func (cr *OAuthClientRepository) CreateOAuthClient(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+	redirectURIs := client.RedirectURIs
+	if redirectURIs == nil {
+		redirectURIs = []string{}
+	}
+
+	query := cr.db.QueryBuilder.Insert("oauth_clients").
+		Columns("client_id", "secret_hash", "name", "redirect_uris", "scopes", "confidential", "created_by").
+		Values(client.ClientID, client.SecretHash, client.Name, redirectURIs, client.Scopes, client.Confidential, client.CreatedBy).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&client.ID,
+		&client.ClientID,
+		&client.SecretHash,
+		&client.Name,
+		&client.RedirectURIs,
+		&client.Scopes,
+		&client.Confidential,
+		&client.CreatedBy,
+		&client.CreatedAt,
+	)
+	if err != nil {
+		if errCode := cr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// GetOAuthClientByClientID gets an oauth client by its public client ID from the database
+func (cr *OAuthClientRepository) GetOAuthClientByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	var client domain.OAuthClient
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("oauth_clients").
+		Where(sq.Eq{"client_id": clientID}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&client.ID,
+		&client.ClientID,
+		&client.SecretHash,
+		&client.Name,
+		&client.RedirectURIs,
+		&client.Scopes,
+		&client.Confidential,
+		&client.CreatedBy,
+		&client.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// ListOAuthClients lists every oauth client from the database, newest first
+func (cr *OAuthClientRepository) ListOAuthClients(ctx context.Context) ([]domain.OAuthClient, error) {
+	var clients []domain.OAuthClient
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("oauth_clients").
+		OrderBy("id DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := cr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var client domain.OAuthClient
+
+		err := rows.Scan(
+			&client.ID,
+			&client.ClientID,
+			&client.SecretHash,
+			&client.Name,
+			&client.RedirectURIs,
+			&client.Scopes,
+			&client.Confidential,
+			&client.CreatedBy,
+			&client.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		clients = append(clients, client)
+	}
+
+	return clients, nil
+}
+
+// DeleteOAuthClient deletes an oauth client and the consents given to it from the database.
+// It returns domain.ErrDataNotFound if there is no such client
+func (cr *OAuthClientRepository) DeleteOAuthClient(ctx context.Context, id uint64) error {
+	query := cr.db.QueryBuilder.Delete("oauth_clients").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := cr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// GetOAuthConsent gets the consent a user gave to an oauth client from the database
+func (cr *OAuthClientRepository) GetOAuthConsent(ctx context.Context, userID, clientID uint64) (*domain.OAuthConsent, error) {
+	var consent domain.OAuthConsent
+
+	query := cr.db.QueryBuilder.Select("*").
+		From("oauth_consents").
+		Where(sq.Eq{
+			"user_id":   userID,
+			"client_id": clientID,
+		}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&consent.UserID,
+		&consent.ClientID,
+		&consent.Scopes,
+		&consent.CreatedAt,
+		&consent.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &consent, nil
+}
+
+// SaveOAuthConsent creates or replaces the consent a user gave to an oauth client in the database
+func (cr *OAuthClientRepository) SaveOAuthConsent(ctx context.Context, consent *domain.OAuthConsent) (*domain.OAuthConsent, error) {
+	query := cr.db.QueryBuilder.Insert("oauth_consents").
+		Columns("user_id", "client_id", "scopes").
+		Values(consent.UserID, consent.ClientID, consent.Scopes).
+		Suffix(`ON CONFLICT ("user_id", "client_id") DO UPDATE SET "scopes" = EXCLUDED."scopes", "updated_at" = now() RETURNING *`)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cr.db.QueryRow(ctx, sql, args...).Scan(
+		&consent.UserID,
+		&consent.ClientID,
+		&consent.Scopes,
+		&consent.CreatedAt,
+		&consent.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return consent, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * PasswordResetRepository implements port.PasswordResetRepository interface
+ * and provides an access to the postgres database
+ */
+type PasswordResetRepository struct {
+	db *postgres.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository instance
+func NewPasswordResetRepository(db *postgres.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db,
+	}
+}
+
+// CreatePasswordResetToken creates a new password reset token in the database
+func (pr *PasswordResetRepository) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+	query := pr.db.QueryBuilder.Insert("password_reset_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(token.UserID, token.TokenHash, token.ExpiresAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = pr.db.QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errCode := pr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// GetPasswordResetTokenByHash gets a password reset token by its hash from the database
+func (pr *PasswordResetRepository) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+
+	query := pr.db.QueryBuilder.Select("*").
+		From("password_reset_tokens").
+		Where(sq.Eq{"token_hash": hash}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = pr.db.QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkPasswordResetTokenUsed marks an unused password reset token as used in the database.
+// It returns domain.ErrDataNotFound if the token was already used
+func (pr *PasswordResetRepository) MarkPasswordResetTokenUsed(ctx context.Context, id uint64) error {
+	query := pr.db.QueryBuilder.Update("password_reset_tokens").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{
+			"id":      id,
+			"used_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := pr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// InvalidateUserPasswordResetTokens marks every unused password reset token of a user as used in the database
+func (pr *PasswordResetRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userID uint64) error {
+	query := pr.db.QueryBuilder.Update("password_reset_tokens").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{
+			"user_id": userID,
+			"used_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * RefreshTokenRepository implements port.RefreshTokenRepository interface
+ * and provides an access to the postgres database
+ */
+type RefreshTokenRepository struct {
+	db *postgres.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository instance
+func NewRefreshTokenRepository(db *postgres.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db,
+	}
+}
+
+// CreateRefreshToken creates a new refresh token in the database
+func (rr *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	query := rr.db.QueryBuilder.Insert("refresh_tokens").
+		Columns("family_id", "user_id", "token_hash", "expires_at", "two_factor").
+		Values(token.FamilyID, token.UserID, token.TokenHash, token.ExpiresAt, token.TwoFactor).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = rr.db.QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.FamilyID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+		&token.TwoFactor,
+	)
+	if err != nil {
+		if errCode := rr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// GetRefreshTokenByHash gets a refresh token by its hash from the database
+func (rr *RefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+
+	query := rr.db.QueryBuilder.Select("*").
+		From("refresh_tokens").
+		Where(sq.Eq{"token_hash": hash}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = rr.db.QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.FamilyID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+		&token.TwoFactor,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed marks an unused refresh token as used in the database.
+// It returns domain.ErrDataNotFound if the token was already used or revoked
+func (rr *RefreshTokenRepository) MarkRefreshTokenUsed(ctx context.Context, id uint64) error {
+	query := rr.db.QueryBuilder.Update("refresh_tokens").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{
+			"id":         id,
+			"used_at":    nil,
+			"revoked_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := rr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes every refresh token of a family in the database
+func (rr *RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := rr.db.QueryBuilder.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{
+			"family_id":  familyID,
+			"revoked_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = rr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of a user in the database
+func (rr *RefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uint64) error {
+	query := rr.db.QueryBuilder.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{
+			"user_id":    userID,
+			"revoked_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = rr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * SessionRepository implements port.SessionRepository interface
+ * and provides an access to the postgres database
+ */
+type SessionRepository struct {
+	db *postgres.DB
+}
+
+// NewSessionRepository creates a new session repository instance
+func NewSessionRepository(db *postgres.DB) *SessionRepository {
+	return &SessionRepository{
+		db,
+	}
+}
+
+// SaveSession creates the session of a new refresh token family or records the latest access token issued for it in the database
+func (sr *SessionRepository) SaveSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	query := sr.db.QueryBuilder.Insert("sessions").
+		Columns("id", "user_id", "token_id", "ip", "user_agent", "expires_at").
+		Values(session.ID, session.UserID, session.TokenID, session.IP, session.UserAgent, session.ExpiresAt).
+		Suffix(`ON CONFLICT ("id") DO UPDATE SET "token_id" = EXCLUDED."token_id", "ip" = EXCLUDED."ip", "user_agent" = EXCLUDED."user_agent", "expires_at" = EXCLUDED."expires_at", "last_seen_at" = now() RETURNING *`)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sr.db.QueryRow(ctx, sql, args...).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenID,
+		&session.IP,
+		&session.UserAgent,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetSession gets a session by id from the database
+func (sr *SessionRepository) GetSession(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	var session domain.Session
+
+	query := sr.db.QueryBuilder.Select("*").
+		From("sessions").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sr.db.QueryRow(ctx, sql, args...).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenID,
+		&session.IP,
+		&session.UserAgent,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// ListUserSessions lists the sessions of a user that are neither revoked nor expired from the database, most recently seen first
+func (sr *SessionRepository) ListUserSessions(ctx context.Context, userID uint64) ([]domain.Session, error) {
+	var sessions []domain.Session
+
+	query := sr.db.QueryBuilder.Select("*").
+		From("sessions").
+		Where(sq.Eq{
+			"user_id":    userID,
+			"revoked_at": nil,
+		}).
+		Where(sq.Gt{"expires_at": time.Now()}).
+		OrderBy("last_seen_at DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session domain.Session
+
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.TokenID,
+			&session.IP,
+			&session.UserAgent,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.ExpiresAt,
+			&session.RevokedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// TouchSession records when a session was last seen in the database
+func (sr *SessionRepository) TouchSession(ctx context.Context, id uuid.UUID, seenAt time.Time) error {
+	query := sr.db.QueryBuilder.Update("sessions").
+		Set("last_seen_at", seenAt).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = sr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeSession revokes a session in the database
+func (sr *SessionRepository) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	return sr.revoke(ctx, sq.Eq{"id": id})
+}
+
+// RevokeUserSessions revokes every session of a user in the database
+func (sr *SessionRepository) RevokeUserSessions(ctx context.Context, userID uint64) error {
+	return sr.revoke(ctx, sq.Eq{"user_id": userID})
+}
+
+// revoke revokes the matching sessions that are not revoked yet
+func (sr *SessionRepository) revoke(ctx context.Context, where sq.Eq) error {
+	query := sr.db.QueryBuilder.Update("sessions").
+		Set("revoked_at", time.Now()).
+		Where(where).
+		Where(sq.Eq{"revoked_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = sr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * TwoFactorRepository implements port.TwoFactorRepository interface
+ * and provides an access to the postgres database
+ */
+type TwoFactorRepository struct {
+	db *postgres.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository instance
+func NewTwoFactorRepository(db *postgres.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db,
+	}
+}
+
+// SaveTOTP stores the TOTP secret of a user in the database, replacing the previous one
+func (tr *TwoFactorRepository) SaveTOTP(ctx context.Context, totp *domain.TOTP) (*domain.TOTP, error) {
+	query := tr.db.QueryBuilder.Insert("user_totp").
+		Columns("user_id", "secret").
+		Values(totp.UserID, totp.Secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, confirmed_at = NULL, created_at = now() RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tr.db.QueryRow(ctx, sql, args...).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return totp, nil
+}
+
+// GetTOTPByUserID gets the TOTP secret of a user from the database
+func (tr *TwoFactorRepository) GetTOTPByUserID(ctx context.Context, userID uint64) (*domain.TOTP, error) {
+	var totp domain.TOTP
+
+	query := tr.db.QueryBuilder.Select("*").
+		From("user_totp").
+		Where(sq.Eq{"user_id": userID}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tr.db.QueryRow(ctx, sql, args...).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+// ConfirmTOTP marks the TOTP secret of a user as confirmed in the database
+func (tr *TwoFactorRepository) ConfirmTOTP(ctx context.Context, userID uint64) error {
+	query := tr.db.QueryBuilder.Update("user_totp").
+		Set("confirmed_at", time.Now()).
+		Where(sq.Eq{
+			"user_id":      userID,
+			"confirmed_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := tr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// DeleteTOTP deletes the TOTP secret and the recovery codes of a user from the database
+func (tr *TwoFactorRepository) DeleteTOTP(ctx context.Context, userID uint64) error {
+	return pgx.BeginFunc(ctx, tr.db, func(tx pgx.Tx) error {
+		for _, table := range []string{"recovery_codes", "user_totp"} {
+			query := tr.db.QueryBuilder.Delete(table).
+				Where(sq.Eq{"user_id": userID})
+
+			sql, args, err := query.ToSql()
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(ctx, sql, args...)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReplaceRecoveryCodes replaces every recovery code of a user in the database
+func (tr *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint64, codeHashes []string) error {
+	return pgx.BeginFunc(ctx, tr.db, func(tx pgx.Tx) error {
+		deleteQuery := tr.db.QueryBuilder.Delete("recovery_codes").
+			Where(sq.Eq{"user_id": userID})
+
+		sql, args, err := deleteQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		insertQuery := tr.db.QueryBuilder.Insert("recovery_codes").
+			Columns("user_id", "code_hash")
+		for _, codeHash := range codeHashes {
+			insertQuery = insertQuery.Values(userID, codeHash)
+		}
+
+		sql, args, err = insertQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, sql, args...)
+		return err
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used in the database.
+// It returns domain.ErrDataNotFound if the user has no such unused code
+func (tr *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error {
+	query := tr.db.QueryBuilder.Update("recovery_codes").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{
+			"user_id":   userID,
+			"code_hash": codeHash,
+			"used_at":   nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := tr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
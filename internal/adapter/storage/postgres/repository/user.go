@@ -28,8 +28,8 @@ func NewUserRepository(db *postgres.DB) *UserRepository {
 // CreateUser creates a new user in the database
 func (ur *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	query := ur.db.QueryBuilder.Insert("users").
-		Columns("name", "email", "password").
-		Values(user.Name, user.Email, user.Password).
+		Columns("name", "email", "password", "role").
+		Values(user.Name, user.Email, user.Password, user.Role).
 		Suffix("RETURNING *")
 
 	sql, args, err := query.ToSql()
@@ -44,6 +44,8 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*d
 		&user.Password,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Role,
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		if errCode := ur.db.ErrorCode(err); errCode == "23505" {
@@ -76,6 +78,8 @@ func (ur *UserRepository) GetUserByID(ctx context.Context, id uint64) (*domain.U
 		&user.Password,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Role,
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -108,6 +112,8 @@ func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*do
 		&user.Password,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Role,
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -149,6 +155,8 @@ func (ur *UserRepository) ListUsers(ctx context.Context, skip, limit uint64) ([]
 			&user.Password,
 			&user.CreatedAt,
 			&user.UpdatedAt,
+			&user.Role,
+			&user.EmailVerifiedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -160,16 +168,19 @@ func (ur *UserRepository) ListUsers(ctx context.Context, skip, limit uint64) ([]
 	return users, nil
 }
 
-// UpdateUser updates a user by ID in the database
+// UpdateUser updates a user by ID in the database, changing the email resets its verification
 func (ur *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	name := nullString(user.Name)
 	email := nullString(user.Email)
 	password := nullString(user.Password)
+	role := nullString(string(user.Role))
 
 	query := ur.db.QueryBuilder.Update("users").
 		Set("name", sq.Expr("COALESCE(?, name)", name)).
 		Set("email", sq.Expr("COALESCE(?, email)", email)).
 		Set("password", sq.Expr("COALESCE(?, password)", password)).
+		Set("role", sq.Expr("COALESCE(?, role)", role)).
+		Set("email_verified_at", sq.Expr("CASE WHEN COALESCE(?, email) = email THEN email_verified_at END", email)).
 		Set("updated_at", time.Now()).
 		Where(sq.Eq{"id": user.ID}).
 		Suffix("RETURNING *")
@@ -186,6 +197,8 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*d
 		&user.Password,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Role,
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		if errCode := ur.db.ErrorCode(err); errCode == "23505" {
@@ -197,6 +210,33 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*d
 	return user, nil
 }
 
+// VerifyUserEmail marks the email of a user as verified in the database.
+// It returns domain.ErrDataNotFound if the user no longer has the given email
+func (ur *UserRepository) VerifyUserEmail(ctx context.Context, id uint64, email string) error {
+	query := ur.db.QueryBuilder.Update("users").
+		Set("email_verified_at", sq.Expr("COALESCE(email_verified_at, ?)", time.Now())).
+		Where(sq.Eq{
+			"id":    id,
+			"email": email,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := ur.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
 // DeleteUser deletes a user by ID from the database
 func (ur *UserRepository) DeleteUser(ctx context.Context, id uint64) error {
 	query := ur.db.QueryBuilder.Delete("users").
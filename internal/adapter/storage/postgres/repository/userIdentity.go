@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cidmiranda/go-ws/internal/adapter/storage/postgres"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * UserIdentityRepository implements port.UserIdentityRepository interface
+ * and provides an access to the postgres database
+ */
+type UserIdentityRepository struct {
+	db *postgres.DB
+}
+
+// NewUserIdentityRepository creates a new user identity repository instance
+func NewUserIdentityRepository(db *postgres.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{
+		db,
+	}
+}
+
+// CreateUserIdentity links an external identity to a user in the database
+func (ir *UserIdentityRepository) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+	query := ir.db.QueryBuilder.Insert("user_identities").
+		Columns("user_id", "provider", "subject", "email").
+		Values(identity.UserID, identity.Provider, identity.Subject, identity.Email).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if errCode := ir.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// GetUserIdentity gets the link of an external identity by its provider and subject from the database
+func (ir *UserIdentityRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+
+	query := ir.db.QueryBuilder.Select("*").
+		From("user_identities").
+		Where(sq.Eq{
+			"provider": provider,
+			"subject":  subject,
+		}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// DeleteUserIdentity deletes the link of an external identity from the database
+func (ir *UserIdentityRepository) DeleteUserIdentity(ctx context.Context, id uint64) error {
+	query := ir.db.QueryBuilder.Delete("user_identities").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = ir.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/cidmiranda/go-ws/internal/adapter/config"
+	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/cidmiranda/go-ws/internal/core/port"
 	"github.com/redis/go-redis/v9"
 )
@@ -38,9 +39,13 @@ func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Dura
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
-// Get retrieves the value from the redis database
+// Get retrieves the value from the redis database, a missing key returns domain.ErrDataNotFound
+// so that callers can tell it apart from an unavailable database
 func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 	res, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, domain.ErrDataNotFound
+	}
 	bytes := []byte(res)
 	return bytes, err
 }
@@ -77,6 +82,23 @@ func (r *Redis) DeleteByPrefix(ctx context.Context, prefix string) error {
 	return nil
 }
 
+// Increment atomically increments the counter stored at the key,
+// the ttl only starts when the counter is created so that it acts as a fixed window
+func (r *Redis) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	if ttl > 0 {
+		pipe.ExpireNX(ctx, key, ttl)
+	}
+
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
 // Close closes the connection to the redis database
 func (r *Redis) Close() error {
 	return r.client.Close()
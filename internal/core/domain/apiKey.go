@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+)
+
+type APIKey struct {
+	ID         uint64
+	UserID     uint64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
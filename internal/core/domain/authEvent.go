@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// AuthEventType is an enum for the type of a security event
+type AuthEventType string
+
+// AuthEventType enum values
+const (
+	LoginEvent          AuthEventType = "login"
+	PasswordChangeEvent AuthEventType = "password_change"
+	UserUpdateEvent     AuthEventType = "user_update"
+	UserDeleteEvent     AuthEventType = "user_delete"
+)
+
+// AuthEventOutcome is an enum for the outcome of a security event
+type AuthEventOutcome string
+
+// AuthEventOutcome enum values
+const (
+	SuccessOutcome AuthEventOutcome = "success"
+	FailureOutcome AuthEventOutcome = "failure"
+)
+
+// AuthEvent is an entity that represents a security event of the audit log,
+// the actor is the user who acted and the target the user the event is about
+type AuthEvent struct {
+	ID        uint64
+	Type      AuthEventType
+	ActorID   uint64
+	TargetID  uint64
+	Email     string
+	IP        string
+	UserAgent string
+	Outcome   AuthEventOutcome
+	Reason    string
+	CreatedAt time.Time
+}
+
+// AuthEventFilter is an entity that represents the criteria to list security events by,
+// zero values match every event
+type AuthEventFilter struct {
+	UserID uint64
+	Type   AuthEventType
+	From   time.Time
+	To     time.Time
+	Skip   uint64
+	Limit  uint64
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuthToken struct {
+	User             *User
+	AccessToken      string
+	RefreshToken     string
+	ChallengeToken   string
+	SessionID        uuid.UUID
+	SessionExpiresAt time.Time
+}
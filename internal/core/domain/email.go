@@ -0,0 +1,7 @@
+package domain
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
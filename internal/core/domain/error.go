@@ -19,22 +19,94 @@ var (
 	ErrInsufficientPayment = errors.New("total paid is less than total price")
 	// ErrTokenDuration is an error for when the token duration format is invalid
 	ErrTokenDuration = errors.New("invalid token duration format")
+	// ErrTokenKey is an error for when the token keys configuration is invalid
+	ErrTokenKey = errors.New("invalid token key configuration")
 	// ErrTokenCreation is an error for when the token creation fails
 	ErrTokenCreation = errors.New("error creating token")
 	// ErrExpiredToken is an error for when the access token is expired
 	ErrExpiredToken = errors.New("access token has expired")
 	// ErrInvalidToken is an error for when the access token is invalid
 	ErrInvalidToken = errors.New("access token is invalid")
+	// ErrInvalidTokenIssuer is an error for when the access token was issued by another issuer
+	ErrInvalidTokenIssuer = errors.New("access token issuer is not accepted")
+	// ErrInvalidTokenAudience is an error for when the access token was issued for another audience
+	ErrInvalidTokenAudience = errors.New("access token audience is not accepted")
+	// ErrRevokedToken is an error for when the access token has been revoked
+	ErrRevokedToken = errors.New("access token has been revoked")
+	// ErrInvalidRefreshToken is an error for when the refresh token is invalid
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	// ErrExpiredRefreshToken is an error for when the refresh token is expired
+	ErrExpiredRefreshToken = errors.New("refresh token has expired")
+	// ErrRefreshTokenReused is an error for when an already used refresh token is presented again
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+	// ErrWeakPassword is an error for when a password does not meet the password policy
+	ErrWeakPassword = errors.New("password does not meet the password policy")
+	// ErrInvalidAPIKey is an error for when the api key is invalid, expired or revoked
+	ErrInvalidAPIKey = errors.New("api key is invalid, expired or revoked")
+	// ErrInvalidOAuthClient is an error for when the oauth client is unknown or its authentication failed
+	ErrInvalidOAuthClient = errors.New("oauth client authentication failed")
+	// ErrUnauthorizedOAuthClient is an error for when the oauth client may not use the requested grant type
+	ErrUnauthorizedOAuthClient = errors.New("oauth client is not allowed to use this grant type")
+	// ErrUnsupportedGrantType is an error for when the grant type is not supported
+	ErrUnsupportedGrantType = errors.New("grant type is not supported")
+	// ErrInvalidGrant is an error for when the authorization code is invalid, expired, used or issued to another client
+	ErrInvalidGrant = errors.New("authorization code is invalid or has expired")
+	// ErrInvalidScope is an error for when the requested scope is not allowed for the oauth client
+	ErrInvalidScope = errors.New("requested scope is not allowed for the client")
+	// ErrInvalidRedirectURI is an error for when the redirect uri is not registered for the oauth client
+	ErrInvalidRedirectURI = errors.New("redirect uri is not registered for the client")
+	// ErrInvalidCodeChallenge is an error for when the PKCE code challenge or verifier is missing or malformed
+	ErrInvalidCodeChallenge = errors.New("PKCE code challenge is missing or invalid")
 	// ErrInvalidCredentials is an error for when the credentials are invalid
 	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidTwoFactorCode is an error for when the two-factor code is invalid or has already been used
+	ErrInvalidTwoFactorCode = errors.New("two-factor code is invalid")
+	// ErrInvalidLoginChallenge is an error for when the login challenge token is invalid or has expired
+	ErrInvalidLoginChallenge = errors.New("login challenge is invalid or has expired")
+	// ErrTwoFactorEnabled is an error for when two-factor authentication is already enabled
+	ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnabled is an error for when two-factor authentication is not enabled
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrEmailNotVerified is an error for when a user logs in before verifying the email address
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	// ErrInvalidVerificationToken is an error for when the email verification token is invalid or has expired
+	ErrInvalidVerificationToken = errors.New("email verification link is invalid or has expired")
+	// ErrInvalidPasswordResetToken is an error for when the password reset token is invalid, used or expired
+	ErrInvalidPasswordResetToken = errors.New("password reset link is invalid or has expired")
+	// ErrSingleSignOnDisabled is an error for when no external identity provider is configured
+	ErrSingleSignOnDisabled = errors.New("single sign-on is not configured")
+	// ErrInvalidSingleSignOnState is an error for when the single sign-on state is unknown, used or has expired
+	ErrInvalidSingleSignOnState = errors.New("single sign-on request is invalid or has expired")
+	// ErrInvalidExternalIdentity is an error for when the identity provider did not return a valid identity
+	ErrInvalidExternalIdentity = errors.New("identity provider did not confirm the sign in")
+	// ErrExternalEmailNotVerified is an error for when the identity provider has not verified the email of a new identity
+	ErrExternalEmailNotVerified = errors.New("identity provider has not verified the email address")
+	// ErrAccountLocked is an error for when logins are locked after too many failed attempts
+	ErrAccountLocked = errors.New("too many failed login attempts, try again later")
 	// ErrEmptyAuthorizationHeader is an error for when the authorization header is empty
 	ErrEmptyAuthorizationHeader = errors.New("authorization header is not provided")
 	// ErrInvalidAuthorizationHeader is an error for when the authorization header is invalid
 	ErrInvalidAuthorizationHeader = errors.New("authorization header format is invalid")
 	// ErrInvalidAuthorizationType is an error for when the authorization type is invalid
 	ErrInvalidAuthorizationType = errors.New("authorization type is not supported")
+	// ErrInvalidCSRFToken is an error for when a request authenticated with the session cookie has a missing or invalid csrf token
+	ErrInvalidCSRFToken = errors.New("csrf token is missing or invalid")
+	// ErrInvalidClientCertificate is an error for when the client certificate does not belong to a user
+	ErrInvalidClientCertificate = errors.New("client certificate does not belong to a user")
 	// ErrUnauthorized is an error for when the user is unauthorized
 	ErrUnauthorized = errors.New("user is unauthorized to access the resource")
+	// ErrRateLimited is an error for when a client sent too many requests
+	ErrRateLimited = errors.New("too many requests, try again later")
 	// ErrForbidden is an error for when the user is forbidden to access the resource
 	ErrForbidden = errors.New("user is forbidden to access the resource")
+	// ErrClientForbidden is an error for when a client authenticated with its own credentials calls a route that acts on behalf of a user
+	ErrClientForbidden = errors.New("this action requires a user, client credentials are not allowed")
+	// ErrAPIKeyForbidden is an error for when an action reserved to the user's own sessions is attempted with an api key
+	ErrAPIKeyForbidden = errors.New("this action is not allowed with an api key")
+	// ErrImpersonationForbidden is an error for when a sensitive action is attempted while impersonating a user
+	ErrImpersonationForbidden = errors.New("this action is not allowed while impersonating a user")
+	// ErrInvalidPolicy is an error for when the authorization policy cannot be loaded or evaluated
+	ErrInvalidPolicy = errors.New("authorization policy is invalid")
+	// ErrPolicyNotLoaded is an error for when no authorization policy has been loaded yet
+	ErrPolicyNotLoaded = errors.New("authorization policy is not loaded")
 )
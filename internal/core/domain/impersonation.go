@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Impersonation struct {
+	ID             uuid.UUID
+	ImpersonatorID uint64
+	UserID         uint64
+	Reason         string
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+type ImpersonatedRequest struct {
+	ID              uint64
+	ImpersonationID uuid.UUID
+	Method          string
+	Path            string
+	Status          int
+	IP              string
+	UserAgent       string
+	CreatedAt       time.Time
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+)
+
+type LockoutPolicy struct {
+	MaxAttempts        int64
+	MaxAttemptsPerIP   int64
+	AttemptWindow      time.Duration
+	LockoutDuration    time.Duration
+	MaxLockoutDuration time.Duration
+}
+
+// AccountLockedError is an error for when logins are locked after too many failed attempts,
+// it wraps ErrAccountLocked and tells when the next attempt may be made
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+// Error returns the message of the wrapped ErrAccountLocked
+func (e *AccountLockedError) Error() string {
+	return ErrAccountLocked.Error()
+}
+
+// Unwrap returns ErrAccountLocked
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
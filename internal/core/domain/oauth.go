@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+)
+
+// OAuthGrantType is an enum for the grant types of the token endpoint
+type OAuthGrantType string
+
+// OAuthGrantType enum values
+const (
+	AuthorizationCodeGrant OAuthGrantType = "authorization_code"
+	ClientCredentialsGrant OAuthGrantType = "client_credentials"
+)
+
+// CodeChallengeS256 is the only supported PKCE code challenge method
+const CodeChallengeS256 = "S256"
+
+type OAuthClient struct {
+	ID           uint64
+	ClientID     string
+	SecretHash   string
+	Name         string
+	RedirectURIs []string
+	Scopes       []string
+	Confidential bool
+	CreatedBy    *uint64
+	CreatedAt    time.Time
+}
+
+type OAuthConsent struct {
+	UserID    uint64
+	ClientID  uint64
+	Scopes    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type OAuthAuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	Scopes              []string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+type OAuthConsentPrompt struct {
+	Client          *OAuthClient
+	RedirectURI     string
+	Scopes          []string
+	ConsentRequired bool
+}
+
+type OAuthAuthorizationCode struct {
+	UserID        uint64
+	ClientID      string
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+}
+
+type OAuthTokenRequest struct {
+	GrantType    OAuthGrantType
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	Scopes       []string
+}
+
+type OAuthToken struct {
+	AccessToken string
+	TokenType   string
+	ExpiresIn   int64
+	Scopes      []string
+}
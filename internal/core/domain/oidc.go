@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+)
+
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type UserIdentity struct {
+	ID        uint64
+	UserID    uint64
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+type OIDCLoginState struct {
+	Nonce        string
+	CodeVerifier string
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"strings"
+)
+
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireUpper     bool
+	RequireLower     bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DenyPersonalInfo bool
+	DenyBreached     bool
+}
+
+// PasswordPolicyError is an error for when a password breaks the password policy,
+// it wraps ErrWeakPassword and lists every rule the password breaks
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+// Error returns the message of the wrapped ErrWeakPassword followed by the violations
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error() + ": " + strings.Join(e.Violations, ", ")
+}
+
+// Unwrap returns ErrWeakPassword
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+type PasswordResetToken struct {
+	ID        uint64
+	UserID    uint64
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
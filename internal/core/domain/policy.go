@@ -0,0 +1,61 @@
+package domain
+
+// PolicyEffect is an enum for the effect of a policy rule
+type PolicyEffect string
+
+// PolicyEffect enum values
+const (
+	Allow PolicyEffect = "allow"
+	Deny  PolicyEffect = "deny"
+)
+
+// ConditionOperator is an enum for the operator of a policy condition
+type ConditionOperator string
+
+// ConditionOperator enum values
+const (
+	OperatorEqual    ConditionOperator = "eq"
+	OperatorNotEqual ConditionOperator = "ne"
+	OperatorIn       ConditionOperator = "in"
+)
+
+type Policy struct {
+	Version string
+	Rules   []PolicyRule
+}
+
+type PolicyRule struct {
+	ID          string
+	Description string
+	Effect      PolicyEffect
+	Subjects    []string
+	Actions     []string
+	Resources   []string
+	Conditions  []PolicyCondition
+}
+
+type PolicyCondition struct {
+	Attribute string
+	Operator  ConditionOperator
+	Values    []string
+}
+
+type PolicyResource struct {
+	Type       string
+	ID         string
+	Attributes map[string]string
+}
+
+type PolicyDecision struct {
+	Allowed     bool
+	RuleID      string
+	Reason      string
+	Evaluations []PolicyEvaluation
+}
+
+type PolicyEvaluation struct {
+	RuleID  string
+	Effect  PolicyEffect
+	Matched bool
+	Reason  string
+}
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"crypto"
+)
+
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+)
+
+type RateLimit struct {
+	Name     string
+	Requests int64
+	Window   time.Duration
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
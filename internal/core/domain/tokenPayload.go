@@ -1,10 +1,25 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type TokenPayload struct {
-	ID     uuid.UUID
-	UserID uint64
+	ID             uuid.UUID
+	UserID         uint64
+	Generation     uint64
+	Issuer         string
+	Audience       string
+	Subject        string
+	Role           UserRole
+	Scopes         []string
+	TwoFactor      bool
+	APIKeyID       uint64
+	ClientID       string
+	SessionID      uuid.UUID
+	ImpersonatorID uint64
+	IssuedAt       time.Time
+	ExpiredAt      time.Time
 }
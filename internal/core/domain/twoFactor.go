@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+type TOTP struct {
+	UserID      uint64
+	Secret      string
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+}
+
+type TOTPKey struct {
+	Secret string
+	URI    string
+}
+
+type RecoveryCode struct {
+	ID        uint64
+	UserID    uint64
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -4,11 +4,23 @@ import (
 	"time"
 )
 
+// UserRole is an enum for user's role
+type UserRole string
+
+// UserRole enum values
+const (
+	Admin   UserRole = "admin"
+	Manager UserRole = "manager"
+	Cashier UserRole = "cashier"
+)
+
 type User struct {
-	ID        uint64
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              uint64
+	Name            string
+	Email           string
+	Password        string
+	Role            UserRole
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
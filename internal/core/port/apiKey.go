@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	ListAPIKeysByUserID(ctx context.Context, userID uint64) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, id uint64) error
+	TouchAPIKey(ctx context.Context, id uint64, usedAt time.Time) error
+}
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, actor *domain.TokenPayload, key *domain.APIKey) (*domain.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID uint64) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, id uint64) error
+	Authenticate(ctx context.Context, key string) (*domain.TokenPayload, error)
+}
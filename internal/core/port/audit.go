@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type AuditRepository interface {
+	CreateAuthEvent(ctx context.Context, event *domain.AuthEvent) (*domain.AuthEvent, error)
+	ListAuthEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]domain.AuthEvent, error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, event *domain.AuthEvent) error
+	ListEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]domain.AuthEvent, error)
+}
@@ -2,15 +2,45 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/google/uuid"
 )
 
 type TokenService interface {
-	CreateToken(user *domain.User) (string, error)
+	CreateToken(payload *domain.TokenPayload) (string, error)
 	VerifyToken(token string) (*domain.TokenPayload, error)
 }
 
+type PublicKeyProvider interface {
+	PublicKeys() []domain.PublicKey
+}
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error)
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uint64) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uint64) error
+}
+
+type LoginLimiter interface {
+	Check(ctx context.Context, email string) error
+	RegisterFailure(ctx context.Context, email string) error
+	RegisterSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email string) error
+}
+
 type AuthService interface {
-	Login(ctx context.Context, email, password string) (string, error)
+	Login(ctx context.Context, email, password string) (*domain.AuthToken, error)
+	LoginTwoFactor(ctx context.Context, challengeToken, code string) (*domain.AuthToken, error)
+	LoginExternal(ctx context.Context, user *domain.User) (*domain.AuthToken, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.AuthToken, error)
+	RefreshTokenSession(ctx context.Context, refreshToken string) (uuid.UUID, error)
+	VerifyToken(ctx context.Context, token string) (*domain.TokenPayload, error)
+	AuthenticateCertificate(ctx context.Context, email string, issuedAt time.Time) (*domain.TokenPayload, error)
+	Logout(ctx context.Context, payload *domain.TokenPayload, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uint64) error
+	Unlock(ctx context.Context, email string) error
 }
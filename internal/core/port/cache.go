@@ -10,5 +10,6 @@ type CacheRepository interface {
 	Get(ctx context.Context, key string) ([]byte, error)
 	Delete(ctx context.Context, key string) error
 	DeleteByPrefix(ctx context.Context, prefix string) error
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
 	Close() error
 }
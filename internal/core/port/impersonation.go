@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type ImpersonationRepository interface {
+	CreateImpersonation(ctx context.Context, impersonation *domain.Impersonation) (*domain.Impersonation, error)
+	CreateImpersonatedRequest(ctx context.Context, request *domain.ImpersonatedRequest) (*domain.ImpersonatedRequest, error)
+}
+
+type ImpersonationService interface {
+	Impersonate(ctx context.Context, actor *domain.TokenPayload, userID uint64, reason string) (*domain.Impersonation, string, error)
+	RecordRequest(ctx context.Context, request *domain.ImpersonatedRequest) error
+}
@@ -12,8 +12,10 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,18 +43,18 @@ func (m *MockTokenService) EXPECT() *MockTokenServiceMockRecorder {
 }
 
 // CreateToken mocks base method.
-func (m *MockTokenService) CreateToken(user *domain.User) (string, error) {
+func (m *MockTokenService) CreateToken(payload *domain.TokenPayload) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateToken", user)
+	ret := m.ctrl.Call(m, "CreateToken", payload)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateToken indicates an expected call of CreateToken.
-func (mr *MockTokenServiceMockRecorder) CreateToken(user any) *gomock.Call {
+func (mr *MockTokenServiceMockRecorder) CreateToken(payload any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockTokenService)(nil).CreateToken), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockTokenService)(nil).CreateToken), payload)
 }
 
 // VerifyToken mocks base method.
@@ -70,6 +72,217 @@ func (mr *MockTokenServiceMockRecorder) VerifyToken(token any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockTokenService)(nil).VerifyToken), token)
 }
 
+// MockPublicKeyProvider is a mock of PublicKeyProvider interface.
+type MockPublicKeyProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublicKeyProviderMockRecorder
+}
+
+// MockPublicKeyProviderMockRecorder is the mock recorder for MockPublicKeyProvider.
+type MockPublicKeyProviderMockRecorder struct {
+	mock *MockPublicKeyProvider
+}
+
+// NewMockPublicKeyProvider creates a new mock instance.
+func NewMockPublicKeyProvider(ctrl *gomock.Controller) *MockPublicKeyProvider {
+	mock := &MockPublicKeyProvider{ctrl: ctrl}
+	mock.recorder = &MockPublicKeyProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublicKeyProvider) EXPECT() *MockPublicKeyProviderMockRecorder {
+	return m.recorder
+}
+
+// PublicKeys mocks base method.
+func (m *MockPublicKeyProvider) PublicKeys() []domain.PublicKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].([]domain.PublicKey)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockPublicKeyProviderMockRecorder) PublicKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockPublicKeyProvider)(nil).PublicKeys))
+}
+
+// MockRefreshTokenRepository is a mock of RefreshTokenRepository interface.
+type MockRefreshTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenRepositoryMockRecorder
+}
+
+// MockRefreshTokenRepositoryMockRecorder is the mock recorder for MockRefreshTokenRepository.
+type MockRefreshTokenRepositoryMockRecorder struct {
+	mock *MockRefreshTokenRepository
+}
+
+// NewMockRefreshTokenRepository creates a new mock instance.
+func NewMockRefreshTokenRepository(ctrl *gomock.Controller) *MockRefreshTokenRepository {
+	mock := &MockRefreshTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenRepository) EXPECT() *MockRefreshTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, token)
+	ret0, _ := ret[0].(*domain.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockRefreshTokenRepositoryMockRecorder) CreateRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockRefreshTokenRepository)(nil).CreateRefreshToken), ctx, token)
+}
+
+// GetRefreshTokenByHash mocks base method.
+func (m *MockRefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*domain.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByHash indicates an expected call of GetRefreshTokenByHash.
+func (mr *MockRefreshTokenRepositoryMockRecorder) GetRefreshTokenByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByHash", reflect.TypeOf((*MockRefreshTokenRepository)(nil).GetRefreshTokenByHash), ctx, hash)
+}
+
+// MarkRefreshTokenUsed mocks base method.
+func (m *MockRefreshTokenRepository) MarkRefreshTokenUsed(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRefreshTokenUsed indicates an expected call of MarkRefreshTokenUsed.
+func (mr *MockRefreshTokenRepositoryMockRecorder) MarkRefreshTokenUsed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefreshTokenUsed", reflect.TypeOf((*MockRefreshTokenRepository)(nil).MarkRefreshTokenUsed), ctx, id)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockRefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeRefreshTokenFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeRefreshTokenFamily), ctx, familyID)
+}
+
+// RevokeUserRefreshTokens mocks base method.
+func (m *MockRefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRefreshTokens", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRefreshTokens indicates an expected call of RevokeUserRefreshTokens.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeUserRefreshTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRefreshTokens", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeUserRefreshTokens), ctx, userID)
+}
+
+// MockLoginLimiter is a mock of LoginLimiter interface.
+type MockLoginLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginLimiterMockRecorder
+}
+
+// MockLoginLimiterMockRecorder is the mock recorder for MockLoginLimiter.
+type MockLoginLimiterMockRecorder struct {
+	mock *MockLoginLimiter
+}
+
+// NewMockLoginLimiter creates a new mock instance.
+func NewMockLoginLimiter(ctrl *gomock.Controller) *MockLoginLimiter {
+	mock := &MockLoginLimiter{ctrl: ctrl}
+	mock.recorder = &MockLoginLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginLimiter) EXPECT() *MockLoginLimiterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginLimiter) Check(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginLimiterMockRecorder) Check(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginLimiter)(nil).Check), ctx, email)
+}
+
+// RegisterFailure mocks base method.
+func (m *MockLoginLimiter) RegisterFailure(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterFailure", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterFailure indicates an expected call of RegisterFailure.
+func (mr *MockLoginLimiterMockRecorder) RegisterFailure(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFailure", reflect.TypeOf((*MockLoginLimiter)(nil).RegisterFailure), ctx, email)
+}
+
+// RegisterSuccess mocks base method.
+func (m *MockLoginLimiter) RegisterSuccess(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterSuccess", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterSuccess indicates an expected call of RegisterSuccess.
+func (mr *MockLoginLimiterMockRecorder) RegisterSuccess(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSuccess", reflect.TypeOf((*MockLoginLimiter)(nil).RegisterSuccess), ctx, email)
+}
+
+// Unlock mocks base method.
+func (m *MockLoginLimiter) Unlock(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLoginLimiterMockRecorder) Unlock(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLoginLimiter)(nil).Unlock), ctx, email)
+}
+
 // MockAuthService is a mock of AuthService interface.
 type MockAuthService struct {
 	ctrl     *gomock.Controller
@@ -93,11 +306,26 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// AuthenticateCertificate mocks base method.
+func (m *MockAuthService) AuthenticateCertificate(ctx context.Context, email string, issuedAt time.Time) (*domain.TokenPayload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateCertificate", ctx, email, issuedAt)
+	ret0, _ := ret[0].(*domain.TokenPayload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateCertificate indicates an expected call of AuthenticateCertificate.
+func (mr *MockAuthServiceMockRecorder) AuthenticateCertificate(ctx, email, issuedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateCertificate", reflect.TypeOf((*MockAuthService)(nil).AuthenticateCertificate), ctx, email, issuedAt)
+}
+
 // Login mocks base method.
-func (m *MockAuthService) Login(ctx context.Context, email, password string) (string, error) {
+func (m *MockAuthService) Login(ctx context.Context, email, password string) (*domain.AuthToken, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login", ctx, email, password)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*domain.AuthToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -106,4 +334,121 @@ func (m *MockAuthService) Login(ctx context.Context, email, password string) (st
 func (mr *MockAuthServiceMockRecorder) Login(ctx, email, password any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), ctx, email, password)
+}
+
+// LoginExternal mocks base method.
+func (m *MockAuthService) LoginExternal(ctx context.Context, user *domain.User) (*domain.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginExternal", ctx, user)
+	ret0, _ := ret[0].(*domain.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginExternal indicates an expected call of LoginExternal.
+func (mr *MockAuthServiceMockRecorder) LoginExternal(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginExternal", reflect.TypeOf((*MockAuthService)(nil).LoginExternal), ctx, user)
+}
+
+// LoginTwoFactor mocks base method.
+func (m *MockAuthService) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*domain.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor", ctx, challengeToken, code)
+	ret0, _ := ret[0].(*domain.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginTwoFactor indicates an expected call of LoginTwoFactor.
+func (mr *MockAuthServiceMockRecorder) LoginTwoFactor(ctx, challengeToken, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockAuthService)(nil).LoginTwoFactor), ctx, challengeToken, code)
+}
+
+// Logout mocks base method.
+func (m *MockAuthService) Logout(ctx context.Context, payload *domain.TokenPayload, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, payload, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthServiceMockRecorder) Logout(ctx, payload, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthService)(nil).Logout), ctx, payload, refreshToken)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthService) LogoutAll(ctx context.Context, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthServiceMockRecorder) LogoutAll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthService)(nil).LogoutAll), ctx, userID)
+}
+
+// Refresh mocks base method.
+func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (*domain.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(*domain.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthServiceMockRecorder) Refresh(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthService)(nil).Refresh), ctx, refreshToken)
+}
+
+// RefreshTokenSession mocks base method.
+func (m *MockAuthService) RefreshTokenSession(ctx context.Context, refreshToken string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokenSession", ctx, refreshToken)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokenSession indicates an expected call of RefreshTokenSession.
+func (mr *MockAuthServiceMockRecorder) RefreshTokenSession(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokenSession", reflect.TypeOf((*MockAuthService)(nil).RefreshTokenSession), ctx, refreshToken)
+}
+
+// Unlock mocks base method.
+func (m *MockAuthService) Unlock(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockAuthServiceMockRecorder) Unlock(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockAuthService)(nil).Unlock), ctx, email)
+}
+
+// VerifyToken mocks base method.
+func (m *MockAuthService) VerifyToken(ctx context.Context, token string) (*domain.TokenPayload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyToken", ctx, token)
+	ret0, _ := ret[0].(*domain.TokenPayload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyToken indicates an expected call of VerifyToken.
+func (mr *MockAuthServiceMockRecorder) VerifyToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockAuthService)(nil).VerifyToken), ctx, token)
 }
\ No newline at end of file
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: impersonation.go
+//
+// Generated by this command:
+//
+//	mockgen -source=impersonation.go -destination=mock/impersonation.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImpersonationRepository is a mock of ImpersonationRepository interface.
+type MockImpersonationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImpersonationRepositoryMockRecorder
+}
+
+// MockImpersonationRepositoryMockRecorder is the mock recorder for MockImpersonationRepository.
+type MockImpersonationRepositoryMockRecorder struct {
+	mock *MockImpersonationRepository
+}
+
+// NewMockImpersonationRepository creates a new mock instance.
+func NewMockImpersonationRepository(ctrl *gomock.Controller) *MockImpersonationRepository {
+	mock := &MockImpersonationRepository{ctrl: ctrl}
+	mock.recorder = &MockImpersonationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImpersonationRepository) EXPECT() *MockImpersonationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateImpersonatedRequest mocks base method.
+func (m *MockImpersonationRepository) CreateImpersonatedRequest(ctx context.Context, request *domain.ImpersonatedRequest) (*domain.ImpersonatedRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImpersonatedRequest", ctx, request)
+	ret0, _ := ret[0].(*domain.ImpersonatedRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImpersonatedRequest indicates an expected call of CreateImpersonatedRequest.
+func (mr *MockImpersonationRepositoryMockRecorder) CreateImpersonatedRequest(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImpersonatedRequest", reflect.TypeOf((*MockImpersonationRepository)(nil).CreateImpersonatedRequest), ctx, request)
+}
+
+// CreateImpersonation mocks base method.
+func (m *MockImpersonationRepository) CreateImpersonation(ctx context.Context, impersonation *domain.Impersonation) (*domain.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImpersonation", ctx, impersonation)
+	ret0, _ := ret[0].(*domain.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImpersonation indicates an expected call of CreateImpersonation.
+func (mr *MockImpersonationRepositoryMockRecorder) CreateImpersonation(ctx, impersonation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImpersonation", reflect.TypeOf((*MockImpersonationRepository)(nil).CreateImpersonation), ctx, impersonation)
+}
+
+// MockImpersonationService is a mock of ImpersonationService interface.
+type MockImpersonationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImpersonationServiceMockRecorder
+}
+
+// MockImpersonationServiceMockRecorder is the mock recorder for MockImpersonationService.
+type MockImpersonationServiceMockRecorder struct {
+	mock *MockImpersonationService
+}
+
+// NewMockImpersonationService creates a new mock instance.
+func NewMockImpersonationService(ctrl *gomock.Controller) *MockImpersonationService {
+	mock := &MockImpersonationService{ctrl: ctrl}
+	mock.recorder = &MockImpersonationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImpersonationService) EXPECT() *MockImpersonationServiceMockRecorder {
+	return m.recorder
+}
+
+// Impersonate mocks base method.
+func (m *MockImpersonationService) Impersonate(ctx context.Context, actor *domain.TokenPayload, userID uint64, reason string) (*domain.Impersonation, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", ctx, actor, userID, reason)
+	ret0, _ := ret[0].(*domain.Impersonation)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockImpersonationServiceMockRecorder) Impersonate(ctx, actor, userID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockImpersonationService)(nil).Impersonate), ctx, actor, userID, reason)
+}
+
+// RecordRequest mocks base method.
+func (m *MockImpersonationService) RecordRequest(ctx context.Context, request *domain.ImpersonatedRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRequest", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordRequest indicates an expected call of RecordRequest.
+func (mr *MockImpersonationServiceMockRecorder) RecordRequest(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRequest", reflect.TypeOf((*MockImpersonationService)(nil).RecordRequest), ctx, request)
+}
\ No newline at end of file
@@ -0,0 +1,257 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: oauth.go
+//
+// Generated by this command:
+//
+//	mockgen -source=oauth.go -destination=mock/oauth.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthClientRepository is a mock of OAuthClientRepository interface.
+type MockOAuthClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthClientRepositoryMockRecorder
+}
+
+// MockOAuthClientRepositoryMockRecorder is the mock recorder for MockOAuthClientRepository.
+type MockOAuthClientRepositoryMockRecorder struct {
+	mock *MockOAuthClientRepository
+}
+
+// NewMockOAuthClientRepository creates a new mock instance.
+func NewMockOAuthClientRepository(ctrl *gomock.Controller) *MockOAuthClientRepository {
+	mock := &MockOAuthClientRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthClientRepository) EXPECT() *MockOAuthClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateOAuthClient mocks base method.
+func (m *MockOAuthClientRepository) CreateOAuthClient(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuthClient", ctx, client)
+	ret0, _ := ret[0].(*domain.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOAuthClient indicates an expected call of CreateOAuthClient.
+func (mr *MockOAuthClientRepositoryMockRecorder) CreateOAuthClient(ctx, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthClient", reflect.TypeOf((*MockOAuthClientRepository)(nil).CreateOAuthClient), ctx, client)
+}
+
+// DeleteOAuthClient mocks base method.
+func (m *MockOAuthClientRepository) DeleteOAuthClient(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuthClient", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuthClient indicates an expected call of DeleteOAuthClient.
+func (mr *MockOAuthClientRepositoryMockRecorder) DeleteOAuthClient(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuthClient", reflect.TypeOf((*MockOAuthClientRepository)(nil).DeleteOAuthClient), ctx, id)
+}
+
+// GetOAuthClientByClientID mocks base method.
+func (m *MockOAuthClientRepository) GetOAuthClientByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthClientByClientID", ctx, clientID)
+	ret0, _ := ret[0].(*domain.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthClientByClientID indicates an expected call of GetOAuthClientByClientID.
+func (mr *MockOAuthClientRepositoryMockRecorder) GetOAuthClientByClientID(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthClientByClientID", reflect.TypeOf((*MockOAuthClientRepository)(nil).GetOAuthClientByClientID), ctx, clientID)
+}
+
+// GetOAuthConsent mocks base method.
+func (m *MockOAuthClientRepository) GetOAuthConsent(ctx context.Context, userID, clientID uint64) (*domain.OAuthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthConsent", ctx, userID, clientID)
+	ret0, _ := ret[0].(*domain.OAuthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthConsent indicates an expected call of GetOAuthConsent.
+func (mr *MockOAuthClientRepositoryMockRecorder) GetOAuthConsent(ctx, userID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthConsent", reflect.TypeOf((*MockOAuthClientRepository)(nil).GetOAuthConsent), ctx, userID, clientID)
+}
+
+// ListOAuthClients mocks base method.
+func (m *MockOAuthClientRepository) ListOAuthClients(ctx context.Context) ([]domain.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOAuthClients", ctx)
+	ret0, _ := ret[0].([]domain.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOAuthClients indicates an expected call of ListOAuthClients.
+func (mr *MockOAuthClientRepositoryMockRecorder) ListOAuthClients(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOAuthClients", reflect.TypeOf((*MockOAuthClientRepository)(nil).ListOAuthClients), ctx)
+}
+
+// SaveOAuthConsent mocks base method.
+func (m *MockOAuthClientRepository) SaveOAuthConsent(ctx context.Context, consent *domain.OAuthConsent) (*domain.OAuthConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuthConsent", ctx, consent)
+	ret0, _ := ret[0].(*domain.OAuthConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveOAuthConsent indicates an expected call of SaveOAuthConsent.
+func (mr *MockOAuthClientRepositoryMockRecorder) SaveOAuthConsent(ctx, consent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuthConsent", reflect.TypeOf((*MockOAuthClientRepository)(nil).SaveOAuthConsent), ctx, consent)
+}
+
+// MockOAuthService is a mock of OAuthService interface.
+type MockOAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthServiceMockRecorder
+}
+
+// MockOAuthServiceMockRecorder is the mock recorder for MockOAuthService.
+type MockOAuthServiceMockRecorder struct {
+	mock *MockOAuthService
+}
+
+// NewMockOAuthService creates a new mock instance.
+func NewMockOAuthService(ctrl *gomock.Controller) *MockOAuthService {
+	mock := &MockOAuthService{ctrl: ctrl}
+	mock.recorder = &MockOAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthService) EXPECT() *MockOAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockOAuthService) Authorize(ctx context.Context, actor *domain.TokenPayload, req *domain.OAuthAuthorizationRequest, approved bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, actor, req, approved)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOAuthServiceMockRecorder) Authorize(ctx, actor, req, approved any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOAuthService)(nil).Authorize), ctx, actor, req, approved)
+}
+
+// DeleteClient mocks base method.
+func (m *MockOAuthService) DeleteClient(ctx context.Context, clientID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, clientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockOAuthServiceMockRecorder) DeleteClient(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockOAuthService)(nil).DeleteClient), ctx, clientID)
+}
+
+// ListClients mocks base method.
+func (m *MockOAuthService) ListClients(ctx context.Context) ([]domain.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClients", ctx)
+	ret0, _ := ret[0].([]domain.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClients indicates an expected call of ListClients.
+func (mr *MockOAuthServiceMockRecorder) ListClients(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClients", reflect.TypeOf((*MockOAuthService)(nil).ListClients), ctx)
+}
+
+// PrepareAuthorization mocks base method.
+func (m *MockOAuthService) PrepareAuthorization(ctx context.Context, actor *domain.TokenPayload, req *domain.OAuthAuthorizationRequest) (*domain.OAuthConsentPrompt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepareAuthorization", ctx, actor, req)
+	ret0, _ := ret[0].(*domain.OAuthConsentPrompt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepareAuthorization indicates an expected call of PrepareAuthorization.
+func (mr *MockOAuthServiceMockRecorder) PrepareAuthorization(ctx, actor, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareAuthorization", reflect.TypeOf((*MockOAuthService)(nil).PrepareAuthorization), ctx, actor, req)
+}
+
+// RegisterClient mocks base method.
+func (m *MockOAuthService) RegisterClient(ctx context.Context, actor *domain.TokenPayload, client *domain.OAuthClient) (*domain.OAuthClient, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterClient", ctx, actor, client)
+	ret0, _ := ret[0].(*domain.OAuthClient)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterClient indicates an expected call of RegisterClient.
+func (mr *MockOAuthServiceMockRecorder) RegisterClient(ctx, actor, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClient", reflect.TypeOf((*MockOAuthService)(nil).RegisterClient), ctx, actor, client)
+}
+
+// Revoke mocks base method.
+func (m *MockOAuthService) Revoke(ctx context.Context, clientID, clientSecret, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, clientID, clientSecret, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockOAuthServiceMockRecorder) Revoke(ctx, clientID, clientSecret, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockOAuthService)(nil).Revoke), ctx, clientID, clientSecret, token)
+}
+
+// Token mocks base method.
+func (m *MockOAuthService) Token(ctx context.Context, req *domain.OAuthTokenRequest) (*domain.OAuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx, req)
+	ret0, _ := ret[0].(*domain.OAuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOAuthServiceMockRecorder) Token(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOAuthService)(nil).Token), ctx, req)
+}
\ No newline at end of file
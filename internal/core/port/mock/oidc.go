@@ -0,0 +1,205 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: oidc.go
+//
+// Generated by this command:
+//
+//	mockgen -source=oidc.go -destination=mock/oidc.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityProvider is a mock of IdentityProvider interface.
+type MockIdentityProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityProviderMockRecorder
+}
+
+// MockIdentityProviderMockRecorder is the mock recorder for MockIdentityProvider.
+type MockIdentityProviderMockRecorder struct {
+	mock *MockIdentityProvider
+}
+
+// NewMockIdentityProvider creates a new mock instance.
+func NewMockIdentityProvider(ctrl *gomock.Controller) *MockIdentityProvider {
+	mock := &MockIdentityProvider{ctrl: ctrl}
+	mock.recorder = &MockIdentityProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityProvider) EXPECT() *MockIdentityProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockIdentityProvider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", state, nonce, codeVerifier)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockIdentityProviderMockRecorder) AuthCodeURL(state, nonce, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockIdentityProvider)(nil).AuthCodeURL), state, nonce, codeVerifier)
+}
+
+// Exchange mocks base method.
+func (m *MockIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, codeVerifier, nonce)
+	ret0, _ := ret[0].(*domain.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIdentityProviderMockRecorder) Exchange(ctx, code, codeVerifier, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIdentityProvider)(nil).Exchange), ctx, code, codeVerifier, nonce)
+}
+
+// Name mocks base method.
+func (m *MockIdentityProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockIdentityProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockIdentityProvider)(nil).Name))
+}
+
+// MockUserIdentityRepository is a mock of UserIdentityRepository interface.
+type MockUserIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserIdentityRepositoryMockRecorder
+}
+
+// MockUserIdentityRepositoryMockRecorder is the mock recorder for MockUserIdentityRepository.
+type MockUserIdentityRepositoryMockRecorder struct {
+	mock *MockUserIdentityRepository
+}
+
+// NewMockUserIdentityRepository creates a new mock instance.
+func NewMockUserIdentityRepository(ctrl *gomock.Controller) *MockUserIdentityRepository {
+	mock := &MockUserIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockUserIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserIdentityRepository) EXPECT() *MockUserIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateUserIdentity mocks base method.
+func (m *MockUserIdentityRepository) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserIdentity", ctx, identity)
+	ret0, _ := ret[0].(*domain.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserIdentity indicates an expected call of CreateUserIdentity.
+func (mr *MockUserIdentityRepositoryMockRecorder) CreateUserIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserIdentity", reflect.TypeOf((*MockUserIdentityRepository)(nil).CreateUserIdentity), ctx, identity)
+}
+
+// DeleteUserIdentity mocks base method.
+func (m *MockUserIdentityRepository) DeleteUserIdentity(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserIdentity", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserIdentity indicates an expected call of DeleteUserIdentity.
+func (mr *MockUserIdentityRepositoryMockRecorder) DeleteUserIdentity(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserIdentity", reflect.TypeOf((*MockUserIdentityRepository)(nil).DeleteUserIdentity), ctx, id)
+}
+
+// GetUserIdentity mocks base method.
+func (m *MockUserIdentityRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*domain.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentity indicates an expected call of GetUserIdentity.
+func (mr *MockUserIdentityRepositoryMockRecorder) GetUserIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentity", reflect.TypeOf((*MockUserIdentityRepository)(nil).GetUserIdentity), ctx, provider, subject)
+}
+
+// MockOIDCService is a mock of OIDCService interface.
+type MockOIDCService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCServiceMockRecorder
+}
+
+// MockOIDCServiceMockRecorder is the mock recorder for MockOIDCService.
+type MockOIDCServiceMockRecorder struct {
+	mock *MockOIDCService
+}
+
+// NewMockOIDCService creates a new mock instance.
+func NewMockOIDCService(ctrl *gomock.Controller) *MockOIDCService {
+	mock := &MockOIDCService{ctrl: ctrl}
+	mock.recorder = &MockOIDCServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCService) EXPECT() *MockOIDCServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockOIDCService) Begin(ctx context.Context) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockOIDCServiceMockRecorder) Begin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockOIDCService)(nil).Begin), ctx)
+}
+
+// Complete mocks base method.
+func (m *MockOIDCService) Complete(ctx context.Context, state, code string) (*domain.AuthToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, state, code)
+	ret0, _ := ret[0].(*domain.AuthToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockOIDCServiceMockRecorder) Complete(ctx, state, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockOIDCService)(nil).Complete), ctx, state, code)
+}
\ No newline at end of file
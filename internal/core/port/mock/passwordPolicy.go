@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: passwordPolicy.go
+//
+// Generated by this command:
+//
+//	mockgen -source=passwordPolicy.go -destination=mock/passwordPolicy.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBreachedPasswordRepository is a mock of BreachedPasswordRepository interface.
+type MockBreachedPasswordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBreachedPasswordRepositoryMockRecorder
+}
+
+// MockBreachedPasswordRepositoryMockRecorder is the mock recorder for MockBreachedPasswordRepository.
+type MockBreachedPasswordRepositoryMockRecorder struct {
+	mock *MockBreachedPasswordRepository
+}
+
+// NewMockBreachedPasswordRepository creates a new mock instance.
+func NewMockBreachedPasswordRepository(ctrl *gomock.Controller) *MockBreachedPasswordRepository {
+	mock := &MockBreachedPasswordRepository{ctrl: ctrl}
+	mock.recorder = &MockBreachedPasswordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBreachedPasswordRepository) EXPECT() *MockBreachedPasswordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ContainsHash mocks base method.
+func (m *MockBreachedPasswordRepository) ContainsHash(ctx context.Context, hash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainsHash", ctx, hash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainsHash indicates an expected call of ContainsHash.
+func (mr *MockBreachedPasswordRepositoryMockRecorder) ContainsHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainsHash", reflect.TypeOf((*MockBreachedPasswordRepository)(nil).ContainsHash), ctx, hash)
+}
+
+// MockPasswordPolicyService is a mock of PasswordPolicyService interface.
+type MockPasswordPolicyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordPolicyServiceMockRecorder
+}
+
+// MockPasswordPolicyServiceMockRecorder is the mock recorder for MockPasswordPolicyService.
+type MockPasswordPolicyServiceMockRecorder struct {
+	mock *MockPasswordPolicyService
+}
+
+// NewMockPasswordPolicyService creates a new mock instance.
+func NewMockPasswordPolicyService(ctrl *gomock.Controller) *MockPasswordPolicyService {
+	mock := &MockPasswordPolicyService{ctrl: ctrl}
+	mock.recorder = &MockPasswordPolicyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordPolicyService) EXPECT() *MockPasswordPolicyServiceMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method.
+func (m *MockPasswordPolicyService) Validate(ctx context.Context, password string, user *domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", ctx, password, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockPasswordPolicyServiceMockRecorder) Validate(ctx, password, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockPasswordPolicyService)(nil).Validate), ctx, password, user)
+}
\ No newline at end of file
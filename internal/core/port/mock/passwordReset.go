@@ -0,0 +1,150 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: passwordReset.go
+//
+// Generated by this command:
+//
+//	mockgen -source=passwordReset.go -destination=mock/passwordReset.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPasswordResetRepository is a mock of PasswordResetRepository interface.
+type MockPasswordResetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetRepositoryMockRecorder
+}
+
+// MockPasswordResetRepositoryMockRecorder is the mock recorder for MockPasswordResetRepository.
+type MockPasswordResetRepositoryMockRecorder struct {
+	mock *MockPasswordResetRepository
+}
+
+// NewMockPasswordResetRepository creates a new mock instance.
+func NewMockPasswordResetRepository(ctrl *gomock.Controller) *MockPasswordResetRepository {
+	mock := &MockPasswordResetRepository{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetRepository) EXPECT() *MockPasswordResetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePasswordResetToken mocks base method.
+func (m *MockPasswordResetRepository) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetToken", ctx, token)
+	ret0, _ := ret[0].(*domain.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordResetToken indicates an expected call of CreatePasswordResetToken.
+func (mr *MockPasswordResetRepositoryMockRecorder) CreatePasswordResetToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetToken", reflect.TypeOf((*MockPasswordResetRepository)(nil).CreatePasswordResetToken), ctx, token)
+}
+
+// GetPasswordResetTokenByHash mocks base method.
+func (m *MockPasswordResetRepository) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetTokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*domain.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetTokenByHash indicates an expected call of GetPasswordResetTokenByHash.
+func (mr *MockPasswordResetRepositoryMockRecorder) GetPasswordResetTokenByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetTokenByHash", reflect.TypeOf((*MockPasswordResetRepository)(nil).GetPasswordResetTokenByHash), ctx, hash)
+}
+
+// InvalidateUserPasswordResetTokens mocks base method.
+func (m *MockPasswordResetRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateUserPasswordResetTokens", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateUserPasswordResetTokens indicates an expected call of InvalidateUserPasswordResetTokens.
+func (mr *MockPasswordResetRepositoryMockRecorder) InvalidateUserPasswordResetTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateUserPasswordResetTokens", reflect.TypeOf((*MockPasswordResetRepository)(nil).InvalidateUserPasswordResetTokens), ctx, userID)
+}
+
+// MarkPasswordResetTokenUsed mocks base method.
+func (m *MockPasswordResetRepository) MarkPasswordResetTokenUsed(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPasswordResetTokenUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPasswordResetTokenUsed indicates an expected call of MarkPasswordResetTokenUsed.
+func (mr *MockPasswordResetRepositoryMockRecorder) MarkPasswordResetTokenUsed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPasswordResetTokenUsed", reflect.TypeOf((*MockPasswordResetRepository)(nil).MarkPasswordResetTokenUsed), ctx, id)
+}
+
+// MockPasswordResetService is a mock of PasswordResetService interface.
+type MockPasswordResetService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetServiceMockRecorder
+}
+
+// MockPasswordResetServiceMockRecorder is the mock recorder for MockPasswordResetService.
+type MockPasswordResetServiceMockRecorder struct {
+	mock *MockPasswordResetService
+}
+
+// NewMockPasswordResetService creates a new mock instance.
+func NewMockPasswordResetService(ctrl *gomock.Controller) *MockPasswordResetService {
+	mock := &MockPasswordResetService{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetService) EXPECT() *MockPasswordResetServiceMockRecorder {
+	return m.recorder
+}
+
+// CompleteReset mocks base method.
+func (m *MockPasswordResetService) CompleteReset(ctx context.Context, token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteReset", ctx, token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteReset indicates an expected call of CompleteReset.
+func (mr *MockPasswordResetServiceMockRecorder) CompleteReset(ctx, token, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteReset", reflect.TypeOf((*MockPasswordResetService)(nil).CompleteReset), ctx, token, password)
+}
+
+// RequestReset mocks base method.
+func (m *MockPasswordResetService) RequestReset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestReset indicates an expected call of RequestReset.
+func (mr *MockPasswordResetServiceMockRecorder) RequestReset(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReset", reflect.TypeOf((*MockPasswordResetService)(nil).RequestReset), ctx, email)
+}
\ No newline at end of file
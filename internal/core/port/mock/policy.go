@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: policy.go
+//
+// Generated by this command:
+//
+//	mockgen -source=policy.go -destination=mock/policy.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPolicyRepository is a mock of PolicyRepository interface.
+type MockPolicyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyRepositoryMockRecorder
+}
+
+// MockPolicyRepositoryMockRecorder is the mock recorder for MockPolicyRepository.
+type MockPolicyRepositoryMockRecorder struct {
+	mock *MockPolicyRepository
+}
+
+// NewMockPolicyRepository creates a new mock instance.
+func NewMockPolicyRepository(ctrl *gomock.Controller) *MockPolicyRepository {
+	mock := &MockPolicyRepository{ctrl: ctrl}
+	mock.recorder = &MockPolicyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyRepository) EXPECT() *MockPolicyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPolicy mocks base method.
+func (m *MockPolicyRepository) GetPolicy(ctx context.Context) (*domain.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", ctx)
+	ret0, _ := ret[0].(*domain.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockPolicyRepositoryMockRecorder) GetPolicy(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockPolicyRepository)(nil).GetPolicy), ctx)
+}
+
+// MockAuthorizer is a mock of Authorizer interface.
+type MockAuthorizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizerMockRecorder
+}
+
+// MockAuthorizerMockRecorder is the mock recorder for MockAuthorizer.
+type MockAuthorizerMockRecorder struct {
+	mock *MockAuthorizer
+}
+
+// NewMockAuthorizer creates a new mock instance.
+func NewMockAuthorizer(ctrl *gomock.Controller) *MockAuthorizer {
+	mock := &MockAuthorizer{ctrl: ctrl}
+	mock.recorder = &MockAuthorizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizer) EXPECT() *MockAuthorizerMockRecorder {
+	return m.recorder
+}
+
+// Can mocks base method.
+func (m *MockAuthorizer) Can(ctx context.Context, principal *domain.TokenPayload, action string, resource *domain.PolicyResource) (*domain.PolicyDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Can", ctx, principal, action, resource)
+	ret0, _ := ret[0].(*domain.PolicyDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Can indicates an expected call of Can.
+func (mr *MockAuthorizerMockRecorder) Can(ctx, principal, action, resource any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Can", reflect.TypeOf((*MockAuthorizer)(nil).Can), ctx, principal, action, resource)
+}
+
+// Reload mocks base method.
+func (m *MockAuthorizer) Reload(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockAuthorizerMockRecorder) Reload(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockAuthorizer)(nil).Reload), ctx)
+}
\ No newline at end of file
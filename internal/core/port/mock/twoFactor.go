@@ -0,0 +1,343 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: twoFactor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=twoFactor.go -destination=mock/twoFactor.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTwoFactorRepository is a mock of TwoFactorRepository interface.
+type MockTwoFactorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorRepositoryMockRecorder
+}
+
+// MockTwoFactorRepositoryMockRecorder is the mock recorder for MockTwoFactorRepository.
+type MockTwoFactorRepositoryMockRecorder struct {
+	mock *MockTwoFactorRepository
+}
+
+// NewMockTwoFactorRepository creates a new mock instance.
+func NewMockTwoFactorRepository(ctrl *gomock.Controller) *MockTwoFactorRepository {
+	mock := &MockTwoFactorRepository{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorRepository) EXPECT() *MockTwoFactorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockTwoFactorRepository) ConfirmTOTP(ctx context.Context, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockTwoFactorRepositoryMockRecorder) ConfirmTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockTwoFactorRepository)(nil).ConfirmTOTP), ctx, userID)
+}
+
+// DeleteTOTP mocks base method.
+func (m *MockTwoFactorRepository) DeleteTOTP(ctx context.Context, userID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTP", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTP indicates an expected call of DeleteTOTP.
+func (mr *MockTwoFactorRepositoryMockRecorder) DeleteTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTP", reflect.TypeOf((*MockTwoFactorRepository)(nil).DeleteTOTP), ctx, userID)
+}
+
+// GetTOTPByUserID mocks base method.
+func (m *MockTwoFactorRepository) GetTOTPByUserID(ctx context.Context, userID uint64) (*domain.TOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTOTPByUserID", ctx, userID)
+	ret0, _ := ret[0].(*domain.TOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTOTPByUserID indicates an expected call of GetTOTPByUserID.
+func (mr *MockTwoFactorRepositoryMockRecorder) GetTOTPByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTOTPByUserID", reflect.TypeOf((*MockTwoFactorRepository)(nil).GetTOTPByUserID), ctx, userID)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint64, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockTwoFactorRepositoryMockRecorder) ReplaceRecoveryCodes(ctx, userID, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockTwoFactorRepository)(nil).ReplaceRecoveryCodes), ctx, userID, codeHashes)
+}
+
+// SaveTOTP mocks base method.
+func (m *MockTwoFactorRepository) SaveTOTP(ctx context.Context, totp *domain.TOTP) (*domain.TOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTOTP", ctx, totp)
+	ret0, _ := ret[0].(*domain.TOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveTOTP indicates an expected call of SaveTOTP.
+func (mr *MockTwoFactorRepositoryMockRecorder) SaveTOTP(ctx, totp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTP", reflect.TypeOf((*MockTwoFactorRepository)(nil).SaveTOTP), ctx, totp)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorRepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactorRepository)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// MockTOTPGenerator is a mock of TOTPGenerator interface.
+type MockTOTPGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPGeneratorMockRecorder
+}
+
+// MockTOTPGeneratorMockRecorder is the mock recorder for MockTOTPGenerator.
+type MockTOTPGeneratorMockRecorder struct {
+	mock *MockTOTPGenerator
+}
+
+// NewMockTOTPGenerator creates a new mock instance.
+func NewMockTOTPGenerator(ctrl *gomock.Controller) *MockTOTPGenerator {
+	mock := &MockTOTPGenerator{ctrl: ctrl}
+	mock.recorder = &MockTOTPGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPGenerator) EXPECT() *MockTOTPGeneratorMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockTOTPGenerator) Generate(accountName string) (*domain.TOTPKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", accountName)
+	ret0, _ := ret[0].(*domain.TOTPKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockTOTPGeneratorMockRecorder) Generate(accountName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockTOTPGenerator)(nil).Generate), accountName)
+}
+
+// Validate mocks base method.
+func (m *MockTOTPGenerator) Validate(code, secret string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", code, secret)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockTOTPGeneratorMockRecorder) Validate(code, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTOTPGenerator)(nil).Validate), code, secret)
+}
+
+// MockSecretCipher is a mock of SecretCipher interface.
+type MockSecretCipher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretCipherMockRecorder
+}
+
+// MockSecretCipherMockRecorder is the mock recorder for MockSecretCipher.
+type MockSecretCipherMockRecorder struct {
+	mock *MockSecretCipher
+}
+
+// NewMockSecretCipher creates a new mock instance.
+func NewMockSecretCipher(ctrl *gomock.Controller) *MockSecretCipher {
+	mock := &MockSecretCipher{ctrl: ctrl}
+	mock.recorder = &MockSecretCipherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretCipher) EXPECT() *MockSecretCipherMockRecorder {
+	return m.recorder
+}
+
+// Decrypt mocks base method.
+func (m *MockSecretCipher) Decrypt(ciphertext string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrypt", ciphertext)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrypt indicates an expected call of Decrypt.
+func (mr *MockSecretCipherMockRecorder) Decrypt(ciphertext any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockSecretCipher)(nil).Decrypt), ciphertext)
+}
+
+// Encrypt mocks base method.
+func (m *MockSecretCipher) Encrypt(plaintext string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encrypt", plaintext)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encrypt indicates an expected call of Encrypt.
+func (mr *MockSecretCipherMockRecorder) Encrypt(plaintext any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockSecretCipher)(nil).Encrypt), plaintext)
+}
+
+// MockTwoFactorService is a mock of TwoFactorService interface.
+type MockTwoFactorService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorServiceMockRecorder
+}
+
+// MockTwoFactorServiceMockRecorder is the mock recorder for MockTwoFactorService.
+type MockTwoFactorServiceMockRecorder struct {
+	mock *MockTwoFactorService
+}
+
+// NewMockTwoFactorService creates a new mock instance.
+func NewMockTwoFactorService(ctrl *gomock.Controller) *MockTwoFactorService {
+	mock := &MockTwoFactorService{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorService) EXPECT() *MockTwoFactorServiceMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockTwoFactorService) Confirm(ctx context.Context, userID uint64, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockTwoFactorServiceMockRecorder) Confirm(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockTwoFactorService)(nil).Confirm), ctx, userID, code)
+}
+
+// Disable mocks base method.
+func (m *MockTwoFactorService) Disable(ctx context.Context, userID uint64, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorServiceMockRecorder) Disable(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactorService)(nil).Disable), ctx, userID, code)
+}
+
+// Enroll mocks base method.
+func (m *MockTwoFactorService) Enroll(ctx context.Context, userID uint64) (*domain.TOTPKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, userID)
+	ret0, _ := ret[0].(*domain.TOTPKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockTwoFactorServiceMockRecorder) Enroll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockTwoFactorService)(nil).Enroll), ctx, userID)
+}
+
+// IsEnabled mocks base method.
+func (m *MockTwoFactorService) IsEnabled(ctx context.Context, userID uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEnabled", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEnabled indicates an expected call of IsEnabled.
+func (mr *MockTwoFactorServiceMockRecorder) IsEnabled(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEnabled", reflect.TypeOf((*MockTwoFactorService)(nil).IsEnabled), ctx, userID)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockTwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockTwoFactorServiceMockRecorder) RegenerateRecoveryCodes(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockTwoFactorService)(nil).RegenerateRecoveryCodes), ctx, userID, code)
+}
+
+// Verify mocks base method.
+func (m *MockTwoFactorService) Verify(ctx context.Context, userID uint64, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockTwoFactorServiceMockRecorder) Verify(ctx, userID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockTwoFactorService)(nil).Verify), ctx, userID, code)
+}
\ No newline at end of file
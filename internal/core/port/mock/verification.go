@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: verification.go
+//
+// Generated by this command:
+//
+//	mockgen -source=verification.go -destination=mock/verification.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/cidmiranda/go-ws/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVerificationService is a mock of VerificationService interface.
+type MockVerificationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerificationServiceMockRecorder
+}
+
+// MockVerificationServiceMockRecorder is the mock recorder for MockVerificationService.
+type MockVerificationServiceMockRecorder struct {
+	mock *MockVerificationService
+}
+
+// NewMockVerificationService creates a new mock instance.
+func NewMockVerificationService(ctrl *gomock.Controller) *MockVerificationService {
+	mock := &MockVerificationService{ctrl: ctrl}
+	mock.recorder = &MockVerificationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVerificationService) EXPECT() *MockVerificationServiceMockRecorder {
+	return m.recorder
+}
+
+// ResendVerification mocks base method.
+func (m *MockVerificationService) ResendVerification(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockVerificationServiceMockRecorder) ResendVerification(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockVerificationService)(nil).ResendVerification), ctx, email)
+}
+
+// SendVerification mocks base method.
+func (m *MockVerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerification", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendVerification indicates an expected call of SendVerification.
+func (mr *MockVerificationServiceMockRecorder) SendVerification(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerification", reflect.TypeOf((*MockVerificationService)(nil).SendVerification), ctx, user)
+}
+
+// Verify mocks base method.
+func (m *MockVerificationService) Verify(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockVerificationServiceMockRecorder) Verify(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockVerificationService)(nil).Verify), ctx, token)
+}
\ No newline at end of file
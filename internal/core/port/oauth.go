@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type OAuthClientRepository interface {
+	CreateOAuthClient(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error)
+	GetOAuthClientByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error)
+	ListOAuthClients(ctx context.Context) ([]domain.OAuthClient, error)
+	DeleteOAuthClient(ctx context.Context, id uint64) error
+	GetOAuthConsent(ctx context.Context, userID, clientID uint64) (*domain.OAuthConsent, error)
+	SaveOAuthConsent(ctx context.Context, consent *domain.OAuthConsent) (*domain.OAuthConsent, error)
+}
+
+type OAuthService interface {
+	RegisterClient(ctx context.Context, actor *domain.TokenPayload, client *domain.OAuthClient) (*domain.OAuthClient, string, error)
+	ListClients(ctx context.Context) ([]domain.OAuthClient, error)
+	DeleteClient(ctx context.Context, clientID string) error
+	PrepareAuthorization(ctx context.Context, actor *domain.TokenPayload, req *domain.OAuthAuthorizationRequest) (*domain.OAuthConsentPrompt, error)
+	Authorize(ctx context.Context, actor *domain.TokenPayload, req *domain.OAuthAuthorizationRequest, approved bool) (string, error)
+	Token(ctx context.Context, req *domain.OAuthTokenRequest) (*domain.OAuthToken, error)
+	Revoke(ctx context.Context, clientID, clientSecret, token string) error
+}
@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalIdentity, error)
+}
+
+type UserIdentityRepository interface {
+	CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error)
+	GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	DeleteUserIdentity(ctx context.Context, id uint64) error
+}
+
+type OIDCService interface {
+	Begin(ctx context.Context) (string, string, error)
+	Complete(ctx context.Context, state, code string) (*domain.AuthToken, error)
+}
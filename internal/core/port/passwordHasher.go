@@ -0,0 +1,7 @@
+package port
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(password, hash string) error
+	NeedsRehash(hash string) bool
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type BreachedPasswordRepository interface {
+	ContainsHash(ctx context.Context, hash string) (bool, error)
+}
+
+type PasswordPolicyService interface {
+	Validate(ctx context.Context, password string, user *domain.User) error
+}
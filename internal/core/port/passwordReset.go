@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type PasswordResetRepository interface {
+	CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error)
+	GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error)
+	MarkPasswordResetTokenUsed(ctx context.Context, id uint64) error
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID uint64) error
+}
+
+type PasswordResetService interface {
+	RequestReset(ctx context.Context, email string) error
+	CompleteReset(ctx context.Context, token, password string) error
+}
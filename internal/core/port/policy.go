@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+//go:generate mockgen -source=policy.go -destination=mock/policy.go -package=mock
+
+type PolicyRepository interface {
+	GetPolicy(ctx context.Context) (*domain.Policy, error)
+}
+
+type Authorizer interface {
+	Can(ctx context.Context, principal *domain.TokenPayload, action string, resource *domain.PolicyResource) (*domain.PolicyDecision, error)
+	Reload(ctx context.Context) error
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitResult, error)
+}
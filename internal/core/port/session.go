@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	SaveSession(ctx context.Context, session *domain.Session) (*domain.Session, error)
+	GetSession(ctx context.Context, id uuid.UUID) (*domain.Session, error)
+	ListUserSessions(ctx context.Context, userID uint64) ([]domain.Session, error)
+	TouchSession(ctx context.Context, id uuid.UUID, seenAt time.Time) error
+	RevokeSession(ctx context.Context, id uuid.UUID) error
+	RevokeUserSessions(ctx context.Context, userID uint64) error
+}
+
+type SessionService interface {
+	ListSessions(ctx context.Context, userID uint64) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID uint64, id uuid.UUID) error
+}
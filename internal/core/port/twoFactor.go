@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type TwoFactorRepository interface {
+	SaveTOTP(ctx context.Context, totp *domain.TOTP) (*domain.TOTP, error)
+	GetTOTPByUserID(ctx context.Context, userID uint64) (*domain.TOTP, error)
+	ConfirmTOTP(ctx context.Context, userID uint64) error
+	DeleteTOTP(ctx context.Context, userID uint64) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uint64, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error
+}
+
+type TOTPGenerator interface {
+	Generate(accountName string) (*domain.TOTPKey, error)
+	Validate(code, secret string) bool
+}
+
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context, userID uint64) (*domain.TOTPKey, error)
+	Confirm(ctx context.Context, userID uint64, code string) ([]string, error)
+	Disable(ctx context.Context, userID uint64, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error)
+	IsEnabled(ctx context.Context, userID uint64) (bool, error)
+	Verify(ctx context.Context, userID uint64, code string) error
+}
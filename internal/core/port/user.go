@@ -14,6 +14,7 @@ type UserRepository interface {
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	ListUsers(ctx context.Context, skip, limit uint64) ([]domain.User, error)
 	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	VerifyUserEmail(ctx context.Context, id uint64, email string) error
 	DeleteUser(ctx context.Context, id uint64) error
 }
 
@@ -21,6 +22,6 @@ type UserService interface {
 	Register(ctx context.Context, user *domain.User) (*domain.User, error)
 	GetUser(ctx context.Context, id uint64) (*domain.User, error)
 	ListUsers(ctx context.Context, skip, limit uint64) ([]domain.User, error)
-	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
-	DeleteUser(ctx context.Context, id uint64) error
+	UpdateUser(ctx context.Context, actor *domain.TokenPayload, user *domain.User) (*domain.User, error)
+	DeleteUser(ctx context.Context, actor *domain.TokenPayload, id uint64) error
 }
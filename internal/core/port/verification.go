@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+type VerificationService interface {
+	SendVerification(ctx context.Context, user *domain.User) error
+	Verify(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+const (
+	// apiKeyPrefix starts every api key so that leaked keys are easy to recognize
+	apiKeyPrefix = "gows_"
+	// apiKeyLookupSize is the number of random bytes of the lookup prefix of an api key
+	apiKeyLookupSize = 6
+	// apiKeySecretSize is the number of random bytes of the secret part of an api key
+	apiKeySecretSize = 32
+	// apiKeyTouchInterval is how often the last use of an api key is recorded
+	apiKeyTouchInterval = time.Minute
+)
+
+/**
+ * APIKeyService implements port.APIKeyService interface
+ * and provides an access to the api key repository and user repository.
+ * Keys look like gows_<prefix>_<secret>, only the prefix and the hash of the key are stored
+ */
+type APIKeyService struct {
+	repo     port.APIKeyRepository
+	userRepo port.UserRepository
+}
+
+// NewAPIKeyService creates a new api key service instance
+func NewAPIKeyService(repo port.APIKeyRepository, userRepo port.UserRepository) *APIKeyService {
+	return &APIKeyService{
+		repo,
+		userRepo,
+	}
+}
+
+// CreateAPIKey creates an api key for the actor and returns it with the key, which is only shown once.
+// Api keys cannot be used to create other api keys
+func (as *APIKeyService) CreateAPIKey(ctx context.Context, actor *domain.TokenPayload, key *domain.APIKey) (*domain.APIKey, string, error) {
+	if actor.APIKeyID != 0 {
+		return nil, "", domain.ErrForbidden
+	}
+
+	lookup := make([]byte, apiKeyLookupSize)
+	_, err := rand.Read(lookup)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+
+	secret, err := util.GenerateRandomToken(apiKeySecretSize)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+
+	key.UserID = actor.UserID
+	key.Prefix = hex.EncodeToString(lookup)
+	plaintext := apiKeyPrefix + key.Prefix + "_" + secret
+	key.KeyHash = util.HashToken(plaintext)
+
+	key, err = as.repo.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+
+	return key, plaintext, nil
+}
+
+// ListAPIKeys lists the api keys of a user, including expired and revoked ones
+func (as *APIKeyService) ListAPIKeys(ctx context.Context, userID uint64) ([]domain.APIKey, error) {
+	keys, err := as.repo.ListAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey revokes an api key of a user
+func (as *APIKeyService) RevokeAPIKey(ctx context.Context, userID, id uint64) error {
+	err := as.repo.RevokeAPIKey(ctx, userID, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// Authenticate checks an api key and returns the principal it stands for,
+// which has the current role of the owner and is restricted to the scopes of the key
+func (as *APIKeyService) Authenticate(ctx context.Context, key string) (*domain.TokenPayload, error) {
+	lookup, _, ok := strings.Cut(strings.TrimPrefix(key, apiKeyPrefix), "_")
+	if !ok || !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	apiKey, err := as.repo.GetAPIKeyByPrefix(ctx, lookup)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if subtle.ConstantTimeCompare([]byte(util.HashToken(key)), []byte(apiKey.KeyHash)) != 1 {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	user, err := as.userRepo.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		err = as.repo.TouchAPIKey(ctx, apiKey.ID, now)
+		if err != nil {
+			slog.Error("Error recording the api key use", "api_key_id", apiKey.ID, "error", err)
+		}
+	}
+
+	payload := &domain.TokenPayload{
+		UserID:   user.ID,
+		Subject:  strconv.FormatUint(user.ID, 10),
+		Role:     user.Role,
+		Scopes:   apiKey.Scopes,
+		APIKeyID: apiKey.ID,
+		IssuedAt: apiKey.CreatedAt,
+	}
+	if apiKey.ExpiresAt != nil {
+		payload.ExpiredAt = *apiKey.ExpiresAt
+	}
+
+	return payload, nil
+}
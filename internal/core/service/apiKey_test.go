@@ -0,0 +1,237 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	actor := &domain.TokenPayload{
+		UserID: 1,
+		Role:   domain.Cashier,
+	}
+	keyActor := &domain.TokenPayload{
+		UserID:   1,
+		Role:     domain.Cashier,
+		APIKeyID: 7,
+	}
+
+	testCases := []struct {
+		desc     string
+		actor    *domain.TokenPayload
+		mocks    func(repo *mock.MockAPIKeyRepository)
+		expected error
+	}{
+		{
+			desc:  "Success",
+			actor: actor,
+			mocks: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+						assert.Equal(t, actor.UserID, key.UserID, "User mismatch")
+						assert.Len(t, key.Prefix, 12, "Prefix mismatch")
+						return key, nil
+					})
+			},
+			expected: nil,
+		},
+		{
+			desc:     "Fail_CreatedWithAPIKey",
+			actor:    keyActor,
+			mocks:    func(repo *mock.MockAPIKeyRepository) {},
+			expected: domain.ErrForbidden,
+		},
+		{
+			desc:  "Fail_InternalError",
+			actor: actor,
+			mocks: func(repo *mock.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockAPIKeyRepository(ctrl)
+			tc.mocks(repo)
+
+			apiKeyService := service.NewAPIKeyService(repo, mock.NewMockUserRepository(ctrl))
+
+			key, plaintext, err := apiKeyService.CreateAPIKey(ctx, tc.actor, &domain.APIKey{Name: "export"})
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+
+			if tc.expected == nil {
+				assert.True(t, strings.HasPrefix(plaintext, "gows_"+key.Prefix+"_"), "Key mismatch")
+				assert.Equal(t, util.HashToken(plaintext), key.KeyHash, "Hash mismatch")
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	prefix := "3f9a1c2b7d4e"
+	plaintext := "gows_" + prefix + "_secret"
+	recently := time.Now().Add(-time.Second)
+	expiredAt := time.Now().Add(-time.Hour)
+	revokedAt := time.Now().Add(-time.Hour)
+
+	user := &domain.User{
+		ID:   1,
+		Role: domain.Manager,
+	}
+	apiKey := &domain.APIKey{
+		ID:      7,
+		UserID:  user.ID,
+		Prefix:  prefix,
+		KeyHash: util.HashToken(plaintext),
+		Scopes:  []string{"users:read"},
+	}
+	recentKey := &domain.APIKey{
+		ID:         apiKey.ID,
+		UserID:     user.ID,
+		Prefix:     prefix,
+		KeyHash:    apiKey.KeyHash,
+		LastUsedAt: &recently,
+	}
+	expiredKey := &domain.APIKey{
+		ID:        apiKey.ID,
+		UserID:    user.ID,
+		Prefix:    prefix,
+		KeyHash:   apiKey.KeyHash,
+		ExpiresAt: &expiredAt,
+	}
+	revokedKey := &domain.APIKey{
+		ID:        apiKey.ID,
+		UserID:    user.ID,
+		Prefix:    prefix,
+		KeyHash:   apiKey.KeyHash,
+		RevokedAt: &revokedAt,
+	}
+
+	testCases := []struct {
+		desc     string
+		key      string
+		mocks    func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository)
+		expected error
+	}{
+		{
+			desc: "Success",
+			key:  plaintext,
+			mocks: func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository) {
+				repo.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).
+					Return(apiKey, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				repo.EXPECT().
+					TouchAPIKey(gomock.Any(), gomock.Eq(apiKey.ID), gomock.Any()).
+					Return(nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Success_RecentlyUsed",
+			key:  plaintext,
+			mocks: func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository) {
+				repo.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).
+					Return(recentKey, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+			},
+			expected: nil,
+		},
+		{
+			desc:     "Fail_Malformed",
+			key:      "not-an-api-key",
+			mocks:    func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository) {},
+			expected: domain.ErrInvalidAPIKey,
+		},
+		{
+			desc: "Fail_NotFound",
+			key:  plaintext,
+			mocks: func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository) {
+				repo.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: domain.ErrInvalidAPIKey,
+		},
+		{
+			desc: "Fail_WrongSecret",
+			key:  "gows_" + prefix + "_other",
+			mocks: func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository) {
+				repo.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).
+					Return(apiKey, nil)
+			},
+			expected: domain.ErrInvalidAPIKey,
+		},
+		{
+			desc: "Fail_Expired",
+			key:  plaintext,
+			mocks: func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository) {
+				repo.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).
+					Return(expiredKey, nil)
+			},
+			expected: domain.ErrInvalidAPIKey,
+		},
+		{
+			desc: "Fail_Revoked",
+			key:  plaintext,
+			mocks: func(repo *mock.MockAPIKeyRepository, userRepo *mock.MockUserRepository) {
+				repo.EXPECT().
+					GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(prefix)).
+					Return(revokedKey, nil)
+			},
+			expected: domain.ErrInvalidAPIKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockAPIKeyRepository(ctrl)
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tc.mocks(repo, userRepo)
+
+			apiKeyService := service.NewAPIKeyService(repo, userRepo)
+
+			payload, err := apiKeyService.Authenticate(ctx, tc.key)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+
+			if tc.expected == nil {
+				assert.Equal(t, user.ID, payload.UserID, "User mismatch")
+				assert.Equal(t, user.Role, payload.Role, "Role mismatch")
+				assert.Equal(t, apiKey.ID, payload.APIKeyID, "Api key mismatch")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+/**
+ * AuditService implements port.AuditService interface
+ * and provides an access to the audit repository.
+ * Security events are only ever appended to the audit log
+ */
+type AuditService struct {
+	repo port.AuditRepository
+}
+
+// NewAuditService creates a new audit service instance
+func NewAuditService(repo port.AuditRepository) *AuditService {
+	return &AuditService{
+		repo,
+	}
+}
+
+// Record appends a security event to the audit log with the IP address and user agent of the client
+func (as *AuditService) Record(ctx context.Context, event *domain.AuthEvent) error {
+	info := util.ClientInfoFromContext(ctx)
+	event.IP = info.IP
+	event.UserAgent = info.UserAgent
+
+	_, err := as.repo.CreateAuthEvent(ctx, event)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ListEvents lists the security events matching the filter, newest first
+func (as *AuditService) ListEvents(ctx context.Context, filter *domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	events, err := as.repo.ListAuthEvents(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return events, nil
+}
+
+// recordAuthEvent records the outcome of a security event, a failure to record it is only logged
+// so that the audit log never blocks the action it describes
+func recordAuthEvent(ctx context.Context, audit port.AuditService, event *domain.AuthEvent, err error) {
+	event.Outcome = domain.SuccessOutcome
+	if err != nil {
+		event.Outcome = domain.FailureOutcome
+		event.Reason = err.Error()
+	}
+
+	recordErr := audit.Record(ctx, event)
+	if recordErr != nil {
+		slog.Error("Error recording the security event", "type", event.Type, "error", recordErr)
+	}
+}
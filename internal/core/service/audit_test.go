@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// expectAuthEvent expects a security event of the given type to be recorded with the outcome of the given error
+func expectAuthEvent(t *testing.T, audit *mock.MockAuditService, eventType domain.AuthEventType, err error) {
+	outcome := domain.SuccessOutcome
+	reason := ""
+	if err != nil {
+		outcome = domain.FailureOutcome
+		reason = err.Error()
+	}
+
+	audit.EXPECT().
+		Record(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *domain.AuthEvent) error {
+			assert.Equal(t, eventType, event.Type, "Event type mismatch")
+			assert.Equal(t, outcome, event.Outcome, "Event outcome mismatch")
+			if err != nil {
+				assert.Equal(t, reason, event.Reason, "Event reason mismatch")
+			}
+			return nil
+		})
+}
+
+func TestAuditService_Record(t *testing.T) {
+	ctx := util.ContextWithClientInfo(context.Background(), &domain.ClientInfo{
+		IP:        "203.0.113.7",
+		UserAgent: "Mozilla/5.0",
+	})
+	event := &domain.AuthEvent{
+		Type:     domain.LoginEvent,
+		ActorID:  1,
+		TargetID: 1,
+		Email:    "test@example.com",
+		Outcome:  domain.SuccessOutcome,
+	}
+	recordedEvent := &domain.AuthEvent{
+		Type:      domain.LoginEvent,
+		ActorID:   1,
+		TargetID:  1,
+		Email:     "test@example.com",
+		IP:        "203.0.113.7",
+		UserAgent: "Mozilla/5.0",
+		Outcome:   domain.SuccessOutcome,
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(repo *mock.MockAuditRepository)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(repo *mock.MockAuditRepository) {
+				repo.EXPECT().
+					CreateAuthEvent(gomock.Any(), gomock.Eq(recordedEvent)).
+					Return(recordedEvent, nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(repo *mock.MockAuditRepository) {
+				repo.EXPECT().
+					CreateAuthEvent(gomock.Any(), gomock.Eq(recordedEvent)).
+					Return(nil, domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockAuditRepository(ctrl)
+			tc.mocks(repo)
+
+			auditService := service.NewAuditService(repo)
+
+			input := *event
+			err := auditService.Record(ctx, &input)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	ctx := context.Background()
+	filter := &domain.AuthEventFilter{
+		UserID: 1,
+		Type:   domain.LoginEvent,
+		From:   time.Now().Add(-24 * time.Hour),
+		To:     time.Now(),
+		Skip:   1,
+		Limit:  10,
+	}
+	events := []domain.AuthEvent{
+		{
+			ID:       2,
+			Type:     domain.LoginEvent,
+			TargetID: 1,
+			Email:    "test@example.com",
+			Outcome:  domain.FailureOutcome,
+			Reason:   domain.ErrInvalidCredentials.Error(),
+		},
+		{
+			ID:       1,
+			Type:     domain.LoginEvent,
+			ActorID:  1,
+			TargetID: 1,
+			Email:    "test@example.com",
+			Outcome:  domain.SuccessOutcome,
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(repo *mock.MockAuditRepository)
+		output   []domain.AuthEvent
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(repo *mock.MockAuditRepository) {
+				repo.EXPECT().
+					ListAuthEvents(gomock.Any(), gomock.Eq(filter)).
+					Return(events, nil)
+			},
+			output:   events,
+			expected: nil,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(repo *mock.MockAuditRepository) {
+				repo.EXPECT().
+					ListAuthEvents(gomock.Any(), gomock.Eq(filter)).
+					Return(nil, domain.ErrInternal)
+			},
+			output:   nil,
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mock.NewMockAuditRepository(ctrl)
+			tc.mocks(repo)
+
+			auditService := service.NewAuditService(repo)
+
+			output, err := auditService.ListEvents(ctx, filter)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			assert.Equal(t, tc.output, output, "Events mismatch")
+		})
+	}
+}
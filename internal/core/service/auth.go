@@ -2,49 +2,663 @@ package service
 
 import (
 	"context"
+	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/cidmiranda/go-ws/internal/core/port"
 	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// refreshTokenSize is the number of random bytes used to generate a refresh token
+	refreshTokenSize = 32
+	// loginChallengeDuration is how long a login challenge can be exchanged for tokens
+	loginChallengeDuration = 5 * time.Minute
+	// sessionTouchInterval is how often the last time a session was seen is recorded
+	sessionTouchInterval = time.Minute
 )
 
 /**
  * AuthService implements port.AuthService interface
- * and provides an access to the user repository
- * and token service
+ * and provides an access to the user repository,
+ * refresh token repository, session repository, cache repository, token service, login limiter,
+ * two-factor service, password hasher and audit service. Unverified emails cannot log in if requireVerifiedEmail is set
  */
 type AuthService struct {
-	repo port.UserRepository
-	ts   port.TokenService
+	repo                 port.UserRepository
+	ts                   port.TokenService
+	refreshRepo          port.RefreshTokenRepository
+	sessionRepo          port.SessionRepository
+	cache                port.CacheRepository
+	limiter              port.LoginLimiter
+	twoFactor            port.TwoFactorService
+	hasher               port.PasswordHasher
+	audit                port.AuditService
+	refreshDuration      time.Duration
+	requireVerifiedEmail bool
 }
 
 // NewAuthService creates a new auth service instance
-func NewAuthService(repo port.UserRepository, ts port.TokenService) *AuthService {
+func NewAuthService(
+	repo port.UserRepository,
+	ts port.TokenService,
+	refreshRepo port.RefreshTokenRepository,
+	sessionRepo port.SessionRepository,
+	cache port.CacheRepository,
+	limiter port.LoginLimiter,
+	twoFactor port.TwoFactorService,
+	hasher port.PasswordHasher,
+	audit port.AuditService,
+	refreshDuration time.Duration,
+	requireVerifiedEmail bool,
+) *AuthService {
 	return &AuthService{
 		repo,
 		ts,
+		refreshRepo,
+		sessionRepo,
+		cache,
+		limiter,
+		twoFactor,
+		hasher,
+		audit,
+		refreshDuration,
+		requireVerifiedEmail,
+	}
+}
+
+// Login gives a registered user an access token and a refresh token if the credentials are valid.
+// Users with two-factor authentication enabled get a challenge token instead, see LoginTwoFactor.
+// Too many failed attempts lock further logins for the email or the client IP.
+// Passwords hashed with an outdated algorithm or parameters are rehashed.
+// Every attempt is recorded in the audit log, failed attempts have no actor
+func (as *AuthService) Login(ctx context.Context, email, password string) (*domain.AuthToken, error) {
+	user, token, err := as.login(ctx, email, password)
+	as.recordLogin(ctx, email, user, token, err)
+
+	return token, err
+}
+
+// LoginExternal gives a user authenticated by an external identity provider an access token and a refresh token.
+// Users with two-factor authentication enabled get a challenge token instead, as with Login.
+// Every attempt is recorded in the audit log
+func (as *AuthService) LoginExternal(ctx context.Context, user *domain.User) (*domain.AuthToken, error) {
+	token, err := as.completeLogin(ctx, user)
+	as.recordLogin(ctx, user.Email, user, token, err)
+
+	return token, err
+}
+
+// LoginTwoFactor exchanges a login challenge and a valid TOTP or recovery code for an access token and a refresh token.
+// Invalid codes count as failed logins of the user's email.
+// Every attempt is recorded in the audit log, attempts with an unknown challenge have no email
+func (as *AuthService) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*domain.AuthToken, error) {
+	user, token, err := as.loginTwoFactor(ctx, challengeToken, code)
+
+	var email string
+	if user != nil {
+		email = user.Email
+	}
+	as.recordLogin(ctx, email, user, token, err)
+
+	return token, err
+}
+
+// loginTwoFactor checks the challenge and the code of a two-factor login and completes it, the user is returned once it is known
+func (as *AuthService) loginTwoFactor(ctx context.Context, challengeToken, code string) (*domain.User, *domain.AuthToken, error) {
+	cacheKey := util.GenerateCacheKey("login_challenge", util.HashToken(challengeToken))
+
+	value, err := as.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidLoginChallenge
+	}
+
+	userID, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidLoginChallenge
+	}
+
+	user, err := as.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, nil, domain.ErrInvalidLoginChallenge
+		}
+		return nil, nil, domain.ErrInternal
+	}
+
+	err = as.limiter.Check(ctx, user.Email)
+	if err != nil {
+		return user, nil, err
+	}
+
+	err = as.twoFactor.Verify(ctx, user.ID, code)
+	if err != nil {
+		if err == domain.ErrInvalidTwoFactorCode {
+			return user, nil, as.loginFailed(ctx, user.Email, err)
+		}
+		return user, nil, err
+	}
+
+	err = as.cache.Delete(ctx, cacheKey)
+	if err != nil {
+		return user, nil, domain.ErrInternal
+	}
+
+	familyID, err := uuid.NewRandom()
+	if err != nil {
+		return user, nil, domain.ErrInternal
+	}
+
+	token, err := as.issueTokens(ctx, user, familyID, true)
+	return user, token, err
+}
+
+// recordLogin records the outcome of a login attempt in the audit log, failed attempts have no actor
+func (as *AuthService) recordLogin(ctx context.Context, email string, user *domain.User, token *domain.AuthToken, err error) {
+	event := &domain.AuthEvent{
+		Type:  domain.LoginEvent,
+		Email: email,
+	}
+	if user != nil {
+		event.TargetID = user.ID
+	}
+	if err == nil {
+		event.ActorID = user.ID
+		if token.ChallengeToken != "" {
+			event.Reason = "two-factor authentication required"
+		}
+	}
+	recordAuthEvent(ctx, as.audit, event, err)
+}
+
+// Refresh rotates a refresh token, giving the user a new access token and refresh token.
+// Presenting an already used refresh token revokes every token of its family
+func (as *AuthService) Refresh(ctx context.Context, refreshToken string) (*domain.AuthToken, error) {
+	tokenHash := util.HashToken(refreshToken)
+
+	token, err := as.refreshRepo.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if token.RevokedAt != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	if token.UsedAt != nil {
+		return nil, as.revokeFamily(ctx, token.FamilyID)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, domain.ErrExpiredRefreshToken
+	}
+
+	err = as.refreshRepo.MarkRefreshTokenUsed(ctx, token.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, as.revokeFamily(ctx, token.FamilyID)
+		}
+		return nil, domain.ErrInternal
+	}
+
+	user, err := as.repo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return as.issueTokens(ctx, user, token.FamilyID, token.TwoFactor)
+}
+
+// RefreshTokenSession returns the session a refresh token belongs to without using the refresh token
+func (as *AuthService) RefreshTokenSession(ctx context.Context, refreshToken string) (uuid.UUID, error) {
+	token, err := as.refreshRepo.GetRefreshTokenByHash(ctx, util.HashToken(refreshToken))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return uuid.Nil, domain.ErrInvalidRefreshToken
+		}
+		return uuid.Nil, domain.ErrInternal
+	}
+
+	return token.FamilyID, nil
+}
+
+// VerifyToken verifies an access token and checks that it has not been revoked,
+// tokens of oauth clients are revoked as well once their client is deleted, and tokens of a session once the session is revoked.
+// The session of the token is recorded as seen
+func (as *AuthService) VerifyToken(ctx context.Context, token string) (*domain.TokenPayload, error) {
+	payload, err := as.ts.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedKeys := []string{util.GenerateCacheKey("revoked_token", payload.ID)}
+	if payload.ClientID != "" {
+		revokedKeys = append(revokedKeys, util.GenerateCacheKey("oauth_client_deleted", payload.ClientID))
+	}
+	if payload.SessionID != uuid.Nil {
+		revokedKeys = append(revokedKeys, util.GenerateCacheKey("revoked_session", payload.SessionID))
+	}
+
+	for _, cacheKey := range revokedKeys {
+		revoked, err := isRevoked(ctx, as.cache, cacheKey)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, domain.ErrRevokedToken
+		}
+	}
+
+	generation, err := tokenGeneration(ctx, as.cache, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Generation < generation {
+		return nil, domain.ErrRevokedToken
+	}
+
+	if payload.SessionID != uuid.Nil {
+		as.touchSession(ctx, payload.SessionID)
+	}
+
+	return payload, nil
+}
+
+// touchSession records that a session was seen, at most once per sessionTouchInterval.
+// Failures are only logged, they do not reject the request
+func (as *AuthService) touchSession(ctx context.Context, id uuid.UUID) {
+	cacheKey := util.GenerateCacheKey("session_seen", id)
+
+	seen, err := as.cache.Increment(ctx, cacheKey, sessionTouchInterval)
+	if err != nil {
+		slog.Error("Error throttling the session seen update", "session_id", id, "error", err)
+		return
+	}
+	if seen > 1 {
+		return
+	}
+
+	err = as.sessionRepo.TouchSession(ctx, id, time.Now())
+	if err != nil {
+		slog.Error("Error recording the session seen", "session_id", id, "error", err)
+	}
+}
+
+// AuthenticateCertificate returns the principal of the user a verified client certificate was issued to,
+// the certificate is issued to the user's email. The user is looked up on every request so that deleted users are rejected,
+// and certificates issued before the user last logged out everywhere are revoked like the tokens of older generations
+func (as *AuthService) AuthenticateCertificate(ctx context.Context, email string, issuedAt time.Time) (*domain.TokenPayload, error) {
+	user, err := as.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidClientCertificate
+		}
+		return nil, domain.ErrInternal
+	}
+
+	generation, err := tokenGeneration(ctx, as.cache, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedBefore, err := certificatesRevokedBefore(ctx, as.cache, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !issuedAt.After(revokedBefore) {
+		return nil, domain.ErrRevokedToken
+	}
+
+	return &domain.TokenPayload{
+		UserID:     user.ID,
+		Subject:    strconv.FormatUint(user.ID, 10),
+		Role:       user.Role,
+		Generation: generation,
+	}, nil
+}
+
+// Logout revokes the given access token until it expires and, if provided, the refresh token family
+func (as *AuthService) Logout(ctx context.Context, payload *domain.TokenPayload, refreshToken string) error {
+	ttl := time.Until(payload.ExpiredAt)
+	if ttl > 0 {
+		cacheKey := util.GenerateCacheKey("revoked_token", payload.ID)
+
+		err := as.cache.Set(ctx, cacheKey, []byte("1"), ttl)
+		if err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	tokenHash := util.HashToken(refreshToken)
+
+	token, err := as.refreshRepo.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil
+		}
+		return domain.ErrInternal
 	}
+
+	if token.UserID != payload.UserID {
+		return nil
+	}
+
+	err = as.refreshRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = as.sessionRepo.RevokeSession(ctx, token.FamilyID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// LogoutAll revokes every access token and refresh token issued to a user
+// by bumping the user's token generation
+func (as *AuthService) LogoutAll(ctx context.Context, userID uint64) error {
+	generation, err := tokenGeneration(ctx, as.cache, userID)
+	if err != nil {
+		return err
+	}
+
+	cacheKey := util.GenerateCacheKey("token_generation", userID)
+	value := []byte(strconv.FormatUint(generation+1, 10))
+
+	err = as.cache.Set(ctx, cacheKey, value, 0)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	cacheKey = util.GenerateCacheKey("certificates_revoked_before", userID)
+	value = []byte(strconv.FormatInt(time.Now().Unix(), 10))
+
+	err = as.cache.Set(ctx, cacheKey, value, 0)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = as.refreshRepo.RevokeUserRefreshTokens(ctx, userID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = as.sessionRepo.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// Unlock lifts the login lock of an email and forgets its failed attempts
+func (as *AuthService) Unlock(ctx context.Context, email string) error {
+	return as.limiter.Unlock(ctx, email)
 }
 
-// Login gives a registered user an access token if the credentials are valid
-func (as *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+// login checks the credentials of a login and completes it, the user is returned once it is known
+func (as *AuthService) login(ctx context.Context, email, password string) (*domain.User, *domain.AuthToken, error) {
+	err := as.limiter.Check(ctx, email)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	user, err := as.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
-			return "", domain.ErrInvalidCredentials
+			return nil, nil, as.loginFailed(ctx, email, domain.ErrInvalidCredentials)
+		}
+		return nil, nil, domain.ErrInternal
+	}
+
+	err = as.hasher.Compare(password, user.Password)
+	if err != nil {
+		return user, nil, as.loginFailed(ctx, email, domain.ErrInvalidCredentials)
+	}
+
+	if as.hasher.NeedsRehash(user.Password) {
+		as.rehash(ctx, user, password)
+	}
+
+	err = as.limiter.RegisterSuccess(ctx, email)
+	if err != nil {
+		return user, nil, domain.ErrInternal
+	}
+
+	if as.requireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return user, nil, domain.ErrEmailNotVerified
+	}
+
+	token, err := as.completeLogin(ctx, user)
+	if err != nil {
+		return user, nil, err
+	}
+
+	return user, token, nil
+}
+
+// loginFailed registers a failed login, returning the lock error if the attempt locked further logins
+// or the given failure otherwise
+func (as *AuthService) loginFailed(ctx context.Context, email string, failure error) error {
+	err := as.limiter.RegisterFailure(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	return failure
+}
+
+// completeLogin issues the tokens of an authenticated user, or a challenge token if two-factor authentication is enabled
+func (as *AuthService) completeLogin(ctx context.Context, user *domain.User) (*domain.AuthToken, error) {
+	enabled, err := as.twoFactor.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if enabled {
+		return as.createChallenge(ctx, user.ID)
+	}
+
+	familyID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return as.issueTokens(ctx, user, familyID, false)
+}
+
+// createChallenge creates a short-lived challenge token to be exchanged with a two-factor code
+func (as *AuthService) createChallenge(ctx context.Context, userID uint64) (*domain.AuthToken, error) {
+	challengeToken, err := util.GenerateRandomToken(refreshTokenSize)
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	cacheKey := util.GenerateCacheKey("login_challenge", util.HashToken(challengeToken))
+	value := []byte(strconv.FormatUint(userID, 10))
+
+	err = as.cache.Set(ctx, cacheKey, value, loginChallengeDuration)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.AuthToken{
+		ChallengeToken: challengeToken,
+	}, nil
+}
+
+// tokenGeneration returns the current token generation of a user, tokens from older generations are revoked.
+// A failed lookup is an error rather than the first generation so that revoked tokens stay revoked while the cache is unavailable
+func tokenGeneration(ctx context.Context, cache port.CacheRepository, userID uint64) (uint64, error) {
+	cacheKey := util.GenerateCacheKey("token_generation", userID)
+
+	value, err := cache.Get(ctx, cacheKey)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return 0, nil
+		}
+		return 0, domain.ErrInternal
+	}
+
+	generation, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return generation, nil
+}
+
+// certificatesRevokedBefore returns when a user last logged out everywhere, client certificates issued until then are revoked.
+// A failed lookup is an error for the same reason as in tokenGeneration
+func certificatesRevokedBefore(ctx context.Context, cache port.CacheRepository, userID uint64) (time.Time, error) {
+	cacheKey := util.GenerateCacheKey("certificates_revoked_before", userID)
+
+	value, err := cache.Get(ctx, cacheKey)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return time.Time{}, nil
 		}
-		return "", domain.ErrInternal
+		return time.Time{}, domain.ErrInternal
 	}
 
-	err = util.ComparePassword(password, user.Password)
+	seconds, err := strconv.ParseInt(string(value), 10, 64)
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		return time.Time{}, domain.ErrInternal
 	}
 
-	accessToken, err := as.ts.CreateToken(user)
+	return time.Unix(seconds, 0), nil
+}
+
+// isRevoked reports whether the revocation marker stored under the cache key is set,
+// a failed lookup is an error so that revoked tokens are not accepted while the cache is unavailable
+func isRevoked(ctx context.Context, cache port.CacheRepository, cacheKey string) (bool, error) {
+	_, err := cache.Get(ctx, cacheKey)
 	if err != nil {
-		return "", domain.ErrTokenCreation
+		if err == domain.ErrDataNotFound {
+			return false, nil
+		}
+		return false, domain.ErrInternal
 	}
 
-	return accessToken, nil
+	return true, nil
+}
+
+// issueTokens creates an access token and a refresh token belonging to the given family,
+// twoFactor records whether the family was started with a second factor.
+// The family is the user's session, which records the issued access token and the client it was issued to
+func (as *AuthService) issueTokens(ctx context.Context, user *domain.User, familyID uuid.UUID, twoFactor bool) (*domain.AuthToken, error) {
+	generation, err := tokenGeneration(ctx, as.cache, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &domain.TokenPayload{
+		UserID:     user.ID,
+		Role:       user.Role,
+		Generation: generation,
+		TwoFactor:  twoFactor,
+		SessionID:  familyID,
+	}
+
+	accessToken, err := as.ts.CreateToken(payload)
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	refreshToken, err := util.GenerateRandomToken(refreshTokenSize)
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	token := &domain.RefreshToken{
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		TokenHash: util.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(as.refreshDuration),
+		TwoFactor: twoFactor,
+	}
+
+	_, err = as.refreshRepo.CreateRefreshToken(ctx, token)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	info := util.ClientInfoFromContext(ctx)
+	session := &domain.Session{
+		ID:        familyID,
+		UserID:    user.ID,
+		TokenID:   payload.ID,
+		IP:        info.IP,
+		UserAgent: info.UserAgent,
+		ExpiresAt: token.ExpiresAt,
+	}
+
+	_, err = as.sessionRepo.SaveSession(ctx, session)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.AuthToken{
+		User:             user,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		SessionID:        familyID,
+		SessionExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+// revokeFamily revokes every refresh token of a family and its session after a reuse was detected
+func (as *AuthService) revokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	err := as.refreshRepo.RevokeRefreshTokenFamily(ctx, familyID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = as.sessionRepo.RevokeSession(ctx, familyID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return domain.ErrRefreshTokenReused
+}
+
+// rehash hashes the password again with the current algorithm and parameters and saves it,
+// a failure is only logged since the outdated hash still works
+func (as *AuthService) rehash(ctx context.Context, user *domain.User, password string) {
+	hashedPassword, err := as.hasher.Hash(password)
+	if err != nil {
+		slog.Error("Error rehashing the password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	_, err = as.repo.UpdateUser(ctx, &domain.User{
+		ID:       user.ID,
+		Password: hashedPassword,
+	})
+	if err != nil {
+		slog.Error("Error saving the rehashed password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	err = as.cache.Delete(ctx, util.GenerateCacheKey("user", user.ID))
+	if err != nil {
+		slog.Error("Error invalidating the cached user", "user_id", user.ID, "error", err)
+	}
 }
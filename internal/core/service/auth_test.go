@@ -2,13 +2,18 @@ package service_test
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit"
 	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/cidmiranda/go-ws/internal/core/port/mock"
 	"github.com/cidmiranda/go-ws/internal/core/service"
 	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
@@ -18,16 +23,32 @@ type loginTestedInput struct {
 }
 
 type loginExpectedOutput struct {
-	token string
-	err   error
+	token     string
+	challenge bool
+	err       error
 }
 
+const refreshDuration = 24 * time.Hour
+
 func TestAuthService_Login(t *testing.T) {
 	ctx := context.Background()
 	email := gofakeit.Email()
 	password := gofakeit.Password(true, true, true, true, false, 8)
-	hashedPassword, _ := util.HashPassword(password)
+	hashedPassword := "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5"
+	legacyPassword := "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+	newHashedPassword := "$argon2id$v=19$m=65536,t=3,p=2$bmV3c2FsdA$bmV3a2V5"
+	verifiedAt := time.Now()
 	user := &domain.User{
+		Email:           email,
+		Password:        hashedPassword,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	legacyUser := &domain.User{
+		Email:           email,
+		Password:        legacyPassword,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	unverifiedUser := &domain.User{
 		Email:    email,
 		Password: hashedPassword,
 	}
@@ -36,12 +57,22 @@ func TestAuthService_Login(t *testing.T) {
 		Password: "wrong password",
 	}
 	token := gofakeit.UUID()
+	lockedErr := &domain.AccountLockedError{
+		RetryAfter: time.Minute,
+	}
+	generationKey := util.GenerateCacheKey("token_generation", user.ID)
 
 	testCases := []struct {
 		desc  string
 		mocks func(
 			userRepo *mock.MockUserRepository,
 			tokenService *mock.MockTokenService,
+			refreshRepo *mock.MockRefreshTokenRepository,
+			sessionRepo *mock.MockSessionRepository,
+			cache *mock.MockCacheRepository,
+			limiter *mock.MockLoginLimiter,
+			twoFactor *mock.MockTwoFactorService,
+			hasher *mock.MockPasswordHasher,
 		)
 		input    loginTestedInput
 		expected loginExpectedOutput
@@ -51,15 +82,127 @@ func TestAuthService_Login(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
 					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(false)
+				limiter.EXPECT().
+					RegisterSuccess(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				twoFactor.EXPECT().
+					IsEnabled(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(false, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				tokenService.EXPECT().
+					CreateToken(gomock.Any()).
+					Times(1).
+					Return(token, nil)
+				refreshRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.RefreshToken{}, nil)
+				sessionRepo.EXPECT().
+					SaveSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.Session{}, nil)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+			},
+			expected: loginExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Success_Rehash",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
+			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(legacyUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(legacyPassword)).
+					Times(1).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(legacyPassword)).
+					Times(1).
+					Return(true)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Times(1).
+					Return(newHashedPassword, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(&domain.User{ID: legacyUser.ID, Password: newHashedPassword})).
+					Times(1).
+					Return(legacyUser, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(util.GenerateCacheKey("user", legacyUser.ID))).
+					Times(1).
+					Return(nil)
+				limiter.EXPECT().
+					RegisterSuccess(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				twoFactor.EXPECT().
+					IsEnabled(gomock.Any(), gomock.Eq(legacyUser.ID)).
+					Times(1).
+					Return(false, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
 				tokenService.EXPECT().
-					CreateToken(gomock.Eq(user)).
+					CreateToken(gomock.Any()).
 					Times(1).
 					Return(token, nil)
+				refreshRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.RefreshToken{}, nil)
+				sessionRepo.EXPECT().
+					SaveSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.Session{}, nil)
 			},
 			input: loginTestedInput{
 				email:    email,
@@ -70,16 +213,123 @@ func TestAuthService_Login(t *testing.T) {
 				err:   nil,
 			},
 		},
+		{
+			desc: "Success_TwoFactorChallenge",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
+			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(false)
+				limiter.EXPECT().
+					RegisterSuccess(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				twoFactor.EXPECT().
+					IsEnabled(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(true, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Eq([]byte("0")), gomock.Eq(5*time.Minute)).
+					Times(1).
+					Return(nil)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+			},
+			expected: loginExpectedOutput{
+				token:     "",
+				challenge: true,
+				err:       nil,
+			},
+		},
+		{
+			desc: "Fail_EmailNotVerified",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
+			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(unverifiedUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(false)
+				limiter.EXPECT().
+					RegisterSuccess(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+			},
+			expected: loginExpectedOutput{
+				token: "",
+				err:   domain.ErrEmailNotVerified,
+			},
+		},
 		{
 			desc: "Fail_UserNotFound",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
 					Return(nil, domain.ErrDataNotFound)
+				limiter.EXPECT().
+					RegisterFailure(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
 			},
 			input: loginTestedInput{
 				email:    email,
@@ -95,11 +345,29 @@ func TestAuthService_Login(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
 					Return(failUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(failUser.Password)).
+					Times(1).
+					Return(domain.ErrInvalidCredentials)
+				limiter.EXPECT().
+					RegisterFailure(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
 			},
 			input: loginTestedInput{
 				email:    email,
@@ -115,13 +383,43 @@ func TestAuthService_Login(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
 					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Times(1).
+					Return(false)
+				limiter.EXPECT().
+					RegisterSuccess(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				twoFactor.EXPECT().
+					IsEnabled(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(false, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
 				tokenService.EXPECT().
-					CreateToken(gomock.Eq(user)).
+					CreateToken(gomock.Any()).
 					Times(1).
 					Return("", domain.ErrTokenCreation)
 			},
@@ -139,7 +437,17 @@ func TestAuthService_Login(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
@@ -154,6 +462,70 @@ func TestAuthService_Login(t *testing.T) {
 				err:   domain.ErrInternal,
 			},
 		},
+		{
+			desc: "Fail_Locked",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
+			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(lockedErr)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+			},
+			expected: loginExpectedOutput{
+				token: "",
+				err:   lockedErr,
+			},
+		},
+		{
+			desc: "Fail_LockedByAttempt",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+				hasher *mock.MockPasswordHasher,
+			) {
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(failUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(failUser.Password)).
+					Times(1).
+					Return(domain.ErrInvalidCredentials)
+				limiter.EXPECT().
+					RegisterFailure(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(lockedErr)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+			},
+			expected: loginExpectedOutput{
+				token: "",
+				err:   lockedErr,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -165,18 +537,1601 @@ func TestAuthService_Login(t *testing.T) {
 
 			userRepo := mock.NewMockUserRepository(ctrl)
 			tokenService := mock.NewMockTokenService(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			limiter := mock.NewMockLoginLimiter(ctrl)
+			twoFactor := mock.NewMockTwoFactorService(ctrl)
+			hasher := mock.NewMockPasswordHasher(ctrl)
 
-			tc.mocks(userRepo, tokenService)
+			tc.mocks(userRepo, tokenService, refreshRepo, sessionRepo, cache, limiter, twoFactor, hasher)
 
-			authService := service.NewAuthService(userRepo, tokenService)
+			audit := mock.NewMockAuditService(ctrl)
+			expectAuthEvent(t, audit, domain.LoginEvent, tc.expected.err)
 
-			token, err := authService.Login(ctx, tc.input.email, tc.input.password)
+			authService := service.NewAuthService(userRepo, tokenService, refreshRepo, sessionRepo, cache, limiter, twoFactor, hasher, audit, refreshDuration, true)
+
+			authToken, err := authService.Login(ctx, tc.input.email, tc.input.password)
 			if err != tc.expected.err {
 				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
 			}
+
+			var token string
+			if authToken != nil {
+				token = authToken.AccessToken
+				if (authToken.ChallengeToken != "") != tc.expected.challenge {
+					t.Errorf("[case: %s] expected to get a challenge token: %t", tc.desc, tc.expected.challenge)
+				}
+				if !tc.expected.challenge && authToken.RefreshToken == "" {
+					t.Errorf("[case: %s] expected to get a refresh token", tc.desc)
+				}
+			}
 			if token != tc.expected.token {
 				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.token, token)
 			}
 		})
 	}
 }
+
+type loginTwoFactorTestedInput struct {
+	challengeToken string
+	code           string
+}
+
+func TestAuthService_LoginTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	challengeToken, _ := util.GenerateRandomToken(32)
+	challengeKey := util.GenerateCacheKey("login_challenge", util.HashToken(challengeToken))
+	code := "123456"
+	user := &domain.User{
+		ID:    1,
+		Email: gofakeit.Email(),
+	}
+	token := gofakeit.UUID()
+	lockedErr := &domain.AccountLockedError{
+		RetryAfter: time.Minute,
+	}
+	generationKey := util.GenerateCacheKey("token_generation", user.ID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			tokenService *mock.MockTokenService,
+			refreshRepo *mock.MockRefreshTokenRepository,
+			sessionRepo *mock.MockSessionRepository,
+			cache *mock.MockCacheRepository,
+			limiter *mock.MockLoginLimiter,
+			twoFactor *mock.MockTwoFactorService,
+		)
+		input    loginTwoFactorTestedInput
+		expected loginExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(nil)
+				twoFactor.EXPECT().
+					Verify(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(code)).
+					Times(1).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(challengeKey)).
+					Times(1).
+					Return(nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				tokenService.EXPECT().
+					CreateToken(gomock.Any()).
+					Times(1).
+					DoAndReturn(func(payload *domain.TokenPayload) (string, error) {
+						if !payload.TwoFactor {
+							t.Errorf("expected the access token to record the second factor")
+						}
+						return token, nil
+					})
+				refreshRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, refreshToken *domain.RefreshToken) (*domain.RefreshToken, error) {
+						if !refreshToken.TwoFactor {
+							t.Errorf("expected the refresh token to record the second factor")
+						}
+						return refreshToken, nil
+					})
+				sessionRepo.EXPECT().
+					SaveSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.Session{}, nil)
+			},
+			input: loginTwoFactorTestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+			},
+			expected: loginExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Fail_InvalidChallenge",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: loginTwoFactorTestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+			},
+			expected: loginExpectedOutput{
+				token: "",
+				err:   domain.ErrInvalidLoginChallenge,
+			},
+		},
+		{
+			desc: "Fail_InvalidCode",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(nil)
+				twoFactor.EXPECT().
+					Verify(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(code)).
+					Times(1).
+					Return(domain.ErrInvalidTwoFactorCode)
+				limiter.EXPECT().
+					RegisterFailure(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(nil)
+			},
+			input: loginTwoFactorTestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+			},
+			expected: loginExpectedOutput{
+				token: "",
+				err:   domain.ErrInvalidTwoFactorCode,
+			},
+		},
+		{
+			desc: "Fail_LockedByAttempt",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				limiter *mock.MockLoginLimiter,
+				twoFactor *mock.MockTwoFactorService,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				limiter.EXPECT().
+					Check(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(nil)
+				twoFactor.EXPECT().
+					Verify(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(code)).
+					Times(1).
+					Return(domain.ErrInvalidTwoFactorCode)
+				limiter.EXPECT().
+					RegisterFailure(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(lockedErr)
+			},
+			input: loginTwoFactorTestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+			},
+			expected: loginExpectedOutput{
+				token: "",
+				err:   lockedErr,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			limiter := mock.NewMockLoginLimiter(ctrl)
+			twoFactor := mock.NewMockTwoFactorService(ctrl)
+
+			tc.mocks(userRepo, tokenService, refreshRepo, sessionRepo, cache, limiter, twoFactor)
+
+			audit := mock.NewMockAuditService(ctrl)
+			expectAuthEvent(t, audit, domain.LoginEvent, tc.expected.err)
+
+			authService := service.NewAuthService(userRepo, tokenService, refreshRepo, sessionRepo, cache, limiter, twoFactor, mock.NewMockPasswordHasher(ctrl), audit, refreshDuration, true)
+
+			authToken, err := authService.LoginTwoFactor(ctx, tc.input.challengeToken, tc.input.code)
+			if err != tc.expected.err {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			var token string
+			if authToken != nil {
+				token = authToken.AccessToken
+			}
+			if token != tc.expected.token {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.token, token)
+			}
+		})
+	}
+}
+
+func TestAuthService_LoginExternal(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{
+		ID:    1,
+		Email: gofakeit.Email(),
+		Role:  domain.Cashier,
+	}
+	token := gofakeit.UUID()
+	generationKey := util.GenerateCacheKey("token_generation", user.ID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			tokenService *mock.MockTokenService,
+			refreshRepo *mock.MockRefreshTokenRepository,
+			sessionRepo *mock.MockSessionRepository,
+			cache *mock.MockCacheRepository,
+			twoFactor *mock.MockTwoFactorService,
+		)
+		expected loginExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				twoFactor *mock.MockTwoFactorService,
+			) {
+				twoFactor.EXPECT().
+					IsEnabled(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(false, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				tokenService.EXPECT().
+					CreateToken(gomock.Any()).
+					Times(1).
+					Return(token, nil)
+				refreshRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.RefreshToken{}, nil)
+				sessionRepo.EXPECT().
+					SaveSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&domain.Session{}, nil)
+			},
+			expected: loginExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Success_TwoFactorRequired",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				twoFactor *mock.MockTwoFactorService,
+			) {
+				twoFactor.EXPECT().
+					IsEnabled(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(true, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Eq([]byte("1")), gomock.Eq(5*time.Minute)).
+					Times(1).
+					Return(nil)
+			},
+			expected: loginExpectedOutput{
+				challenge: true,
+				err:       nil,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+				twoFactor *mock.MockTwoFactorService,
+			) {
+				twoFactor.EXPECT().
+					IsEnabled(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(false, domain.ErrInternal)
+			},
+			expected: loginExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			twoFactor := mock.NewMockTwoFactorService(ctrl)
+
+			tc.mocks(tokenService, refreshRepo, sessionRepo, cache, twoFactor)
+
+			audit := mock.NewMockAuditService(ctrl)
+			expectAuthEvent(t, audit, domain.LoginEvent, tc.expected.err)
+
+			authService := service.NewAuthService(mock.NewMockUserRepository(ctrl), tokenService, refreshRepo, sessionRepo, cache, mock.NewMockLoginLimiter(ctrl), twoFactor, mock.NewMockPasswordHasher(ctrl), audit, refreshDuration, true)
+
+			authToken, err := authService.LoginExternal(ctx, user)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+
+			var accessToken string
+			var challenge bool
+			if authToken != nil {
+				accessToken = authToken.AccessToken
+				challenge = authToken.ChallengeToken != ""
+			}
+			assert.Equal(t, tc.expected.token, accessToken, "Token mismatch")
+			assert.Equal(t, tc.expected.challenge, challenge, "Challenge mismatch")
+		})
+	}
+}
+
+type refreshTestedInput struct {
+	refreshToken string
+}
+
+type refreshExpectedOutput struct {
+	token string
+	err   error
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	refreshToken, _ := util.GenerateRandomToken(32)
+	tokenHash := util.HashToken(refreshToken)
+	token := gofakeit.UUID()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: gofakeit.Email(),
+	}
+	usedAt := time.Now().Add(-time.Minute)
+	storedToken := &domain.RefreshToken{
+		ID:        gofakeit.Uint64(),
+		FamilyID:  uuid.New(),
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(refreshDuration),
+	}
+	usedToken := &domain.RefreshToken{
+		ID:        storedToken.ID,
+		FamilyID:  storedToken.FamilyID,
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: storedToken.ExpiresAt,
+		UsedAt:    &usedAt,
+	}
+	expiredToken := &domain.RefreshToken{
+		ID:        storedToken.ID,
+		FamilyID:  storedToken.FamilyID,
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	generationKey := util.GenerateCacheKey("token_generation", user.ID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			tokenService *mock.MockTokenService,
+			refreshRepo *mock.MockRefreshTokenRepository,
+			sessionRepo *mock.MockSessionRepository,
+			cache *mock.MockCacheRepository,
+		)
+		input    refreshTestedInput
+		expected refreshExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(storedToken, nil)
+				refreshRepo.EXPECT().
+					MarkRefreshTokenUsed(gomock.Any(), gomock.Eq(storedToken.ID)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				tokenService.EXPECT().
+					CreateToken(gomock.Any()).
+					Times(1).
+					Return(token, nil)
+				refreshRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						if rt.FamilyID != storedToken.FamilyID {
+							t.Errorf("expected rotated token to keep family %s; got %s", storedToken.FamilyID, rt.FamilyID)
+						}
+						return rt, nil
+					})
+				sessionRepo.EXPECT().
+					SaveSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, session *domain.Session) (*domain.Session, error) {
+						if session.ID != storedToken.FamilyID {
+							t.Errorf("expected rotated token to keep session %s; got %s", storedToken.FamilyID, session.ID)
+						}
+						return session, nil
+					})
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: "",
+				err:   domain.ErrInvalidRefreshToken,
+			},
+		},
+		{
+			desc: "Fail_Expired",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(expiredToken, nil)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: "",
+				err:   domain.ErrExpiredRefreshToken,
+			},
+		},
+		{
+			desc: "Fail_ReuseRevokesFamily",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(usedToken, nil)
+				refreshRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(usedToken.FamilyID)).
+					Times(1).
+					Return(nil)
+				sessionRepo.EXPECT().
+					RevokeSession(gomock.Any(), gomock.Eq(usedToken.FamilyID)).
+					Times(1).
+					Return(nil)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: "",
+				err:   domain.ErrRefreshTokenReused,
+			},
+		},
+		{
+			desc: "Fail_ConcurrentReuseRevokesFamily",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(storedToken, nil)
+				refreshRepo.EXPECT().
+					MarkRefreshTokenUsed(gomock.Any(), gomock.Eq(storedToken.ID)).
+					Times(1).
+					Return(domain.ErrDataNotFound)
+				refreshRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(storedToken.FamilyID)).
+					Times(1).
+					Return(nil)
+				sessionRepo.EXPECT().
+					RevokeSession(gomock.Any(), gomock.Eq(storedToken.FamilyID)).
+					Times(1).
+					Return(nil)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: "",
+				err:   domain.ErrRefreshTokenReused,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(nil, domain.ErrInternal)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: "",
+				err:   domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, tokenService, refreshRepo, sessionRepo, cache)
+
+			authService := service.NewAuthService(userRepo, tokenService, refreshRepo, sessionRepo, cache, mock.NewMockLoginLimiter(ctrl), mock.NewMockTwoFactorService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl), refreshDuration, true)
+
+			authToken, err := authService.Refresh(ctx, tc.input.refreshToken)
+			if err != tc.expected.err {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			var token string
+			if authToken != nil {
+				token = authToken.AccessToken
+			}
+			if token != tc.expected.token {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.token, token)
+			}
+		})
+	}
+}
+
+func TestAuthService_RefreshTokenSession(t *testing.T) {
+	ctx := context.Background()
+	refreshToken := gofakeit.UUID()
+	tokenHash := util.HashToken(refreshToken)
+	familyID := uuid.New()
+
+	testCases := []struct {
+		desc     string
+		mocks    func(refreshRepo *mock.MockRefreshTokenRepository)
+		output   uuid.UUID
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(refreshRepo *mock.MockRefreshTokenRepository) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(&domain.RefreshToken{FamilyID: familyID}, nil)
+			},
+			output:   familyID,
+			expected: nil,
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(refreshRepo *mock.MockRefreshTokenRepository) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			output:   uuid.Nil,
+			expected: domain.ErrInvalidRefreshToken,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(refreshRepo *mock.MockRefreshTokenRepository) {
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(nil, domain.ErrInternal)
+			},
+			output:   uuid.Nil,
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			tc.mocks(refreshRepo)
+
+			authService := service.NewAuthService(mock.NewMockUserRepository(ctrl), mock.NewMockTokenService(ctrl), refreshRepo, mock.NewMockSessionRepository(ctrl), mock.NewMockCacheRepository(ctrl), mock.NewMockLoginLimiter(ctrl), mock.NewMockTwoFactorService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl), refreshDuration, true)
+
+			sessionID, err := authService.RefreshTokenSession(ctx, refreshToken)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			assert.Equal(t, tc.output, sessionID, "Session mismatch")
+		})
+	}
+}
+
+type verifyTokenTestedInput struct {
+	token string
+}
+
+type verifyTokenExpectedOutput struct {
+	payload *domain.TokenPayload
+	err     error
+}
+
+func TestAuthService_VerifyToken(t *testing.T) {
+	ctx := context.Background()
+	token := gofakeit.UUID()
+	payload := &domain.TokenPayload{
+		ID:         uuid.New(),
+		UserID:     gofakeit.Uint64(),
+		Generation: 1,
+		ExpiredAt:  time.Now().Add(time.Minute),
+	}
+	sessionPayload := &domain.TokenPayload{
+		ID:         payload.ID,
+		UserID:     payload.UserID,
+		Generation: 1,
+		SessionID:  uuid.New(),
+		ExpiredAt:  payload.ExpiredAt,
+	}
+	revokedKey := util.GenerateCacheKey("revoked_token", payload.ID)
+	revokedSessionKey := util.GenerateCacheKey("revoked_session", sessionPayload.SessionID)
+	generationKey := util.GenerateCacheKey("token_generation", payload.UserID)
+	sessionSeenKey := util.GenerateCacheKey("session_seen", sessionPayload.SessionID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			tokenService *mock.MockTokenService,
+			cache *mock.MockCacheRepository,
+			sessionRepo *mock.MockSessionRepository,
+		)
+		input    verifyTokenTestedInput
+		expected verifyTokenExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: payload,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Fail_InvalidToken",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(nil, domain.ErrInvalidToken)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrInvalidToken,
+			},
+		},
+		{
+			desc: "Fail_RevokedToken",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrRevokedToken,
+			},
+		},
+		{
+			desc: "Success_Session",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(sessionPayload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedSessionKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sessionSeenKey), gomock.Eq(time.Minute)).
+					Times(1).
+					Return(int64(1), nil)
+				sessionRepo.EXPECT().
+					TouchSession(gomock.Any(), gomock.Eq(sessionPayload.SessionID), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: sessionPayload,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_SessionSeenRecently",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(sessionPayload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedSessionKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sessionSeenKey), gomock.Eq(time.Minute)).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: sessionPayload,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_SessionTouchFailure",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(sessionPayload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedSessionKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sessionSeenKey), gomock.Eq(time.Minute)).
+					Times(1).
+					Return(int64(1), nil)
+				sessionRepo.EXPECT().
+					TouchSession(gomock.Any(), gomock.Eq(sessionPayload.SessionID), gomock.Any()).
+					Times(1).
+					Return(errors.New("connection refused"))
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: sessionPayload,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Fail_RevokedSession",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(sessionPayload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedSessionKey)).
+					Times(1).
+					Return([]byte("1"), nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrRevokedToken,
+			},
+		},
+		{
+			desc: "Fail_OutdatedGeneration",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("2"), nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrRevokedToken,
+			},
+		},
+		{
+			desc: "Fail_RevocationLookup",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_GenerationLookup",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+				sessionRepo *mock.MockSessionRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Times(1).
+					Return(sessionPayload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(revokedSessionKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(tokenService, cache, sessionRepo)
+
+			authService := service.NewAuthService(userRepo, tokenService, refreshRepo, sessionRepo, cache, mock.NewMockLoginLimiter(ctrl), mock.NewMockTwoFactorService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl), refreshDuration, true)
+
+			payload, err := authService.VerifyToken(ctx, tc.input.token)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+			assert.Equal(t, tc.expected.payload, payload, "Payload mismatch")
+		})
+	}
+}
+
+type logoutTestedInput struct {
+	payload      *domain.TokenPayload
+	refreshToken string
+}
+
+type logoutExpectedOutput struct {
+	err error
+}
+
+func TestAuthService_AuthenticateCertificate(t *testing.T) {
+	ctx := context.Background()
+	email := gofakeit.Email()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: email,
+		Role:  domain.Manager,
+	}
+	issuedAt := time.Now().Add(-time.Hour)
+	generationKey := util.GenerateCacheKey("token_generation", user.ID)
+	certificatesKey := util.GenerateCacheKey("certificates_revoked_before", user.ID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			cache *mock.MockCacheRepository,
+		)
+		output   *domain.TokenPayload
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("2"), nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(certificatesKey)).
+					Times(1).
+					Return([]byte(strconv.FormatInt(issuedAt.Add(-time.Minute).Unix(), 10)), nil)
+			},
+			output: &domain.TokenPayload{
+				UserID:     user.ID,
+				Subject:    strconv.FormatUint(user.ID, 10),
+				Role:       user.Role,
+				Generation: 2,
+			},
+			expected: nil,
+		},
+		{
+			desc: "Success_NeverLoggedOutEverywhere",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(certificatesKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			output: &domain.TokenPayload{
+				UserID:  user.ID,
+				Subject: strconv.FormatUint(user.ID, 10),
+				Role:    user.Role,
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_IssuedBeforeLogoutAll",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("3"), nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(certificatesKey)).
+					Times(1).
+					Return([]byte(strconv.FormatInt(issuedAt.Add(time.Minute).Unix(), 10)), nil)
+			},
+			output:   nil,
+			expected: domain.ErrRevokedToken,
+		},
+		{
+			desc: "Fail_UnknownUser",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			output:   nil,
+			expected: domain.ErrInvalidClientCertificate,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil, domain.ErrInternal)
+			},
+			output:   nil,
+			expected: domain.ErrInternal,
+		},
+		{
+			desc: "Fail_RevocationLookup",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("2"), nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(certificatesKey)).
+					Times(1).
+					Return(nil, errors.New("connection refused"))
+			},
+			output:   nil,
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			tc.mocks(userRepo, cache)
+
+			authService := service.NewAuthService(userRepo, mock.NewMockTokenService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), mock.NewMockSessionRepository(ctrl), cache, mock.NewMockLoginLimiter(ctrl), mock.NewMockTwoFactorService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl), refreshDuration, true)
+
+			payload, err := authService.AuthenticateCertificate(ctx, email, issuedAt)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			assert.Equal(t, tc.output, payload, "Payload mismatch")
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+	payload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		UserID:    gofakeit.Uint64(),
+		ExpiredAt: time.Now().Add(time.Minute),
+	}
+	expiredPayload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		UserID:    payload.UserID,
+		ExpiredAt: time.Now().Add(-time.Minute),
+	}
+	refreshToken, _ := util.GenerateRandomToken(32)
+	tokenHash := util.HashToken(refreshToken)
+	storedToken := &domain.RefreshToken{
+		ID:       gofakeit.Uint64(),
+		FamilyID: uuid.New(),
+		UserID:   payload.UserID,
+	}
+	foreignToken := &domain.RefreshToken{
+		ID:       gofakeit.Uint64(),
+		FamilyID: uuid.New(),
+		UserID:   payload.UserID + 1,
+	}
+	revokedKey := util.GenerateCacheKey("revoked_token", payload.ID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			refreshRepo *mock.MockRefreshTokenRepository,
+			sessionRepo *mock.MockSessionRepository,
+			cache *mock.MockCacheRepository,
+		)
+		input    logoutTestedInput
+		expected logoutExpectedOutput
+	}{
+		{
+			desc: "Success_AccessTokenOnly",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedKey), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			input: logoutTestedInput{
+				payload: payload,
+			},
+			expected: logoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_WithRefreshToken",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedKey), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(storedToken, nil)
+				refreshRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(storedToken.FamilyID)).
+					Times(1).
+					Return(nil)
+				sessionRepo.EXPECT().
+					RevokeSession(gomock.Any(), gomock.Eq(storedToken.FamilyID)).
+					Times(1).
+					Return(nil)
+			},
+			input: logoutTestedInput{
+				payload:      payload,
+				refreshToken: refreshToken,
+			},
+			expected: logoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_IgnoresForeignRefreshToken",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedKey), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				refreshRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+					Times(1).
+					Return(foreignToken, nil)
+			},
+			input: logoutTestedInput{
+				payload:      payload,
+				refreshToken: refreshToken,
+			},
+			expected: logoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_ExpiredAccessToken",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+			},
+			input: logoutTestedInput{
+				payload: expiredPayload,
+			},
+			expected: logoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_SetCache",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedKey), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(domain.ErrInternal)
+			},
+			input: logoutTestedInput{
+				payload: payload,
+			},
+			expected: logoutExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(refreshRepo, sessionRepo, cache)
+
+			authService := service.NewAuthService(userRepo, tokenService, refreshRepo, sessionRepo, cache, mock.NewMockLoginLimiter(ctrl), mock.NewMockTwoFactorService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl), refreshDuration, true)
+
+			err := authService.Logout(ctx, tc.input.payload, tc.input.refreshToken)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+		})
+	}
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	generationKey := util.GenerateCacheKey("token_generation", userID)
+	certificatesKey := util.GenerateCacheKey("certificates_revoked_before", userID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			refreshRepo *mock.MockRefreshTokenRepository,
+			sessionRepo *mock.MockSessionRepository,
+			cache *mock.MockCacheRepository,
+		)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return([]byte("4"), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(generationKey), gomock.Eq([]byte("5")), gomock.Eq(time.Duration(0))).
+					Times(1).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(certificatesKey), gomock.Any(), gomock.Eq(time.Duration(0))).
+					Times(1).
+					Return(nil)
+				refreshRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(nil)
+				sessionRepo.EXPECT().
+					RevokeUserSessions(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_RevokeRefreshTokens",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(generationKey), gomock.Eq([]byte("1")), gomock.Eq(time.Duration(0))).
+					Times(1).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(certificatesKey), gomock.Any(), gomock.Eq(time.Duration(0))).
+					Times(1).
+					Return(nil)
+				refreshRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+		{
+			desc: "Fail_RevokeSessions",
+			mocks: func(
+				refreshRepo *mock.MockRefreshTokenRepository,
+				sessionRepo *mock.MockSessionRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(generationKey), gomock.Eq([]byte("1")), gomock.Eq(time.Duration(0))).
+					Times(1).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(certificatesKey), gomock.Any(), gomock.Eq(time.Duration(0))).
+					Times(1).
+					Return(nil)
+				refreshRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(nil)
+				sessionRepo.EXPECT().
+					RevokeUserSessions(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(refreshRepo, sessionRepo, cache)
+
+			authService := service.NewAuthService(userRepo, tokenService, refreshRepo, sessionRepo, cache, mock.NewMockLoginLimiter(ctrl), mock.NewMockTwoFactorService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl), refreshDuration, true)
+
+			err := authService.LogoutAll(ctx, userID)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+const (
+	// wildcard matches any subject, action or resource
+	wildcard = "*"
+	// principalPrefix is the prefix of condition attributes taken from the principal
+	principalPrefix = "principal."
+	// resourcePrefix is the prefix of condition attributes taken from the resource
+	resourcePrefix = "resource."
+)
+
+/**
+ * AuthorizerService implements port.Authorizer interface
+ * and evaluates the rules of the policy loaded from the policy repository.
+ * Deny rules take precedence over allow rules and anything that is not
+ * explicitly allowed is denied
+ */
+type AuthorizerService struct {
+	repo   port.PolicyRepository
+	mu     sync.RWMutex
+	policy *domain.Policy
+}
+
+// NewAuthorizerService creates a new authorizer service instance, the policy must be loaded with Reload
+func NewAuthorizerService(repo port.PolicyRepository) *AuthorizerService {
+	return &AuthorizerService{
+		repo: repo,
+	}
+}
+
+// Reload loads the policy from the policy repository and replaces the current one if it is valid
+func (as *AuthorizerService) Reload(ctx context.Context) error {
+	policy, err := as.repo.GetPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = validatePolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	as.mu.Lock()
+	as.policy = policy
+	as.mu.Unlock()
+
+	return nil
+}
+
+// Can decides whether the principal may perform the action on the resource,
+// explaining the outcome of every rule of the policy.
+// Principals with scopes may only perform the actions their scopes cover
+func (as *AuthorizerService) Can(
+	ctx context.Context,
+	principal *domain.TokenPayload,
+	action string,
+	resource *domain.PolicyResource,
+) (*domain.PolicyDecision, error) {
+	as.mu.RLock()
+	policy := as.policy
+	as.mu.RUnlock()
+
+	if policy == nil {
+		return nil, domain.ErrPolicyNotLoaded
+	}
+
+	decision := &domain.PolicyDecision{
+		Reason: "no rule allows the action",
+	}
+
+	var allowRule, denyRule string
+
+	for _, rule := range policy.Rules {
+		matched, reason := matchRule(&rule, principal, action, resource)
+
+		decision.Evaluations = append(decision.Evaluations, domain.PolicyEvaluation{
+			RuleID:  rule.ID,
+			Effect:  rule.Effect,
+			Matched: matched,
+			Reason:  reason,
+		})
+
+		if !matched {
+			continue
+		}
+
+		if rule.Effect == domain.Deny && denyRule == "" {
+			denyRule = rule.ID
+		}
+		if rule.Effect == domain.Allow && allowRule == "" {
+			allowRule = rule.ID
+		}
+	}
+
+	switch {
+	case denyRule != "":
+		decision.RuleID = denyRule
+		decision.Reason = fmt.Sprintf("denied by rule %q", denyRule)
+	case !util.ScopesAllow(principal.Scopes, action):
+		decision.Reason = "action is outside the scopes of the principal"
+	case allowRule != "":
+		decision.Allowed = true
+		decision.RuleID = allowRule
+		decision.Reason = fmt.Sprintf("allowed by rule %q", allowRule)
+	}
+
+	return decision, nil
+}
+
+// matchRule checks whether a rule applies to the request and explains why it does or does not
+func matchRule(
+	rule *domain.PolicyRule,
+	principal *domain.TokenPayload,
+	action string,
+	resource *domain.PolicyResource,
+) (bool, string) {
+	subjects := principalSubjects(principal)
+	if !slices.ContainsFunc(rule.Subjects, func(subject string) bool {
+		return subject == wildcard || slices.Contains(subjects, subject)
+	}) {
+		return false, "subject does not match"
+	}
+
+	if !matchPattern(rule.Actions, action) {
+		return false, "action does not match"
+	}
+
+	if !matchPattern(rule.Resources, resourceName(resource)) {
+		return false, "resource does not match"
+	}
+
+	for _, condition := range rule.Conditions {
+		if !matchCondition(&condition, principal, resource) {
+			return false, fmt.Sprintf(
+				"condition %s %s %s is not met",
+				condition.Attribute,
+				condition.Operator,
+				strings.Join(condition.Values, ","),
+			)
+		}
+	}
+
+	return true, "matched"
+}
+
+// matchPattern checks whether the value matches any of the glob patterns
+func matchPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern == wildcard {
+			return true
+		}
+
+		matched, err := path.Match(pattern, value)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchCondition checks whether a condition holds, conditions on missing attributes never hold
+func matchCondition(condition *domain.PolicyCondition, principal *domain.TokenPayload, resource *domain.PolicyResource) bool {
+	value, ok := attributeValue(condition.Attribute, principal, resource)
+	if !ok {
+		return false
+	}
+
+	var values []string
+	for _, v := range condition.Values {
+		if strings.HasPrefix(v, principalPrefix) || strings.HasPrefix(v, resourcePrefix) {
+			resolved, ok := attributeValue(v, principal, resource)
+			if !ok {
+				return false
+			}
+			v = resolved
+		}
+		values = append(values, v)
+	}
+
+	switch condition.Operator {
+	case domain.OperatorEqual:
+		return value == values[0]
+	case domain.OperatorNotEqual:
+		return value != values[0]
+	case domain.OperatorIn:
+		return slices.Contains(values, value)
+	default:
+		return false
+	}
+}
+
+// attributeValue resolves a principal or resource attribute referenced by a condition
+func attributeValue(name string, principal *domain.TokenPayload, resource *domain.PolicyResource) (string, bool) {
+	switch {
+	case strings.HasPrefix(name, principalPrefix):
+		value, ok := principalAttributes(principal)[strings.TrimPrefix(name, principalPrefix)]
+		return value, ok
+	case strings.HasPrefix(name, resourcePrefix):
+		key := strings.TrimPrefix(name, resourcePrefix)
+		switch key {
+		case "type":
+			return resource.Type, true
+		case "id":
+			return resource.ID, resource.ID != ""
+		}
+		value, ok := resource.Attributes[key]
+		return value, ok
+	default:
+		return "", false
+	}
+}
+
+// principalAttributes returns the attributes of the principal that conditions may refer to
+func principalAttributes(principal *domain.TokenPayload) map[string]string {
+	return map[string]string{
+		"id":              strconv.FormatUint(principal.UserID, 10),
+		"role":            string(principal.Role),
+		"subject":         principal.Subject,
+		"issuer":          principal.Issuer,
+		"audience":        principal.Audience,
+		"two_factor":      strconv.FormatBool(principal.TwoFactor),
+		"auth_method":     authMethod(principal),
+		"client_id":       principal.ClientID,
+		"impersonator_id": strconv.FormatUint(principal.ImpersonatorID, 10),
+	}
+}
+
+// authMethod returns how the principal authenticated
+func authMethod(principal *domain.TokenPayload) string {
+	if principal.ImpersonatorID != 0 {
+		return "impersonation"
+	}
+
+	if principal.APIKeyID != 0 {
+		return "api_key"
+	}
+
+	if principal.ClientID != "" {
+		return "oauth"
+	}
+
+	return "token"
+}
+
+// principalSubjects returns the policy subjects the principal is known as
+func principalSubjects(principal *domain.TokenPayload) []string {
+	subjects := []string{
+		"user:" + strconv.FormatUint(principal.UserID, 10),
+	}
+
+	if principal.Role != "" {
+		subjects = append(subjects, "role:"+string(principal.Role))
+	}
+
+	if principal.ClientID != "" {
+		subjects = append(subjects, "client:"+principal.ClientID)
+	}
+
+	for _, scope := range principal.Scopes {
+		subjects = append(subjects, "scope:"+scope)
+	}
+
+	return subjects
+}
+
+// resourceName returns the name matched against resource patterns, such as users/42
+func resourceName(resource *domain.PolicyResource) string {
+	if resource.ID == "" {
+		return resource.Type
+	}
+
+	return resource.Type + "/" + resource.ID
+}
+
+// validatePolicy checks that every rule of the policy can be evaluated
+func validatePolicy(policy *domain.Policy) error {
+	ids := make(map[string]bool)
+
+	for i, rule := range policy.Rules {
+		if rule.ID == "" {
+			return fmt.Errorf("%w: rule %d has no id", domain.ErrInvalidPolicy, i)
+		}
+
+		if ids[rule.ID] {
+			return fmt.Errorf("%w: duplicate rule %q", domain.ErrInvalidPolicy, rule.ID)
+		}
+		ids[rule.ID] = true
+
+		if rule.Effect != domain.Allow && rule.Effect != domain.Deny {
+			return fmt.Errorf("%w: rule %q has an unknown effect %q", domain.ErrInvalidPolicy, rule.ID, rule.Effect)
+		}
+
+		if len(rule.Subjects) == 0 || len(rule.Actions) == 0 || len(rule.Resources) == 0 {
+			return fmt.Errorf("%w: rule %q needs subjects, actions and resources", domain.ErrInvalidPolicy, rule.ID)
+		}
+
+		for _, pattern := range slices.Concat(rule.Actions, rule.Resources) {
+			_, err := path.Match(pattern, "")
+			if err != nil {
+				return fmt.Errorf("%w: rule %q has an invalid pattern %q", domain.ErrInvalidPolicy, rule.ID, pattern)
+			}
+		}
+
+		for _, condition := range rule.Conditions {
+			err := validateCondition(&condition)
+			if err != nil {
+				return fmt.Errorf("%w: rule %q: %v", domain.ErrInvalidPolicy, rule.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCondition checks the attribute, operator and values of a condition
+func validateCondition(condition *domain.PolicyCondition) error {
+	if !strings.HasPrefix(condition.Attribute, principalPrefix) && !strings.HasPrefix(condition.Attribute, resourcePrefix) {
+		return fmt.Errorf("attribute %q must start with %q or %q", condition.Attribute, principalPrefix, resourcePrefix)
+	}
+
+	switch condition.Operator {
+	case domain.OperatorEqual, domain.OperatorNotEqual:
+		if len(condition.Values) != 1 {
+			return fmt.Errorf("operator %q needs exactly one value", condition.Operator)
+		}
+	case domain.OperatorIn:
+		if len(condition.Values) == 0 {
+			return fmt.Errorf("operator %q needs at least one value", condition.Operator)
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", condition.Operator)
+	}
+
+	return nil
+}
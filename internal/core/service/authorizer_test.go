@@ -0,0 +1,327 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// testPolicy lets admins do anything, users manage themselves, managers update cashiers
+// and denies deleting the root user to anyone
+var testPolicy = &domain.Policy{
+	Version: "1",
+	Rules: []domain.PolicyRule{
+		{
+			ID:        "admin-all",
+			Effect:    domain.Allow,
+			Subjects:  []string{"role:admin"},
+			Actions:   []string{"*"},
+			Resources: []string{"*"},
+		},
+		{
+			ID:        "self-manage",
+			Effect:    domain.Allow,
+			Subjects:  []string{"*"},
+			Actions:   []string{"users:update", "users:delete"},
+			Resources: []string{"users/*"},
+			Conditions: []domain.PolicyCondition{
+				{
+					Attribute: "resource.id",
+					Operator:  domain.OperatorEqual,
+					Values:    []string{"principal.id"},
+				},
+			},
+		},
+		{
+			ID:        "manager-edit-cashiers",
+			Effect:    domain.Allow,
+			Subjects:  []string{"role:manager"},
+			Actions:   []string{"users:update"},
+			Resources: []string{"users/*"},
+			Conditions: []domain.PolicyCondition{
+				{
+					Attribute: "resource.role",
+					Operator:  domain.OperatorIn,
+					Values:    []string{"cashier"},
+				},
+			},
+		},
+		{
+			ID:        "protect-root",
+			Effect:    domain.Deny,
+			Subjects:  []string{"*"},
+			Actions:   []string{"users:delete"},
+			Resources: []string{"users/1"},
+		},
+	},
+}
+
+type canTestedInput struct {
+	principal *domain.TokenPayload
+	action    string
+	resource  *domain.PolicyResource
+}
+
+type canExpectedOutput struct {
+	allowed bool
+	ruleID  string
+}
+
+func TestAuthorizerService_Can(t *testing.T) {
+	ctx := context.Background()
+
+	admin := &domain.TokenPayload{UserID: 1, Role: domain.Admin}
+	manager := &domain.TokenPayload{UserID: 2, Role: domain.Manager}
+	cashier := &domain.TokenPayload{UserID: 3, Role: domain.Cashier}
+	scopedManager := &domain.TokenPayload{UserID: 2, Role: domain.Manager, Scopes: []string{"users:read"}, APIKeyID: 7}
+
+	cashierResource := &domain.PolicyResource{
+		Type:       "users",
+		ID:         "3",
+		Attributes: map[string]string{"role": string(domain.Cashier)},
+	}
+	adminResource := &domain.PolicyResource{
+		Type:       "users",
+		ID:         "1",
+		Attributes: map[string]string{"role": string(domain.Admin)},
+	}
+
+	testCases := []struct {
+		desc     string
+		input    canTestedInput
+		expected canExpectedOutput
+	}{
+		{
+			desc: "Allow_Admin",
+			input: canTestedInput{
+				principal: admin,
+				action:    "users:change_role",
+				resource:  cashierResource,
+			},
+			expected: canExpectedOutput{
+				allowed: true,
+				ruleID:  "admin-all",
+			},
+		},
+		{
+			desc: "Allow_Self",
+			input: canTestedInput{
+				principal: cashier,
+				action:    "users:delete",
+				resource:  cashierResource,
+			},
+			expected: canExpectedOutput{
+				allowed: true,
+				ruleID:  "self-manage",
+			},
+		},
+		{
+			desc: "Allow_ManagerCashier",
+			input: canTestedInput{
+				principal: manager,
+				action:    "users:update",
+				resource:  cashierResource,
+			},
+			expected: canExpectedOutput{
+				allowed: true,
+				ruleID:  "manager-edit-cashiers",
+			},
+		},
+		{
+			desc: "Deny_ManagerAdmin",
+			input: canTestedInput{
+				principal: manager,
+				action:    "users:update",
+				resource:  adminResource,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+			},
+		},
+		{
+			desc: "Deny_ManagerDeleteCashier",
+			input: canTestedInput{
+				principal: manager,
+				action:    "users:delete",
+				resource:  cashierResource,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+			},
+		},
+		{
+			desc: "Deny_CashierOther",
+			input: canTestedInput{
+				principal: cashier,
+				action:    "users:update",
+				resource:  adminResource,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+			},
+		},
+		{
+			desc: "Deny_OutsideScopes",
+			input: canTestedInput{
+				principal: scopedManager,
+				action:    "users:update",
+				resource:  cashierResource,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+			},
+		},
+		{
+			desc: "Deny_OverridesAllow",
+			input: canTestedInput{
+				principal: admin,
+				action:    "users:delete",
+				resource:  adminResource,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+				ruleID:  "protect-root",
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	policyRepo := mock.NewMockPolicyRepository(ctrl)
+	policyRepo.EXPECT().
+		GetPolicy(gomock.Any()).
+		Return(testPolicy, nil)
+
+	authorizer := service.NewAuthorizerService(policyRepo)
+	err := authorizer.Reload(ctx)
+	assert.NoError(t, err, "Reload error")
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			decision, err := authorizer.Can(ctx, tc.input.principal, tc.input.action, tc.input.resource)
+			assert.NoError(t, err, "Error mismatch")
+			assert.Equal(t, tc.expected.allowed, decision.Allowed, "Allowed mismatch")
+			assert.Equal(t, tc.expected.ruleID, decision.RuleID, "Rule mismatch")
+			assert.Len(t, decision.Evaluations, len(testPolicy.Rules), "Evaluations mismatch")
+		})
+	}
+}
+
+func TestAuthorizerService_Can_NotLoaded(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	policyRepo := mock.NewMockPolicyRepository(ctrl)
+	authorizer := service.NewAuthorizerService(policyRepo)
+
+	decision, err := authorizer.Can(ctx, &domain.TokenPayload{}, "users:update", &domain.PolicyResource{Type: "users"})
+	assert.Equal(t, domain.ErrPolicyNotLoaded, err, "Error mismatch")
+	assert.Nil(t, decision, "Decision mismatch")
+}
+
+type reloadExpectedOutput struct {
+	err error
+}
+
+func TestAuthorizerService_Reload(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		desc     string
+		mocks    func(policyRepo *mock.MockPolicyRepository)
+		expected reloadExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(policyRepo *mock.MockPolicyRepository) {
+				policyRepo.EXPECT().
+					GetPolicy(gomock.Any()).
+					Return(testPolicy, nil)
+			},
+			expected: reloadExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_Repository",
+			mocks: func(policyRepo *mock.MockPolicyRepository) {
+				policyRepo.EXPECT().
+					GetPolicy(gomock.Any()).
+					Return(nil, domain.ErrInvalidPolicy)
+			},
+			expected: reloadExpectedOutput{
+				err: domain.ErrInvalidPolicy,
+			},
+		},
+		{
+			desc: "Fail_DuplicateRule",
+			mocks: func(policyRepo *mock.MockPolicyRepository) {
+				policyRepo.EXPECT().
+					GetPolicy(gomock.Any()).
+					Return(&domain.Policy{
+						Rules: []domain.PolicyRule{testPolicy.Rules[0], testPolicy.Rules[0]},
+					}, nil)
+			},
+			expected: reloadExpectedOutput{
+				err: domain.ErrInvalidPolicy,
+			},
+		},
+		{
+			desc: "Fail_UnknownOperator",
+			mocks: func(policyRepo *mock.MockPolicyRepository) {
+				policyRepo.EXPECT().
+					GetPolicy(gomock.Any()).
+					Return(&domain.Policy{
+						Rules: []domain.PolicyRule{
+							{
+								ID:        "bad",
+								Effect:    domain.Allow,
+								Subjects:  []string{"*"},
+								Actions:   []string{"*"},
+								Resources: []string{"*"},
+								Conditions: []domain.PolicyCondition{
+									{
+										Attribute: "resource.role",
+										Operator:  "gt",
+										Values:    []string{"cashier"},
+									},
+								},
+							},
+						},
+					}, nil)
+			},
+			expected: reloadExpectedOutput{
+				err: domain.ErrInvalidPolicy,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			policyRepo := mock.NewMockPolicyRepository(ctrl)
+
+			tc.mocks(policyRepo)
+
+			authorizer := service.NewAuthorizerService(policyRepo)
+
+			err := authorizer.Reload(ctx)
+			assert.True(t, errors.Is(err, tc.expected.err), "Error mismatch")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+)
+
+/**
+ * ImpersonationService implements port.ImpersonationService interface
+ * and provides an access to the impersonation repository, user repository,
+ * token service and cache repository.
+ * Every impersonation and every request made while impersonating is recorded
+ */
+type ImpersonationService struct {
+	repo     port.ImpersonationRepository
+	userRepo port.UserRepository
+	ts       port.TokenService
+	cache    port.CacheRepository
+}
+
+// NewImpersonationService creates a new impersonation service instance
+func NewImpersonationService(
+	repo port.ImpersonationRepository,
+	userRepo port.UserRepository,
+	ts port.TokenService,
+	cache port.CacheRepository,
+) *ImpersonationService {
+	return &ImpersonationService{
+		repo,
+		userRepo,
+		ts,
+		cache,
+	}
+}
+
+// Impersonate issues an access token that acts as the user and records who asked for it and why.
+// The token has no refresh token, it cannot be used to impersonate further and it is revoked along with the user's other tokens.
+// Only users signed in with a password or single sign-on may impersonate, and admins cannot be impersonated
+func (is *ImpersonationService) Impersonate(ctx context.Context, actor *domain.TokenPayload, userID uint64, reason string) (*domain.Impersonation, string, error) {
+	if actor.APIKeyID != 0 || actor.ClientID != "" || actor.ImpersonatorID != 0 || actor.UserID == userID {
+		return nil, "", domain.ErrForbidden
+	}
+
+	user, err := is.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, "", err
+		}
+		return nil, "", domain.ErrInternal
+	}
+
+	if user.Role == domain.Admin {
+		return nil, "", domain.ErrForbidden
+	}
+
+	generation, err := tokenGeneration(ctx, is.cache, user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload := &domain.TokenPayload{
+		UserID:         user.ID,
+		Role:           user.Role,
+		Generation:     generation,
+		ImpersonatorID: actor.UserID,
+	}
+
+	accessToken, err := is.ts.CreateToken(payload)
+	if err != nil {
+		return nil, "", domain.ErrTokenCreation
+	}
+
+	impersonation, err := is.repo.CreateImpersonation(ctx, &domain.Impersonation{
+		ID:             payload.ID,
+		ImpersonatorID: actor.UserID,
+		UserID:         user.ID,
+		Reason:         reason,
+		ExpiresAt:      payload.ExpiredAt,
+	})
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+
+	return impersonation, accessToken, nil
+}
+
+// RecordRequest records a request made with an impersonation token
+func (is *ImpersonationService) RecordRequest(ctx context.Context, request *domain.ImpersonatedRequest) error {
+	_, err := is.repo.CreateImpersonatedRequest(ctx, request)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
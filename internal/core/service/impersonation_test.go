@@ -0,0 +1,255 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type impersonationMocks struct {
+	repo     *mock.MockImpersonationRepository
+	userRepo *mock.MockUserRepository
+	ts       *mock.MockTokenService
+	cache    *mock.MockCacheRepository
+}
+
+// newImpersonationService creates an impersonation service backed by fresh mocks
+func newImpersonationService(ctrl *gomock.Controller) (*service.ImpersonationService, *impersonationMocks) {
+	mocks := &impersonationMocks{
+		repo:     mock.NewMockImpersonationRepository(ctrl),
+		userRepo: mock.NewMockUserRepository(ctrl),
+		ts:       mock.NewMockTokenService(ctrl),
+		cache:    mock.NewMockCacheRepository(ctrl),
+	}
+
+	impersonationService := service.NewImpersonationService(
+		mocks.repo,
+		mocks.userRepo,
+		mocks.ts,
+		mocks.cache,
+	)
+
+	return impersonationService, mocks
+}
+
+func TestImpersonationService_Impersonate(t *testing.T) {
+	ctx := context.Background()
+	reason := "support ticket #1234"
+	accessToken := "access-token"
+	tokenID := uuid.New()
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	admin := &domain.TokenPayload{
+		UserID:    1,
+		Role:      domain.Admin,
+		TwoFactor: true,
+	}
+	user := &domain.User{
+		ID:   2,
+		Name: "John Doe",
+		Role: domain.Cashier,
+	}
+	otherAdmin := &domain.User{
+		ID:   3,
+		Name: "Jane Doe",
+		Role: domain.Admin,
+	}
+	impersonation := &domain.Impersonation{
+		ID:             tokenID,
+		ImpersonatorID: admin.UserID,
+		UserID:         user.ID,
+		Reason:         reason,
+		ExpiresAt:      expiresAt,
+	}
+	generationKey := util.GenerateCacheKey("token_generation", user.ID)
+
+	testCases := []struct {
+		desc     string
+		actor    *domain.TokenPayload
+		userID   uint64
+		mocks    func(m *impersonationMocks)
+		output   *domain.Impersonation
+		token    string
+		expected error
+	}{
+		{
+			desc:   "Success",
+			actor:  admin,
+			userID: user.ID,
+			mocks: func(m *impersonationMocks) {
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Return([]byte("4"), nil)
+				m.ts.EXPECT().
+					CreateToken(gomock.Any()).
+					DoAndReturn(func(payload *domain.TokenPayload) (string, error) {
+						assert.Equal(t, user.ID, payload.UserID, "User mismatch")
+						assert.Equal(t, user.Role, payload.Role, "Role mismatch")
+						assert.Equal(t, uint64(4), payload.Generation, "Generation mismatch")
+						assert.Equal(t, admin.UserID, payload.ImpersonatorID, "Impersonator mismatch")
+						assert.False(t, payload.TwoFactor, "Impersonation inherited second factor")
+						payload.ID = tokenID
+						payload.ExpiredAt = expiresAt
+						return accessToken, nil
+					})
+				m.repo.EXPECT().
+					CreateImpersonation(gomock.Any(), gomock.Eq(impersonation)).
+					Return(impersonation, nil)
+			},
+			output:   impersonation,
+			token:    accessToken,
+			expected: nil,
+		},
+		{
+			desc:     "Fail_Self",
+			actor:    admin,
+			userID:   admin.UserID,
+			mocks:    func(m *impersonationMocks) {},
+			expected: domain.ErrForbidden,
+		},
+		{
+			desc: "Fail_AlreadyImpersonating",
+			actor: &domain.TokenPayload{
+				UserID:         user.ID,
+				Role:           user.Role,
+				ImpersonatorID: admin.UserID,
+			},
+			userID:   otherAdmin.ID,
+			mocks:    func(m *impersonationMocks) {},
+			expected: domain.ErrForbidden,
+		},
+		{
+			desc: "Fail_DelegatedActor",
+			actor: &domain.TokenPayload{
+				UserID:   admin.UserID,
+				Role:     admin.Role,
+				APIKeyID: 7,
+			},
+			userID:   user.ID,
+			mocks:    func(m *impersonationMocks) {},
+			expected: domain.ErrForbidden,
+		},
+		{
+			desc:   "Fail_AdminTarget",
+			actor:  admin,
+			userID: otherAdmin.ID,
+			mocks: func(m *impersonationMocks) {
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(otherAdmin.ID)).
+					Return(otherAdmin, nil)
+			},
+			expected: domain.ErrForbidden,
+		},
+		{
+			desc:   "Fail_UserNotFound",
+			actor:  admin,
+			userID: user.ID,
+			mocks: func(m *impersonationMocks) {
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: domain.ErrDataNotFound,
+		},
+		{
+			desc:   "Fail_InternalError",
+			actor:  admin,
+			userID: user.ID,
+			mocks: func(m *impersonationMocks) {
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(generationKey)).
+					Return(nil, domain.ErrDataNotFound)
+				m.ts.EXPECT().
+					CreateToken(gomock.Any()).
+					Return(accessToken, nil)
+				m.repo.EXPECT().
+					CreateImpersonation(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			impersonationService, mocks := newImpersonationService(ctrl)
+			tc.mocks(mocks)
+
+			output, token, err := impersonationService.Impersonate(ctx, tc.actor, tc.userID, reason)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			assert.Equal(t, tc.output, output, "Impersonation mismatch")
+			assert.Equal(t, tc.token, token, "Token mismatch")
+		})
+	}
+}
+
+func TestImpersonationService_RecordRequest(t *testing.T) {
+	ctx := context.Background()
+	request := &domain.ImpersonatedRequest{
+		ImpersonationID: uuid.New(),
+		Method:          "GET",
+		Path:            "/v1/orders",
+		Status:          200,
+		IP:              "203.0.113.7",
+		UserAgent:       "Mozilla/5.0",
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(m *impersonationMocks)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(m *impersonationMocks) {
+				m.repo.EXPECT().
+					CreateImpersonatedRequest(gomock.Any(), gomock.Eq(request)).
+					Return(request, nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(m *impersonationMocks) {
+				m.repo.EXPECT().
+					CreateImpersonatedRequest(gomock.Any(), gomock.Eq(request)).
+					Return(nil, domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			impersonationService, mocks := newImpersonationService(ctrl)
+			tc.mocks(mocks)
+
+			err := impersonationService.RecordRequest(ctx, request)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+// lockoutMemory is how long the number of lockouts is remembered to escalate the next lockout
+const lockoutMemory = 24 * time.Hour
+
+/**
+ * LockoutService implements port.LoginLimiter interface
+ * and tracks failed logins per email and per client IP in the cache repository.
+ * Once the threshold is reached, logins are locked for a duration that doubles
+ * on every lockout up to the configured maximum
+ */
+type LockoutService struct {
+	cache  port.CacheRepository
+	policy domain.LockoutPolicy
+}
+
+// NewLockoutService creates a new lockout service instance
+func NewLockoutService(cache port.CacheRepository, policy domain.LockoutPolicy) *LockoutService {
+	return &LockoutService{
+		cache,
+		policy,
+	}
+}
+
+// Check returns an *domain.AccountLockedError if logins for the email or from the client IP are locked
+func (ls *LockoutService) Check(ctx context.Context, email string) error {
+	info := util.ClientInfoFromContext(ctx)
+
+	for _, subject := range ls.subjects(email, info.IP) {
+		value, err := ls.cache.Get(ctx, lockoutKey("login_lock", subject))
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				continue
+			}
+			return domain.ErrInternal
+		}
+
+		unlockAt, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		retryAfter := time.Until(time.Unix(unlockAt, 0))
+		if retryAfter > 0 {
+			return &domain.AccountLockedError{
+				RetryAfter: retryAfter,
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisterFailure counts a failed login for the email and the client IP,
+// returning an *domain.AccountLockedError if it reached the threshold
+func (ls *LockoutService) RegisterFailure(ctx context.Context, email string) error {
+	info := util.ClientInfoFromContext(ctx)
+
+	var lockedErr error
+
+	for _, subject := range ls.subjects(email, info.IP) {
+		maxAttempts := ls.policy.MaxAttempts
+		if strings.HasPrefix(subject, "ip:") {
+			maxAttempts = ls.policy.MaxAttemptsPerIP
+		}
+
+		if maxAttempts <= 0 {
+			continue
+		}
+
+		failures, err := ls.cache.Increment(ctx, lockoutKey("login_failures", subject), ls.policy.AttemptWindow)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		if failures < maxAttempts {
+			continue
+		}
+
+		retryAfter, err := ls.lock(ctx, subject)
+		if err != nil {
+			return err
+		}
+
+		lockedErr = &domain.AccountLockedError{
+			RetryAfter: retryAfter,
+		}
+	}
+
+	return lockedErr
+}
+
+// RegisterSuccess forgets the failed logins of the email, failures from the client IP are kept
+func (ls *LockoutService) RegisterSuccess(ctx context.Context, email string) error {
+	return ls.reset(ctx, email, "login_failures", "login_lockouts")
+}
+
+// Unlock lifts the lock of the email and forgets its failed logins and previous lockouts
+func (ls *LockoutService) Unlock(ctx context.Context, email string) error {
+	return ls.reset(ctx, email, "login_lock", "login_failures", "login_lockouts")
+}
+
+// lock locks the subject for a duration escalating with its number of lockouts
+func (ls *LockoutService) lock(ctx context.Context, subject string) (time.Duration, error) {
+	lockouts, err := ls.cache.Increment(ctx, lockoutKey("login_lockouts", subject), lockoutMemory)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	duration := ls.lockoutDuration(lockouts)
+	unlockAt := time.Now().Add(duration)
+	value := []byte(strconv.FormatInt(unlockAt.Unix(), 10))
+
+	err = ls.cache.Set(ctx, lockoutKey("login_lock", subject), value, duration)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	err = ls.cache.Delete(ctx, lockoutKey("login_failures", subject))
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return duration, nil
+}
+
+// lockoutDuration doubles the lockout duration for every previous lockout, up to the maximum
+func (ls *LockoutService) lockoutDuration(lockouts int64) time.Duration {
+	duration := ls.policy.LockoutDuration
+	maxDuration := max(ls.policy.MaxLockoutDuration, duration)
+
+	for i := int64(1); i < lockouts && duration < maxDuration; i++ {
+		duration *= 2
+	}
+
+	return min(duration, maxDuration)
+}
+
+// reset deletes the given lockout keys of the email
+func (ls *LockoutService) reset(ctx context.Context, email string, prefixes ...string) error {
+	subject := "email:" + normalizeEmail(email)
+
+	for _, prefix := range prefixes {
+		err := ls.cache.Delete(ctx, lockoutKey(prefix, subject))
+		if err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+// subjects returns the subjects whose failed logins are tracked
+func (ls *LockoutService) subjects(email, ip string) []string {
+	subjects := []string{"email:" + normalizeEmail(email)}
+
+	if ip != "" {
+		subjects = append(subjects, "ip:"+ip)
+	}
+
+	return subjects
+}
+
+// lockoutKey generates the cache key of a lockout counter or lock
+func lockoutKey(prefix, subject string) string {
+	return util.GenerateCacheKey(prefix, subject)
+}
+
+// normalizeEmail lowercases the email so that case variations share the same counters
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,282 @@
+package service_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var lockoutPolicy = domain.LockoutPolicy{
+	MaxAttempts:        5,
+	MaxAttemptsPerIP:   20,
+	AttemptWindow:      15 * time.Minute,
+	LockoutDuration:    time.Minute,
+	MaxLockoutDuration: time.Hour,
+}
+
+type lockoutExpectedOutput struct {
+	retryAfter time.Duration
+	err        error
+}
+
+func TestLockoutService_Check(t *testing.T) {
+	ip := "10.0.0.1"
+	ctx := util.ContextWithClientInfo(context.Background(), &domain.ClientInfo{IP: ip})
+	email := "Test@Example.com"
+	emailLockKey := util.GenerateCacheKey("login_lock", "email:test@example.com")
+	ipLockKey := util.GenerateCacheKey("login_lock", "ip:"+ip)
+	unlockAt := []byte(strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	expiredAt := []byte(strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+
+	testCases := []struct {
+		desc     string
+		mocks    func(cache *mock.MockCacheRepository)
+		expected lockoutExpectedOutput
+	}{
+		{
+			desc: "Success_NotLocked",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(emailLockKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(ipLockKey)).
+					Return(expiredAt, nil)
+			},
+			expected: lockoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_EmailLocked",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(emailLockKey)).
+					Return(unlockAt, nil)
+			},
+			expected: lockoutExpectedOutput{
+				retryAfter: time.Hour,
+				err:        domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_IPLocked",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(emailLockKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(ipLockKey)).
+					Return(unlockAt, nil)
+			},
+			expected: lockoutExpectedOutput{
+				retryAfter: time.Hour,
+				err:        domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(emailLockKey)).
+					Return(nil, domain.ErrInternal)
+			},
+			expected: lockoutExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(cache)
+
+			lockoutService := service.NewLockoutService(cache, lockoutPolicy)
+
+			err := lockoutService.Check(ctx, email)
+			assertLockout(t, tc.expected, err)
+		})
+	}
+}
+
+func TestLockoutService_RegisterFailure(t *testing.T) {
+	ip := "10.0.0.1"
+	ctx := util.ContextWithClientInfo(context.Background(), &domain.ClientInfo{IP: ip})
+	email := "test@example.com"
+	emailFailuresKey := util.GenerateCacheKey("login_failures", "email:"+email)
+	emailLockoutsKey := util.GenerateCacheKey("login_lockouts", "email:"+email)
+	emailLockKey := util.GenerateCacheKey("login_lock", "email:"+email)
+	ipFailuresKey := util.GenerateCacheKey("login_failures", "ip:"+ip)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(cache *mock.MockCacheRepository)
+		expected lockoutExpectedOutput
+	}{
+		{
+			desc: "Success_BelowThreshold",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(4), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(ipFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(4), nil)
+			},
+			expected: lockoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_Locked",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(5), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailLockoutsKey), gomock.Any()).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(emailLockKey), gomock.Any(), gomock.Eq(time.Minute)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(emailFailuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(ipFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(5), nil)
+			},
+			expected: lockoutExpectedOutput{
+				retryAfter: time.Minute,
+				err:        domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_LockedEscalated",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(5), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailLockoutsKey), gomock.Any()).
+					Return(int64(3), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(emailLockKey), gomock.Any(), gomock.Eq(4*time.Minute)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(emailFailuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(ipFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(1), nil)
+			},
+			expected: lockoutExpectedOutput{
+				retryAfter: 4 * time.Minute,
+				err:        domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_LockedCapped",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(5), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailLockoutsKey), gomock.Any()).
+					Return(int64(100), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(emailLockKey), gomock.Any(), gomock.Eq(time.Hour)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(emailFailuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(ipFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(1), nil)
+			},
+			expected: lockoutExpectedOutput{
+				retryAfter: time.Hour,
+				err:        domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(emailFailuresKey), gomock.Eq(lockoutPolicy.AttemptWindow)).
+					Return(int64(0), domain.ErrInternal)
+			},
+			expected: lockoutExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(cache)
+
+			lockoutService := service.NewLockoutService(cache, lockoutPolicy)
+
+			err := lockoutService.RegisterFailure(ctx, email)
+			assertLockout(t, tc.expected, err)
+		})
+	}
+}
+
+func TestLockoutService_Unlock(t *testing.T) {
+	ctx := context.Background()
+	email := "test@example.com"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock.NewMockCacheRepository(ctrl)
+	for _, prefix := range []string{"login_lock", "login_failures", "login_lockouts"} {
+		cache.EXPECT().
+			Delete(gomock.Any(), gomock.Eq(util.GenerateCacheKey(prefix, "email:"+email))).
+			Return(nil)
+	}
+
+	lockoutService := service.NewLockoutService(cache, lockoutPolicy)
+
+	err := lockoutService.Unlock(ctx, email)
+	assert.NoError(t, err, "Error mismatch")
+}
+
+// assertLockout checks the error and, for lock errors, the time to wait before retrying
+func assertLockout(t *testing.T, expected lockoutExpectedOutput, err error) {
+	t.Helper()
+
+	assert.ErrorIs(t, err, expected.err, "Error mismatch")
+
+	if expected.retryAfter > 0 {
+		lockedErr, ok := err.(*domain.AccountLockedError)
+		assert.True(t, ok, "Error type mismatch")
+		if ok {
+			assert.InDelta(t, expected.retryAfter, lockedErr.RetryAfter, float64(5*time.Second), "Retry after mismatch")
+		}
+	}
+}
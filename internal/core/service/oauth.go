@@ -0,0 +1,506 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+const (
+	// oauthClientIDSize is the number of random bytes used to generate an oauth client ID
+	oauthClientIDSize = 16
+	// oauthClientSecretSize is the number of random bytes used to generate an oauth client secret
+	oauthClientSecretSize = 32
+	// oauthCodeSize is the number of random bytes used to generate an authorization code
+	oauthCodeSize = 32
+	// oauthCodeDuration is how long an authorization code can be exchanged for an access token
+	oauthCodeDuration = 5 * time.Minute
+	// codeChallengeLength is the length of a S256 code challenge, the base64url encoded SHA-256 hash of the verifier
+	codeChallengeLength = 43
+	// minCodeVerifierLength and maxCodeVerifierLength bound the length of a PKCE code verifier
+	minCodeVerifierLength = 43
+	maxCodeVerifierLength = 128
+)
+
+/**
+ * OAuthService implements port.OAuthService interface
+ * and provides an access to the oauth client repository, user repository,
+ * token service and cache repository. Authorization codes live in the cache,
+ * only the hash of client secrets and codes is stored
+ */
+type OAuthService struct {
+	clientRepo port.OAuthClientRepository
+	userRepo   port.UserRepository
+	ts         port.TokenService
+	cache      port.CacheRepository
+}
+
+// NewOAuthService creates a new oauth service instance
+func NewOAuthService(
+	clientRepo port.OAuthClientRepository,
+	userRepo port.UserRepository,
+	ts port.TokenService,
+	cache port.CacheRepository,
+) *OAuthService {
+	return &OAuthService{
+		clientRepo,
+		userRepo,
+		ts,
+		cache,
+	}
+}
+
+// RegisterClient registers an oauth client and returns it with its secret, which is only shown once.
+// Only confidential clients get a secret, public clients must authorize users with PKCE
+func (oas *OAuthService) RegisterClient(ctx context.Context, actor *domain.TokenPayload, client *domain.OAuthClient) (*domain.OAuthClient, string, error) {
+	if isDelegated(actor) {
+		return nil, "", domain.ErrForbidden
+	}
+
+	if len(client.Scopes) == 0 {
+		return nil, "", domain.ErrInvalidScope
+	}
+
+	if !client.Confidential && len(client.RedirectURIs) == 0 {
+		return nil, "", domain.ErrInvalidRedirectURI
+	}
+
+	clientID, err := util.GenerateRandomToken(oauthClientIDSize)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+
+	var secret string
+	if client.Confidential {
+		secret, err = util.GenerateRandomToken(oauthClientSecretSize)
+		if err != nil {
+			return nil, "", domain.ErrInternal
+		}
+
+		client.SecretHash = util.HashToken(secret)
+	}
+
+	client.ClientID = clientID
+	client.CreatedBy = &actor.UserID
+
+	client, err = oas.clientRepo.CreateOAuthClient(ctx, client)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+
+	return client, secret, nil
+}
+
+// ListClients lists every registered oauth client
+func (oas *OAuthService) ListClients(ctx context.Context) ([]domain.OAuthClient, error) {
+	clients, err := oas.clientRepo.ListOAuthClients(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return clients, nil
+}
+
+// DeleteClient deletes an oauth client and the consents given to it,
+// the access tokens already issued to the client are revoked as well
+func (oas *OAuthService) DeleteClient(ctx context.Context, clientID string) error {
+	client, err := oas.clientRepo.GetOAuthClientByClientID(ctx, clientID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	err = oas.clientRepo.DeleteOAuthClient(ctx, client.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("oauth_client_deleted", client.ClientID)
+	err = oas.cache.Set(ctx, cacheKey, []byte("1"), 0)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// PrepareAuthorization validates an authorization request and returns what the user is asked to consent to,
+// consent is not required again when the user already granted every requested scope to the client
+func (oas *OAuthService) PrepareAuthorization(ctx context.Context, actor *domain.TokenPayload, req *domain.OAuthAuthorizationRequest) (*domain.OAuthConsentPrompt, error) {
+	if isDelegated(actor) {
+		return nil, domain.ErrForbidden
+	}
+
+	client, redirectURI, scopes, err := oas.validateAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := oas.clientRepo.GetOAuthConsent(ctx, actor.UserID, client.ID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+
+	consentRequired := consent == nil
+	if consent != nil {
+		for _, scope := range scopes {
+			if !slices.Contains(consent.Scopes, scope) {
+				consentRequired = true
+				break
+			}
+		}
+	}
+
+	return &domain.OAuthConsentPrompt{
+		Client:          client,
+		RedirectURI:     redirectURI,
+		Scopes:          scopes,
+		ConsentRequired: consentRequired,
+	}, nil
+}
+
+// Authorize records the decision of the user on an authorization request and returns the url to redirect the user to.
+// An approval remembers the consent and redirects with a single-use authorization code bound to the PKCE code challenge,
+// a denial redirects with the access_denied error
+func (oas *OAuthService) Authorize(ctx context.Context, actor *domain.TokenPayload, req *domain.OAuthAuthorizationRequest, approved bool) (string, error) {
+	if isDelegated(actor) {
+		return "", domain.ErrForbidden
+	}
+
+	client, redirectURI, scopes, err := oas.validateAuthorization(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if !approved {
+		return redirectWith(redirectURI, map[string]string{
+			"error": "access_denied",
+			"state": req.State,
+		})
+	}
+
+	err = oas.saveConsent(ctx, actor.UserID, client.ID, scopes)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := util.GenerateRandomToken(oauthCodeSize)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	value, err := util.Serialize(&domain.OAuthAuthorizationCode{
+		UserID:        actor.UserID,
+		ClientID:      client.ClientID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+	})
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("oauth_code", util.HashToken(code))
+	err = oas.cache.Set(ctx, cacheKey, value, oauthCodeDuration)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	return redirectWith(redirectURI, map[string]string{
+		"code":  code,
+		"state": req.State,
+	})
+}
+
+// Token authenticates the client and issues an access token for the authorization code or client credentials grant.
+// Access tokens are restricted to the granted scopes and carry the client ID
+func (oas *OAuthService) Token(ctx context.Context, req *domain.OAuthTokenRequest) (*domain.OAuthToken, error) {
+	client, err := oas.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case domain.AuthorizationCodeGrant:
+		return oas.exchangeCode(ctx, client, req)
+	case domain.ClientCredentialsGrant:
+		return oas.clientCredentials(ctx, client, req.Scopes)
+	default:
+		return nil, domain.ErrUnsupportedGrantType
+	}
+}
+
+// Revoke revokes an access token issued to the client until it expires.
+// Invalid tokens and tokens of other clients are ignored, as the revocation endpoint does not reveal them
+func (oas *OAuthService) Revoke(ctx context.Context, clientID, clientSecret, token string) error {
+	client, err := oas.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	payload, err := oas.ts.VerifyToken(token)
+	if err != nil || payload.ClientID != client.ClientID {
+		return nil
+	}
+
+	ttl := time.Until(payload.ExpiredAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	cacheKey := util.GenerateCacheKey("revoked_token", payload.ID)
+	err = oas.cache.Set(ctx, cacheKey, []byte("1"), ttl)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// validateAuthorization checks the client, redirect uri, PKCE code challenge and scopes of an authorization request,
+// returning the redirect uri and scopes to use when the request leaves them out
+func (oas *OAuthService) validateAuthorization(ctx context.Context, req *domain.OAuthAuthorizationRequest) (*domain.OAuthClient, string, []string, error) {
+	client, err := oas.clientRepo.GetOAuthClientByClientID(ctx, req.ClientID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, "", nil, domain.ErrInvalidOAuthClient
+		}
+		return nil, "", nil, domain.ErrInternal
+	}
+
+	redirectURI := req.RedirectURI
+	if redirectURI == "" && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+	if !slices.Contains(client.RedirectURIs, redirectURI) {
+		return nil, "", nil, domain.ErrInvalidRedirectURI
+	}
+
+	if req.CodeChallengeMethod != domain.CodeChallengeS256 || len(req.CodeChallenge) != codeChallengeLength {
+		return nil, "", nil, domain.ErrInvalidCodeChallenge
+	}
+
+	scopes, err := grantedScopes(client, req.Scopes)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return client, redirectURI, scopes, nil
+}
+
+// saveConsent adds the scopes to the consent the user gave to the client
+func (oas *OAuthService) saveConsent(ctx context.Context, userID, clientID uint64, scopes []string) error {
+	consent, err := oas.clientRepo.GetOAuthConsent(ctx, userID, clientID)
+	if err != nil {
+		if err != domain.ErrDataNotFound {
+			return domain.ErrInternal
+		}
+
+		consent = &domain.OAuthConsent{
+			UserID:   userID,
+			ClientID: clientID,
+		}
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(consent.Scopes, scope) {
+			consent.Scopes = append(consent.Scopes, scope)
+		}
+	}
+
+	_, err = oas.clientRepo.SaveOAuthConsent(ctx, consent)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// authenticateClient looks up the client and checks the secret of confidential clients
+func (oas *OAuthService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	if clientID == "" {
+		return nil, domain.ErrInvalidOAuthClient
+	}
+
+	client, err := oas.clientRepo.GetOAuthClientByClientID(ctx, clientID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidOAuthClient
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if client.Confidential && subtle.ConstantTimeCompare([]byte(util.HashToken(clientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, domain.ErrInvalidOAuthClient
+	}
+
+	return client, nil
+}
+
+// exchangeCode exchanges a single-use authorization code for an access token of the user who approved it,
+// the code verifier must match the code challenge of the authorization request
+func (oas *OAuthService) exchangeCode(ctx context.Context, client *domain.OAuthClient, req *domain.OAuthTokenRequest) (*domain.OAuthToken, error) {
+	if req.Code == "" {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	codeHash := util.HashToken(req.Code)
+	cacheKey := util.GenerateCacheKey("oauth_code", codeHash)
+
+	value, err := oas.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	usedKey := util.GenerateCacheKey("oauth_code_used", codeHash)
+	uses, err := oas.cache.Increment(ctx, usedKey, oauthCodeDuration)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if uses > 1 {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	err = oas.cache.Delete(ctx, cacheKey)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	var code domain.OAuthAuthorizationCode
+	err = util.Deserialize(value, &code)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if code.ClientID != client.ClientID || code.RedirectURI != req.RedirectURI {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	if !verifyCodeChallenge(req.CodeVerifier, code.CodeChallenge) {
+		return nil, domain.ErrInvalidGrant
+	}
+
+	user, err := oas.userRepo.GetUserByID(ctx, code.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidGrant
+		}
+		return nil, domain.ErrInternal
+	}
+
+	generation, err := tokenGeneration(ctx, oas.cache, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return oas.issueToken(&domain.TokenPayload{
+		UserID:     user.ID,
+		Role:       user.Role,
+		Generation: generation,
+		Scopes:     code.Scopes,
+		ClientID:   client.ClientID,
+	})
+}
+
+// clientCredentials issues an access token for the client itself, only confidential clients may use this grant
+func (oas *OAuthService) clientCredentials(ctx context.Context, client *domain.OAuthClient, requested []string) (*domain.OAuthToken, error) {
+	if !client.Confidential {
+		return nil, domain.ErrUnauthorizedOAuthClient
+	}
+
+	scopes, err := grantedScopes(client, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	return oas.issueToken(&domain.TokenPayload{
+		Subject:  "client:" + client.ClientID,
+		Scopes:   scopes,
+		ClientID: client.ClientID,
+	})
+}
+
+// issueToken creates the access token of a token response
+func (oas *OAuthService) issueToken(payload *domain.TokenPayload) (*domain.OAuthToken, error) {
+	accessToken, err := oas.ts.CreateToken(payload)
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	return &domain.OAuthToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(payload.ExpiredAt).Round(time.Second).Seconds()),
+		Scopes:      payload.Scopes,
+	}, nil
+}
+
+// isDelegated reports whether the principal acts through an api key or an oauth client,
+// such principals may not grant access to other clients
+func isDelegated(actor *domain.TokenPayload) bool {
+	return actor.APIKeyID != 0 || actor.ClientID != ""
+}
+
+// grantedScopes checks that the client may request the scopes, defaulting to every scope of the client.
+// Tokens of oauth clients always carry scopes, as a principal without scopes would not be restricted
+func grantedScopes(client *domain.OAuthClient, requested []string) ([]string, error) {
+	if len(client.Scopes) == 0 {
+		return nil, domain.ErrInvalidScope
+	}
+
+	if len(requested) == 0 {
+		return client.Scopes, nil
+	}
+
+	for _, scope := range requested {
+		if !util.ScopesAllow(client.Scopes, scope) {
+			return nil, domain.ErrInvalidScope
+		}
+	}
+
+	return requested, nil
+}
+
+// verifyCodeChallenge checks a PKCE code verifier against its S256 code challenge
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < minCodeVerifierLength || len(verifier) > maxCodeVerifierLength {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// redirectWith adds the non-empty parameters to the query of the redirect uri
+func redirectWith(redirectURI string, params map[string]string) (string, error) {
+	link, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", domain.ErrInvalidRedirectURI
+	}
+
+	query := link.Query()
+	for key, value := range params {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
@@ -0,0 +1,560 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	// codeVerifier and codeChallenge are the PKCE example of RFC 7636
+	codeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	codeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	redirectURI   = "https://reports.example.com/callback"
+	clientSecret  = "client-secret"
+)
+
+type oauthMocks struct {
+	clientRepo *mock.MockOAuthClientRepository
+	userRepo   *mock.MockUserRepository
+	ts         *mock.MockTokenService
+	cache      *mock.MockCacheRepository
+}
+
+// newOAuthService creates an oauth service backed by fresh mocks
+func newOAuthService(ctrl *gomock.Controller) (*service.OAuthService, *oauthMocks) {
+	mocks := &oauthMocks{
+		clientRepo: mock.NewMockOAuthClientRepository(ctrl),
+		userRepo:   mock.NewMockUserRepository(ctrl),
+		ts:         mock.NewMockTokenService(ctrl),
+		cache:      mock.NewMockCacheRepository(ctrl),
+	}
+
+	oauthService := service.NewOAuthService(mocks.clientRepo, mocks.userRepo, mocks.ts, mocks.cache)
+
+	return oauthService, mocks
+}
+
+func TestOAuthService_Authorize(t *testing.T) {
+	ctx := context.Background()
+	actor := &domain.TokenPayload{
+		UserID: 1,
+		Role:   domain.Cashier,
+	}
+	client := &domain.OAuthClient{
+		ID:           3,
+		ClientID:     "public-client",
+		RedirectURIs: []string{redirectURI},
+		Scopes:       []string{"users:read", "users:update"},
+	}
+
+	validRequest := func() *domain.OAuthAuthorizationRequest {
+		return &domain.OAuthAuthorizationRequest{
+			ClientID:            client.ClientID,
+			Scopes:              []string{"users:read"},
+			State:               "xyz",
+			CodeChallenge:       codeChallenge,
+			CodeChallengeMethod: domain.CodeChallengeS256,
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		actor    *domain.TokenPayload
+		request  func() *domain.OAuthAuthorizationRequest
+		approved bool
+		mocks    func(m *oauthMocks)
+		query    url.Values
+		expected error
+	}{
+		{
+			desc:     "Success",
+			actor:    actor,
+			request:  validRequest,
+			approved: true,
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+				m.clientRepo.EXPECT().
+					GetOAuthConsent(gomock.Any(), gomock.Eq(actor.UserID), gomock.Eq(client.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				m.clientRepo.EXPECT().
+					SaveOAuthConsent(gomock.Any(), gomock.Eq(&domain.OAuthConsent{
+						UserID:   actor.UserID,
+						ClientID: client.ID,
+						Scopes:   []string{"users:read"},
+					})).
+					Return(&domain.OAuthConsent{}, nil)
+				m.cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(5*time.Minute)).
+					DoAndReturn(func(_ context.Context, _ string, value []byte, _ time.Duration) error {
+						var code domain.OAuthAuthorizationCode
+						assert.NoError(t, util.Deserialize(value, &code), "Code mismatch")
+						assert.Equal(t, actor.UserID, code.UserID, "User mismatch")
+						assert.Equal(t, codeChallenge, code.CodeChallenge, "Code challenge mismatch")
+						return nil
+					})
+			},
+			query:    url.Values{"state": {"xyz"}},
+			expected: nil,
+		},
+		{
+			desc:     "Success_Denied",
+			actor:    actor,
+			request:  validRequest,
+			approved: false,
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+			},
+			query:    url.Values{"error": {"access_denied"}, "state": {"xyz"}},
+			expected: nil,
+		},
+		{
+			desc:     "Fail_DelegatedActor",
+			actor:    &domain.TokenPayload{UserID: 1, ClientID: "other-client"},
+			request:  validRequest,
+			approved: true,
+			mocks:    func(m *oauthMocks) {},
+			expected: domain.ErrForbidden,
+		},
+		{
+			desc:  "Fail_UnknownRedirectURI",
+			actor: actor,
+			request: func() *domain.OAuthAuthorizationRequest {
+				req := validRequest()
+				req.RedirectURI = "https://attacker.example.com/callback"
+				return req
+			},
+			approved: true,
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+			},
+			expected: domain.ErrInvalidRedirectURI,
+		},
+		{
+			desc:  "Fail_PlainCodeChallenge",
+			actor: actor,
+			request: func() *domain.OAuthAuthorizationRequest {
+				req := validRequest()
+				req.CodeChallengeMethod = "plain"
+				return req
+			},
+			approved: true,
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+			},
+			expected: domain.ErrInvalidCodeChallenge,
+		},
+		{
+			desc:  "Fail_ScopeNotAllowed",
+			actor: actor,
+			request: func() *domain.OAuthAuthorizationRequest {
+				req := validRequest()
+				req.Scopes = []string{"users:delete"}
+				return req
+			},
+			approved: true,
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+			},
+			expected: domain.ErrInvalidScope,
+		},
+		{
+			desc:     "Fail_UnknownClient",
+			actor:    actor,
+			request:  validRequest,
+			approved: true,
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: domain.ErrInvalidOAuthClient,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			oauthService, mocks := newOAuthService(ctrl)
+			tc.mocks(mocks)
+
+			redirect, err := oauthService.Authorize(ctx, tc.actor, tc.request(), tc.approved)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+
+			if tc.expected == nil {
+				link, err := url.Parse(redirect)
+				assert.NoError(t, err, "Redirect mismatch")
+				assert.Equal(t, redirectURI, link.Scheme+"://"+link.Host+link.Path, "Redirect uri mismatch")
+
+				query := link.Query()
+				for key := range tc.query {
+					assert.Equal(t, tc.query.Get(key), query.Get(key), "Query mismatch")
+				}
+				if tc.approved {
+					assert.NotEmpty(t, query.Get("code"), "Code mismatch")
+				}
+			}
+		})
+	}
+}
+
+func TestOAuthService_Token(t *testing.T) {
+	ctx := context.Background()
+	code := "authorization-code"
+	codeKey := util.GenerateCacheKey("oauth_code", util.HashToken(code))
+	usedKey := util.GenerateCacheKey("oauth_code_used", util.HashToken(code))
+	user := &domain.User{
+		ID:   1,
+		Role: domain.Cashier,
+	}
+	publicClient := &domain.OAuthClient{
+		ID:           3,
+		ClientID:     "public-client",
+		RedirectURIs: []string{redirectURI},
+		Scopes:       []string{"users:read"},
+	}
+	confidentialClient := &domain.OAuthClient{
+		ID:           4,
+		ClientID:     "confidential-client",
+		SecretHash:   util.HashToken(clientSecret),
+		Scopes:       []string{"users:*"},
+		Confidential: true,
+	}
+	storedCode, _ := util.Serialize(&domain.OAuthAuthorizationCode{
+		UserID:        user.ID,
+		ClientID:      publicClient.ClientID,
+		Scopes:        []string{"users:read"},
+		CodeChallenge: codeChallenge,
+	})
+
+	createToken := func(expected *domain.TokenPayload) func(payload *domain.TokenPayload) (string, error) {
+		return func(payload *domain.TokenPayload) (string, error) {
+			assert.Equal(t, expected, payload, "Payload mismatch")
+			payload.ExpiredAt = time.Now().Add(15 * time.Minute)
+			return "access-token", nil
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		request  *domain.OAuthTokenRequest
+		mocks    func(m *oauthMocks)
+		expected *domain.OAuthToken
+		err      error
+	}{
+		{
+			desc: "Success_AuthorizationCode",
+			request: &domain.OAuthTokenRequest{
+				GrantType:    domain.AuthorizationCodeGrant,
+				ClientID:     publicClient.ClientID,
+				Code:         code,
+				CodeVerifier: codeVerifier,
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(publicClient.ClientID)).
+					Return(publicClient, nil)
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(codeKey)).
+					Return(storedCode, nil)
+				m.cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedKey), gomock.Any()).
+					Return(int64(1), nil)
+				m.cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(codeKey)).
+					Return(nil)
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(util.GenerateCacheKey("token_generation", user.ID))).
+					Return([]byte("2"), nil)
+				m.ts.EXPECT().
+					CreateToken(gomock.Any()).
+					DoAndReturn(createToken(&domain.TokenPayload{
+						UserID:     user.ID,
+						Role:       user.Role,
+						Generation: 2,
+						Scopes:     []string{"users:read"},
+						ClientID:   publicClient.ClientID,
+					}))
+			},
+			expected: &domain.OAuthToken{
+				AccessToken: "access-token",
+				TokenType:   "Bearer",
+				ExpiresIn:   900,
+				Scopes:      []string{"users:read"},
+			},
+			err: nil,
+		},
+		{
+			desc: "Fail_WrongCodeVerifier",
+			request: &domain.OAuthTokenRequest{
+				GrantType:    domain.AuthorizationCodeGrant,
+				ClientID:     publicClient.ClientID,
+				Code:         code,
+				CodeVerifier: "wrong-verifier-wrong-verifier-wrong-verifier",
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(publicClient.ClientID)).
+					Return(publicClient, nil)
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(codeKey)).
+					Return(storedCode, nil)
+				m.cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedKey), gomock.Any()).
+					Return(int64(1), nil)
+				m.cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(codeKey)).
+					Return(nil)
+			},
+			expected: nil,
+			err:      domain.ErrInvalidGrant,
+		},
+		{
+			desc: "Fail_CodeReused",
+			request: &domain.OAuthTokenRequest{
+				GrantType:    domain.AuthorizationCodeGrant,
+				ClientID:     publicClient.ClientID,
+				Code:         code,
+				CodeVerifier: codeVerifier,
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(publicClient.ClientID)).
+					Return(publicClient, nil)
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(codeKey)).
+					Return(storedCode, nil)
+				m.cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedKey), gomock.Any()).
+					Return(int64(2), nil)
+			},
+			expected: nil,
+			err:      domain.ErrInvalidGrant,
+		},
+		{
+			desc: "Fail_CodeOfAnotherClient",
+			request: &domain.OAuthTokenRequest{
+				GrantType:    domain.AuthorizationCodeGrant,
+				ClientID:     confidentialClient.ClientID,
+				ClientSecret: clientSecret,
+				Code:         code,
+				CodeVerifier: codeVerifier,
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(confidentialClient.ClientID)).
+					Return(confidentialClient, nil)
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(codeKey)).
+					Return(storedCode, nil)
+				m.cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedKey), gomock.Any()).
+					Return(int64(1), nil)
+				m.cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(codeKey)).
+					Return(nil)
+			},
+			expected: nil,
+			err:      domain.ErrInvalidGrant,
+		},
+		{
+			desc: "Success_ClientCredentials",
+			request: &domain.OAuthTokenRequest{
+				GrantType:    domain.ClientCredentialsGrant,
+				ClientID:     confidentialClient.ClientID,
+				ClientSecret: clientSecret,
+				Scopes:       []string{"users:read"},
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(confidentialClient.ClientID)).
+					Return(confidentialClient, nil)
+				m.ts.EXPECT().
+					CreateToken(gomock.Any()).
+					DoAndReturn(createToken(&domain.TokenPayload{
+						Subject:  "client:" + confidentialClient.ClientID,
+						Scopes:   []string{"users:read"},
+						ClientID: confidentialClient.ClientID,
+					}))
+			},
+			expected: &domain.OAuthToken{
+				AccessToken: "access-token",
+				TokenType:   "Bearer",
+				ExpiresIn:   900,
+				Scopes:      []string{"users:read"},
+			},
+			err: nil,
+		},
+		{
+			desc: "Fail_ClientCredentialsWrongSecret",
+			request: &domain.OAuthTokenRequest{
+				GrantType:    domain.ClientCredentialsGrant,
+				ClientID:     confidentialClient.ClientID,
+				ClientSecret: "wrong-secret",
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(confidentialClient.ClientID)).
+					Return(confidentialClient, nil)
+			},
+			expected: nil,
+			err:      domain.ErrInvalidOAuthClient,
+		},
+		{
+			desc: "Fail_ClientCredentialsPublicClient",
+			request: &domain.OAuthTokenRequest{
+				GrantType: domain.ClientCredentialsGrant,
+				ClientID:  publicClient.ClientID,
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(publicClient.ClientID)).
+					Return(publicClient, nil)
+			},
+			expected: nil,
+			err:      domain.ErrUnauthorizedOAuthClient,
+		},
+		{
+			desc: "Fail_UnsupportedGrantType",
+			request: &domain.OAuthTokenRequest{
+				GrantType:    "password",
+				ClientID:     confidentialClient.ClientID,
+				ClientSecret: clientSecret,
+			},
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(confidentialClient.ClientID)).
+					Return(confidentialClient, nil)
+			},
+			expected: nil,
+			err:      domain.ErrUnsupportedGrantType,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			oauthService, mocks := newOAuthService(ctrl)
+			tc.mocks(mocks)
+
+			token, err := oauthService.Token(ctx, tc.request)
+			assert.Equal(t, tc.err, err, "Error mismatch")
+			assert.Equal(t, tc.expected, token, "Token mismatch")
+		})
+	}
+}
+
+func TestOAuthService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	token := "access-token"
+	client := &domain.OAuthClient{
+		ID:           4,
+		ClientID:     "confidential-client",
+		SecretHash:   util.HashToken(clientSecret),
+		Scopes:       []string{"users:read"},
+		Confidential: true,
+	}
+	payload := &domain.TokenPayload{
+		ClientID:  client.ClientID,
+		ExpiredAt: time.Now().Add(time.Minute),
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(m *oauthMocks)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+				m.ts.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(payload, nil)
+				m.cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(util.GenerateCacheKey("revoked_token", payload.ID)), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Success_TokenOfAnotherClientIgnored",
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+				m.ts.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(&domain.TokenPayload{ClientID: "other-client", ExpiredAt: payload.ExpiredAt}, nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Success_InvalidTokenIgnored",
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(client, nil)
+				m.ts.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(nil, domain.ErrInvalidToken)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_UnknownClient",
+			mocks: func(m *oauthMocks) {
+				m.clientRepo.EXPECT().
+					GetOAuthClientByClientID(gomock.Any(), gomock.Eq(client.ClientID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: domain.ErrInvalidOAuthClient,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			oauthService, mocks := newOAuthService(ctrl)
+			tc.mocks(mocks)
+
+			err := oauthService.Revoke(ctx, client.ClientID, clientSecret, token)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
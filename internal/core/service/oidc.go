@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+const (
+	// oidcStateSize is the number of random bytes used to generate the state, nonce and PKCE code verifier of a sign in
+	oidcStateSize = 32
+	// oidcStateDuration is how long a user has to sign in at the identity provider
+	oidcStateDuration = 10 * time.Minute
+)
+
+/**
+ * OIDCService implements port.OIDCService interface
+ * and provides an access to the identity provider, user identity repository,
+ * user repository, cache repository, auth service and password hasher.
+ * The provider is nil when single sign-on is not configured
+ */
+type OIDCService struct {
+	provider     port.IdentityProvider
+	identityRepo port.UserIdentityRepository
+	userRepo     port.UserRepository
+	cache        port.CacheRepository
+	auth         port.AuthService
+	hasher       port.PasswordHasher
+}
+
+// NewOIDCService creates a new oidc service instance
+func NewOIDCService(
+	provider port.IdentityProvider,
+	identityRepo port.UserIdentityRepository,
+	userRepo port.UserRepository,
+	cache port.CacheRepository,
+	auth port.AuthService,
+	hasher port.PasswordHasher,
+) *OIDCService {
+	return &OIDCService{
+		provider,
+		identityRepo,
+		userRepo,
+		cache,
+		auth,
+		hasher,
+	}
+}
+
+// Begin starts a sign in with the identity provider and returns the url to send the user to and the state of the sign in.
+// The state, nonce and PKCE code verifier are kept in the cache until the user comes back
+func (oc *OIDCService) Begin(ctx context.Context) (string, string, error) {
+	if oc.provider == nil {
+		return "", "", domain.ErrSingleSignOnDisabled
+	}
+
+	var values [3]string
+	for i := range values {
+		value, err := util.GenerateRandomToken(oidcStateSize)
+		if err != nil {
+			return "", "", domain.ErrInternal
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	value, err := util.Serialize(&domain.OIDCLoginState{
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	})
+	if err != nil {
+		return "", "", domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("oidc_state", util.HashToken(state))
+	err = oc.cache.Set(ctx, cacheKey, value, oidcStateDuration)
+	if err != nil {
+		return "", "", domain.ErrInternal
+	}
+
+	return oc.provider.AuthCodeURL(state, nonce, codeVerifier), state, nil
+}
+
+// Complete exchanges the authorization code the identity provider sent back for the user's identity and logs the user in.
+// Known identities log in as the user they are linked to, new identities are linked to the user with the same email,
+// or to a new user, as long as the identity provider verified the email
+func (oc *OIDCService) Complete(ctx context.Context, state, code string) (*domain.AuthToken, error) {
+	if oc.provider == nil {
+		return nil, domain.ErrSingleSignOnDisabled
+	}
+
+	cacheKey := util.GenerateCacheKey("oidc_state", util.HashToken(state))
+
+	value, err := oc.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return nil, domain.ErrInvalidSingleSignOnState
+	}
+
+	err = oc.cache.Delete(ctx, cacheKey)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	var loginState domain.OIDCLoginState
+	err = util.Deserialize(value, &loginState)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	identity, err := oc.provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, domain.ErrInvalidExternalIdentity
+	}
+
+	user, err := oc.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return oc.auth.LoginExternal(ctx, user)
+}
+
+// resolveUser returns the user an external identity is linked to, linking it first if the identity is new.
+// A link to a user that no longer exists is dropped and the identity is linked again
+func (oc *OIDCService) resolveUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	link, err := oc.identityRepo.GetUserIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, err := oc.userRepo.GetUserByID(ctx, link.UserID)
+		if err == nil {
+			return user, nil
+		}
+		if err != domain.ErrDataNotFound {
+			return nil, domain.ErrInternal
+		}
+
+		err = oc.identityRepo.DeleteUserIdentity(ctx, link.ID)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+	} else if err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, domain.ErrExternalEmailNotVerified
+	}
+
+	user, err := oc.userRepo.GetUserByEmail(ctx, identity.Email)
+	if err != nil {
+		if err != domain.ErrDataNotFound {
+			return nil, domain.ErrInternal
+		}
+
+		user, err = oc.createUser(ctx, identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = oc.identityRepo.CreateUserIdentity(ctx, &domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if user.EmailVerifiedAt == nil {
+		err = oc.userRepo.VerifyUserEmail(ctx, user.ID, user.Email)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+
+		err = oc.cache.Delete(ctx, util.GenerateCacheKey("user", user.ID))
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return user, nil
+}
+
+// createUser creates a cashier for a new external identity,
+// with a random password since the user signs in with the identity provider
+func (oc *OIDCService) createUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	password, err := util.GenerateRandomToken(oidcStateSize)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	hashedPassword, err := oc.hasher.Hash(password)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	user, err := oc.userRepo.CreateUser(ctx, &domain.User{
+		Name:     name,
+		Email:    identity.Email,
+		Password: hashedPassword,
+		Role:     domain.Cashier,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	err = oc.cache.DeleteByPrefix(ctx, "users:*")
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return user, nil
+}
@@ -0,0 +1,352 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type oidcMocks struct {
+	provider     *mock.MockIdentityProvider
+	identityRepo *mock.MockUserIdentityRepository
+	userRepo     *mock.MockUserRepository
+	cache        *mock.MockCacheRepository
+	auth         *mock.MockAuthService
+	hasher       *mock.MockPasswordHasher
+}
+
+// newOIDCService creates an oidc service backed by fresh mocks
+func newOIDCService(ctrl *gomock.Controller) (*service.OIDCService, *oidcMocks) {
+	mocks := &oidcMocks{
+		provider:     mock.NewMockIdentityProvider(ctrl),
+		identityRepo: mock.NewMockUserIdentityRepository(ctrl),
+		userRepo:     mock.NewMockUserRepository(ctrl),
+		cache:        mock.NewMockCacheRepository(ctrl),
+		auth:         mock.NewMockAuthService(ctrl),
+		hasher:       mock.NewMockPasswordHasher(ctrl),
+	}
+
+	oidcService := service.NewOIDCService(
+		mocks.provider,
+		mocks.identityRepo,
+		mocks.userRepo,
+		mocks.cache,
+		mocks.auth,
+		mocks.hasher,
+	)
+
+	return oidcService, mocks
+}
+
+func TestOIDCService_Begin(t *testing.T) {
+	ctx := context.Background()
+	authorizationURL := "https://login.example.com/authorize?state=state"
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		oidcService, mocks := newOIDCService(ctrl)
+
+		var cacheKey, authorizationState string
+		var loginState domain.OIDCLoginState
+		mocks.cache.EXPECT().
+			Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(10*time.Minute)).
+			DoAndReturn(func(_ context.Context, key string, value []byte, _ time.Duration) error {
+				cacheKey = key
+				return util.Deserialize(value, &loginState)
+			})
+		mocks.provider.EXPECT().
+			AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(state, nonce, codeVerifier string) string {
+				assert.Equal(t, util.GenerateCacheKey("oidc_state", util.HashToken(state)), cacheKey, "State mismatch")
+				assert.Equal(t, loginState.Nonce, nonce, "Nonce mismatch")
+				assert.Equal(t, loginState.CodeVerifier, codeVerifier, "Code verifier mismatch")
+				assert.NotEqual(t, nonce, codeVerifier, "Nonce reused as code verifier")
+				authorizationState = state
+				return authorizationURL
+			})
+
+		url, state, err := oidcService.Begin(ctx)
+		assert.NoError(t, err, "Error mismatch")
+		assert.Equal(t, authorizationURL, url, "URL mismatch")
+		assert.Equal(t, authorizationState, state, "State mismatch")
+	})
+
+	t.Run("Fail_Disabled", func(t *testing.T) {
+		t.Parallel()
+		oidcService := service.NewOIDCService(nil, nil, nil, nil, nil, nil)
+
+		_, _, err := oidcService.Begin(ctx)
+		assert.Equal(t, domain.ErrSingleSignOnDisabled, err, "Error mismatch")
+	})
+}
+
+func TestOIDCService_Complete(t *testing.T) {
+	ctx := context.Background()
+	state := "state"
+	code := "code"
+	stateKey := util.GenerateCacheKey("oidc_state", util.HashToken(state))
+	loginState := &domain.OIDCLoginState{
+		Nonce:        "nonce",
+		CodeVerifier: "code-verifier",
+	}
+	stateValue, err := util.Serialize(loginState)
+	assert.NoError(t, err)
+
+	verifiedAt := time.Now()
+	identity := &domain.ExternalIdentity{
+		Provider:      "corporate",
+		Subject:       "248289761001",
+		Email:         "test@example.com",
+		EmailVerified: true,
+		Name:          "John Doe",
+	}
+	unverifiedIdentity := &domain.ExternalIdentity{
+		Provider: "corporate",
+		Subject:  "248289761001",
+		Email:    "test@example.com",
+	}
+	user := &domain.User{
+		ID:              1,
+		Name:            "John Doe",
+		Email:           "test@example.com",
+		Role:            domain.Cashier,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	authToken := &domain.AuthToken{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+	}
+
+	// validState expects the login state to be read and consumed
+	validState := func(m *oidcMocks) {
+		m.cache.EXPECT().
+			Get(gomock.Any(), gomock.Eq(stateKey)).
+			Return(stateValue, nil)
+		m.cache.EXPECT().
+			Delete(gomock.Any(), gomock.Eq(stateKey)).
+			Return(nil)
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(m *oidcMocks)
+		output   *domain.AuthToken
+		expected error
+	}{
+		{
+			desc: "Success_LinkedIdentity",
+			mocks: func(m *oidcMocks) {
+				validState(m)
+				m.provider.EXPECT().
+					Exchange(gomock.Any(), gomock.Eq(code), gomock.Eq(loginState.CodeVerifier), gomock.Eq(loginState.Nonce)).
+					Return(unverifiedIdentity, nil)
+				m.identityRepo.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(identity.Provider), gomock.Eq(identity.Subject)).
+					Return(&domain.UserIdentity{UserID: user.ID}, nil)
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				m.auth.EXPECT().
+					LoginExternal(gomock.Any(), gomock.Eq(user)).
+					Return(authToken, nil)
+			},
+			output:   authToken,
+			expected: nil,
+		},
+		{
+			desc: "Success_LinkByVerifiedEmail",
+			mocks: func(m *oidcMocks) {
+				unverifiedUser := &domain.User{
+					ID:    user.ID,
+					Name:  user.Name,
+					Email: user.Email,
+					Role:  user.Role,
+				}
+
+				validState(m)
+				m.provider.EXPECT().
+					Exchange(gomock.Any(), gomock.Eq(code), gomock.Eq(loginState.CodeVerifier), gomock.Eq(loginState.Nonce)).
+					Return(identity, nil)
+				m.identityRepo.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(identity.Provider), gomock.Eq(identity.Subject)).
+					Return(nil, domain.ErrDataNotFound)
+				m.userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(identity.Email)).
+					Return(unverifiedUser, nil)
+				m.identityRepo.EXPECT().
+					CreateUserIdentity(gomock.Any(), gomock.Eq(&domain.UserIdentity{
+						UserID:   user.ID,
+						Provider: identity.Provider,
+						Subject:  identity.Subject,
+						Email:    identity.Email,
+					})).
+					Return(&domain.UserIdentity{}, nil)
+				m.userRepo.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(user.Email)).
+					Return(nil)
+				m.cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(util.GenerateCacheKey("user", user.ID))).
+					Return(nil)
+				m.auth.EXPECT().
+					LoginExternal(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, user *domain.User) (*domain.AuthToken, error) {
+						assert.NotNil(t, user.EmailVerifiedAt, "Email not verified")
+						return authToken, nil
+					})
+			},
+			output:   authToken,
+			expected: nil,
+		},
+		{
+			desc: "Success_CreateUser",
+			mocks: func(m *oidcMocks) {
+				validState(m)
+				m.provider.EXPECT().
+					Exchange(gomock.Any(), gomock.Eq(code), gomock.Eq(loginState.CodeVerifier), gomock.Eq(loginState.Nonce)).
+					Return(identity, nil)
+				m.identityRepo.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(identity.Provider), gomock.Eq(identity.Subject)).
+					Return(nil, domain.ErrDataNotFound)
+				m.userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(identity.Email)).
+					Return(nil, domain.ErrDataNotFound)
+				m.hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return("hashed-password", nil)
+				m.userRepo.EXPECT().
+					CreateUser(gomock.Any(), gomock.Eq(&domain.User{
+						Name:     identity.Name,
+						Email:    identity.Email,
+						Password: "hashed-password",
+						Role:     domain.Cashier,
+					})).
+					Return(user, nil)
+				m.cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				m.identityRepo.EXPECT().
+					CreateUserIdentity(gomock.Any(), gomock.Any()).
+					Return(&domain.UserIdentity{}, nil)
+				m.auth.EXPECT().
+					LoginExternal(gomock.Any(), gomock.Eq(user)).
+					Return(authToken, nil)
+			},
+			output:   authToken,
+			expected: nil,
+		},
+		{
+			desc: "Success_StaleLink",
+			mocks: func(m *oidcMocks) {
+				staleLink := &domain.UserIdentity{
+					ID:     3,
+					UserID: 9,
+				}
+
+				validState(m)
+				m.provider.EXPECT().
+					Exchange(gomock.Any(), gomock.Eq(code), gomock.Eq(loginState.CodeVerifier), gomock.Eq(loginState.Nonce)).
+					Return(identity, nil)
+				m.identityRepo.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(identity.Provider), gomock.Eq(identity.Subject)).
+					Return(staleLink, nil)
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(staleLink.UserID)).
+					Return(nil, domain.ErrDataNotFound)
+				m.identityRepo.EXPECT().
+					DeleteUserIdentity(gomock.Any(), gomock.Eq(staleLink.ID)).
+					Return(nil)
+				m.userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(identity.Email)).
+					Return(user, nil)
+				m.identityRepo.EXPECT().
+					CreateUserIdentity(gomock.Any(), gomock.Eq(&domain.UserIdentity{
+						UserID:   user.ID,
+						Provider: identity.Provider,
+						Subject:  identity.Subject,
+						Email:    identity.Email,
+					})).
+					Return(&domain.UserIdentity{}, nil)
+				m.auth.EXPECT().
+					LoginExternal(gomock.Any(), gomock.Eq(user)).
+					Return(authToken, nil)
+			},
+			output:   authToken,
+			expected: nil,
+		},
+		{
+			desc: "Fail_InvalidState",
+			mocks: func(m *oidcMocks) {
+				m.cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(stateKey)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			output:   nil,
+			expected: domain.ErrInvalidSingleSignOnState,
+		},
+		{
+			desc: "Fail_ExchangeFailed",
+			mocks: func(m *oidcMocks) {
+				validState(m)
+				m.provider.EXPECT().
+					Exchange(gomock.Any(), gomock.Eq(code), gomock.Eq(loginState.CodeVerifier), gomock.Eq(loginState.Nonce)).
+					Return(nil, domain.ErrInvalidToken)
+			},
+			output:   nil,
+			expected: domain.ErrInvalidExternalIdentity,
+		},
+		{
+			desc: "Fail_EmailNotVerified",
+			mocks: func(m *oidcMocks) {
+				validState(m)
+				m.provider.EXPECT().
+					Exchange(gomock.Any(), gomock.Eq(code), gomock.Eq(loginState.CodeVerifier), gomock.Eq(loginState.Nonce)).
+					Return(unverifiedIdentity, nil)
+				m.identityRepo.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(identity.Provider), gomock.Eq(identity.Subject)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			output:   nil,
+			expected: domain.ErrExternalEmailNotVerified,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(m *oidcMocks) {
+				validState(m)
+				m.provider.EXPECT().
+					Exchange(gomock.Any(), gomock.Eq(code), gomock.Eq(loginState.CodeVerifier), gomock.Eq(loginState.Nonce)).
+					Return(identity, nil)
+				m.identityRepo.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(identity.Provider), gomock.Eq(identity.Subject)).
+					Return(nil, domain.ErrInternal)
+			},
+			output:   nil,
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			oidcService, mocks := newOIDCService(ctrl)
+			tc.mocks(mocks)
+
+			token, err := oidcService.Complete(ctx, state, code)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			assert.Equal(t, tc.output, token, "Token mismatch")
+		})
+	}
+}
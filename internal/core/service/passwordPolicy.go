@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+)
+
+// minPersonalInfoLength is the shortest part of a name or email that a password may not contain,
+// shorter parts are too common to reject
+const minPersonalInfoLength = 3
+
+/**
+ * PasswordPolicyService implements port.PasswordPolicyService interface
+ * and checks passwords against the configured password policy.
+ * Breached passwords are looked up by their SHA-1 hash in the breached password repository
+ */
+type PasswordPolicyService struct {
+	breached port.BreachedPasswordRepository
+	policy   domain.PasswordPolicy
+}
+
+// NewPasswordPolicyService creates a new password policy service instance
+func NewPasswordPolicyService(breached port.BreachedPasswordRepository, policy domain.PasswordPolicy) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		breached,
+		policy,
+	}
+}
+
+// Validate returns a *domain.PasswordPolicyError listing every rule the password breaks,
+// the user is the owner of the password and may be nil when it is not known
+func (ps *PasswordPolicyService) Validate(ctx context.Context, password string, user *domain.User) error {
+	var violations []string
+
+	length := utf8.RuneCountInString(password)
+	if length < ps.policy.MinLength {
+		violations = append(violations, fmt.Sprintf("password must be at least %d characters long", ps.policy.MinLength))
+	}
+	if ps.policy.MaxLength > 0 && length > ps.policy.MaxLength {
+		violations = append(violations, fmt.Sprintf("password must be at most %d characters long", ps.policy.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if ps.policy.RequireUpper && !hasUpper {
+		violations = append(violations, "password must contain an uppercase letter")
+	}
+	if ps.policy.RequireLower && !hasLower {
+		violations = append(violations, "password must contain a lowercase letter")
+	}
+	if ps.policy.RequireDigit && !hasDigit {
+		violations = append(violations, "password must contain a digit")
+	}
+	if ps.policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, "password must contain a symbol")
+	}
+
+	if ps.policy.DenyPersonalInfo && user != nil {
+		lowerPassword := strings.ToLower(password)
+
+		if containsAny(lowerPassword, strings.Fields(user.Name)) {
+			violations = append(violations, "password must not contain your name")
+		}
+
+		localPart, _, _ := strings.Cut(user.Email, "@")
+		if containsAny(lowerPassword, []string{user.Email, localPart}) {
+			violations = append(violations, "password must not contain your email address")
+		}
+	}
+
+	if ps.policy.DenyBreached {
+		hash := sha1.Sum([]byte(password))
+
+		breached, err := ps.breached.ContainsHash(ctx, strings.ToUpper(hex.EncodeToString(hash[:])))
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		if breached {
+			violations = append(violations, "password has appeared in a data breach, choose another one")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &domain.PasswordPolicyError{
+			Violations: violations,
+		}
+	}
+
+	return nil
+}
+
+// containsAny reports whether the lowercase password contains one of the parts long enough to be checked
+func containsAny(password string, parts []string) bool {
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) >= minPersonalInfoLength && strings.Contains(password, strings.ToLower(part)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPasswordPolicyService_Validate(t *testing.T) {
+	ctx := context.Background()
+	policy := domain.PasswordPolicy{
+		MinLength:        10,
+		MaxLength:        72,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DenyPersonalInfo: true,
+		DenyBreached:     true,
+	}
+	user := &domain.User{
+		Name:  "John Doe",
+		Email: "jdoe99@example.com",
+	}
+
+	testCases := []struct {
+		desc     string
+		password string
+		mocks    func(breached *mock.MockBreachedPasswordRepository)
+		expected error
+	}{
+		{
+			desc:     "Success",
+			password: "Correct4Horse!Battery",
+			mocks: func(breached *mock.MockBreachedPasswordRepository) {
+				breached.EXPECT().
+					ContainsHash(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+			},
+			expected: nil,
+		},
+		{
+			desc:     "Fail_EveryCharacterRule",
+			password: "short",
+			mocks: func(breached *mock.MockBreachedPasswordRepository) {
+				breached.EXPECT().
+					ContainsHash(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+			},
+			expected: &domain.PasswordPolicyError{
+				Violations: []string{
+					"password must be at least 10 characters long",
+					"password must contain an uppercase letter",
+					"password must contain a digit",
+					"password must contain a symbol",
+				},
+			},
+		},
+		{
+			desc:     "Fail_PersonalInfo",
+			password: "Jdoe99-Johnny!",
+			mocks: func(breached *mock.MockBreachedPasswordRepository) {
+				breached.EXPECT().
+					ContainsHash(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+			},
+			expected: &domain.PasswordPolicyError{
+				Violations: []string{
+					"password must not contain your name",
+					"password must not contain your email address",
+				},
+			},
+		},
+		{
+			desc:     "Fail_Breached",
+			password: "P@ssw0rd1234",
+			mocks: func(breached *mock.MockBreachedPasswordRepository) {
+				breached.EXPECT().
+					ContainsHash(gomock.Any(), gomock.Eq("116A4DA0477B36B603C9382E8A14ED1679DD211D")).
+					Return(true, nil)
+			},
+			expected: &domain.PasswordPolicyError{
+				Violations: []string{
+					"password has appeared in a data breach, choose another one",
+				},
+			},
+		},
+		{
+			desc:     "Fail_BreachLookup",
+			password: "Correct4Horse!Battery",
+			mocks: func(breached *mock.MockBreachedPasswordRepository) {
+				breached.EXPECT().
+					ContainsHash(gomock.Any(), gomock.Any()).
+					Return(false, domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			breached := mock.NewMockBreachedPasswordRepository(ctrl)
+			tc.mocks(breached)
+
+			passwordPolicyService := service.NewPasswordPolicyService(breached, policy)
+
+			err := passwordPolicyService.Validate(ctx, tc.password, user)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+// passwordResetTokenSize is the number of random bytes used to generate a password reset token
+const passwordResetTokenSize = 32
+
+/**
+ * PasswordResetService implements port.PasswordResetService interface
+ * and provides an access to the user repository, password reset repository,
+ * cache repository, mailer, auth service, password policy and password hasher.
+ * Only the hash of reset tokens is stored, the token itself is only sent by email
+ */
+type PasswordResetService struct {
+	repo      port.UserRepository
+	resetRepo port.PasswordResetRepository
+	cache     port.CacheRepository
+	mailer    port.Mailer
+	auth      port.AuthService
+	policy    port.PasswordPolicyService
+	hasher    port.PasswordHasher
+	url       string
+	duration  time.Duration
+}
+
+// NewPasswordResetService creates a new password reset service instance,
+// links point to the given url with the token in the token query parameter
+func NewPasswordResetService(
+	repo port.UserRepository,
+	resetRepo port.PasswordResetRepository,
+	cache port.CacheRepository,
+	mailer port.Mailer,
+	auth port.AuthService,
+	policy port.PasswordPolicyService,
+	hasher port.PasswordHasher,
+	url string,
+	duration time.Duration,
+) *PasswordResetService {
+	return &PasswordResetService{
+		repo,
+		resetRepo,
+		cache,
+		mailer,
+		auth,
+		policy,
+		hasher,
+		url,
+		duration,
+	}
+}
+
+// RequestReset emails a single-use password reset link if the email belongs to a user.
+// Unknown emails and delivery failures are not reported, and the link is sent in the background,
+// so that neither the response nor its timing reveals whether the email is registered
+func (ps *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := ps.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	go ps.deliverReset(context.WithoutCancel(ctx), user)
+
+	return nil
+}
+
+// CompleteReset sets a new password with a valid reset token, then revokes every token and session of the user.
+// The token is only used up once the new password meets the password policy
+func (ps *PasswordResetService) CompleteReset(ctx context.Context, token, password string) error {
+	resetToken, err := ps.resetRepo.GetPasswordResetTokenByHash(ctx, util.HashToken(token))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidPasswordResetToken
+		}
+		return domain.ErrInternal
+	}
+
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return domain.ErrInvalidPasswordResetToken
+	}
+
+	user, err := ps.repo.GetUserByID(ctx, resetToken.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidPasswordResetToken
+		}
+		return domain.ErrInternal
+	}
+
+	err = ps.policy.Validate(ctx, password, user)
+	if err != nil {
+		return err
+	}
+
+	err = ps.resetRepo.MarkPasswordResetTokenUsed(ctx, resetToken.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidPasswordResetToken
+		}
+		return domain.ErrInternal
+	}
+
+	hashedPassword, err := ps.hasher.Hash(password)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err = ps.repo.UpdateUser(ctx, &domain.User{
+		ID:       resetToken.UserID,
+		Password: hashedPassword,
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = ps.resetRepo.InvalidateUserPasswordResetTokens(ctx, resetToken.UserID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = ps.cache.Delete(ctx, util.GenerateCacheKey("user", resetToken.UserID))
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return ps.auth.LogoutAll(ctx, resetToken.UserID)
+}
+
+// deliverReset sends a password reset to the user outside of the request, a failure is only logged
+func (ps *PasswordResetService) deliverReset(ctx context.Context, user *domain.User) {
+	err := ps.sendReset(ctx, user)
+	if err != nil {
+		slog.Error("Error sending the password reset", "user_id", user.ID, "error", err)
+	}
+}
+
+// sendReset stores a new reset token for the user and emails the link carrying it
+func (ps *PasswordResetService) sendReset(ctx context.Context, user *domain.User) error {
+	token, err := util.GenerateRandomToken(passwordResetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(ps.duration)
+
+	_, err = ps.resetRepo.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(ps.url)
+	if err != nil {
+		return err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return ps.mailer.Send(ctx, &domain.Email{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your account. Open the link below to choose a new one:\n\n%s\n\nThe link can be used once and is valid until %s. If you did not ask for it, you can ignore this email.\n",
+			user.Name,
+			link.String(),
+			expiresAt.UTC().Format(time.RFC1123),
+		),
+	})
+}
@@ -0,0 +1,316 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const resetURL = "http://localhost:5173/reset-password"
+
+type passwordResetMocks struct {
+	userRepo  *mock.MockUserRepository
+	resetRepo *mock.MockPasswordResetRepository
+	cache     *mock.MockCacheRepository
+	mailer    *mock.MockMailer
+	auth      *mock.MockAuthService
+	policy    *mock.MockPasswordPolicyService
+	hasher    *mock.MockPasswordHasher
+	// delivered is closed by the mocks once the reset sent in the background is done
+	delivered chan struct{}
+}
+
+// newPasswordResetService creates a password reset service backed by fresh mocks
+func newPasswordResetService(ctrl *gomock.Controller) (*service.PasswordResetService, *passwordResetMocks) {
+	mocks := &passwordResetMocks{
+		userRepo:  mock.NewMockUserRepository(ctrl),
+		resetRepo: mock.NewMockPasswordResetRepository(ctrl),
+		cache:     mock.NewMockCacheRepository(ctrl),
+		mailer:    mock.NewMockMailer(ctrl),
+		auth:      mock.NewMockAuthService(ctrl),
+		policy:    mock.NewMockPasswordPolicyService(ctrl),
+		hasher:    mock.NewMockPasswordHasher(ctrl),
+		delivered: make(chan struct{}),
+	}
+
+	passwordResetService := service.NewPasswordResetService(
+		mocks.userRepo,
+		mocks.resetRepo,
+		mocks.cache,
+		mocks.mailer,
+		mocks.auth,
+		mocks.policy,
+		mocks.hasher,
+		resetURL,
+		time.Hour,
+	)
+
+	return passwordResetService, mocks
+}
+
+// waitDelivered waits for an email sent in the background
+func waitDelivered(t *testing.T, delivered <-chan struct{}) {
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("Email not delivered")
+	}
+}
+
+func TestPasswordResetService_RequestReset(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{
+		ID:    1,
+		Name:  "John Doe",
+		Email: "test@example.com",
+	}
+
+	testCases := []struct {
+		desc      string
+		email     string
+		mocks     func(m *passwordResetMocks)
+		delivered bool
+		expected  error
+	}{
+		{
+			desc:  "Success",
+			email: user.Email,
+			mocks: func(m *passwordResetMocks) {
+				m.userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(user, nil)
+				m.resetRepo.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+						assert.Equal(t, user.ID, token.UserID, "User mismatch")
+						assert.Len(t, token.TokenHash, 64, "Token hash mismatch")
+						return token, nil
+					})
+				m.mailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, email *domain.Email) error {
+						assert.Equal(t, user.Email, email.To, "Recipient mismatch")
+						assert.Contains(t, email.Body, resetURL+"?token=", "Link mismatch")
+						close(m.delivered)
+						return nil
+					})
+			},
+			delivered: true,
+			expected:  nil,
+		},
+		{
+			desc:  "Success_UnknownEmail",
+			email: "unknown@example.com",
+			mocks: func(m *passwordResetMocks) {
+				m.userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq("unknown@example.com")).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: nil,
+		},
+		{
+			desc:  "Success_DeliveryFailureHidden",
+			email: user.Email,
+			mocks: func(m *passwordResetMocks) {
+				m.userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(user, nil)
+				m.resetRepo.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					Return(&domain.PasswordResetToken{}, nil)
+				m.mailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *domain.Email) error {
+						close(m.delivered)
+						return domain.ErrInternal
+					})
+			},
+			delivered: true,
+			expected:  nil,
+		},
+		{
+			desc:  "Fail_InternalError",
+			email: user.Email,
+			mocks: func(m *passwordResetMocks) {
+				m.userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(nil, domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			passwordResetService, mocks := newPasswordResetService(ctrl)
+			tc.mocks(mocks)
+
+			err := passwordResetService.RequestReset(ctx, tc.email)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+
+			if tc.delivered {
+				waitDelivered(t, mocks.delivered)
+			}
+		})
+	}
+}
+
+func TestPasswordResetService_CompleteReset(t *testing.T) {
+	ctx := context.Background()
+	token := "reset-token"
+	password := "new password"
+	hashedPassword := "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5"
+	usedAt := time.Now()
+	user := &domain.User{
+		ID:    1,
+		Name:  "John Doe",
+		Email: "test@example.com",
+	}
+	weakPasswordErr := &domain.PasswordPolicyError{
+		Violations: []string{"password must contain an uppercase letter"},
+	}
+
+	validToken := &domain.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	usedToken := &domain.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+	expiredToken := &domain.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(m *passwordResetMocks)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(m *passwordResetMocks) {
+				m.resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(util.HashToken(token))).
+					Return(validToken, nil)
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(validToken.UserID)).
+					Return(user, nil)
+				m.policy.EXPECT().
+					Validate(gomock.Any(), gomock.Eq(password), gomock.Eq(user)).
+					Return(nil)
+				m.resetRepo.EXPECT().
+					MarkPasswordResetTokenUsed(gomock.Any(), gomock.Eq(validToken.ID)).
+					Return(nil)
+				m.hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return(hashedPassword, nil)
+				m.userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(&domain.User{ID: validToken.UserID, Password: hashedPassword})).
+					Return(user, nil)
+				m.resetRepo.EXPECT().
+					InvalidateUserPasswordResetTokens(gomock.Any(), gomock.Eq(validToken.UserID)).
+					Return(nil)
+				m.cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(util.GenerateCacheKey("user", validToken.UserID))).
+					Return(nil)
+				m.auth.EXPECT().
+					LogoutAll(gomock.Any(), gomock.Eq(validToken.UserID)).
+					Return(nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_WeakPassword",
+			mocks: func(m *passwordResetMocks) {
+				m.resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(util.HashToken(token))).
+					Return(validToken, nil)
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(validToken.UserID)).
+					Return(user, nil)
+				m.policy.EXPECT().
+					Validate(gomock.Any(), gomock.Eq(password), gomock.Eq(user)).
+					Return(weakPasswordErr)
+			},
+			expected: weakPasswordErr,
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(m *passwordResetMocks) {
+				m.resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(util.HashToken(token))).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: domain.ErrInvalidPasswordResetToken,
+		},
+		{
+			desc: "Fail_Used",
+			mocks: func(m *passwordResetMocks) {
+				m.resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(util.HashToken(token))).
+					Return(usedToken, nil)
+			},
+			expected: domain.ErrInvalidPasswordResetToken,
+		},
+		{
+			desc: "Fail_Expired",
+			mocks: func(m *passwordResetMocks) {
+				m.resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(util.HashToken(token))).
+					Return(expiredToken, nil)
+			},
+			expected: domain.ErrInvalidPasswordResetToken,
+		},
+		{
+			desc: "Fail_ConcurrentUse",
+			mocks: func(m *passwordResetMocks) {
+				m.resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(util.HashToken(token))).
+					Return(validToken, nil)
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(validToken.UserID)).
+					Return(user, nil)
+				m.policy.EXPECT().
+					Validate(gomock.Any(), gomock.Eq(password), gomock.Eq(user)).
+					Return(nil)
+				m.resetRepo.EXPECT().
+					MarkPasswordResetTokenUsed(gomock.Any(), gomock.Eq(validToken.ID)).
+					Return(domain.ErrDataNotFound)
+			},
+			expected: domain.ErrInvalidPasswordResetToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			passwordResetService, mocks := newPasswordResetService(ctrl)
+			tc.mocks(mocks)
+
+			err := passwordResetService.CompleteReset(ctx, token, password)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
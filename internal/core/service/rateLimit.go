@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+/**
+ * RateLimitService implements port.RateLimiter interface
+ * and counts requests in the cache repository with a sliding window:
+ * the count of the previous fixed window is weighted by how much
+ * of it still overlaps the sliding window ending now
+ */
+type RateLimitService struct {
+	cache port.CacheRepository
+}
+
+// NewRateLimitService creates a new rate limit service instance
+func NewRateLimitService(cache port.CacheRepository) *RateLimitService {
+	return &RateLimitService{
+		cache,
+	}
+}
+
+// Allow counts a request of the client identified by the key and decides whether it is within the limit.
+// Rejected requests are counted too, so that clients ignoring the limit stay limited
+func (rs *RateLimitService) Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitResult, error) {
+	now := time.Now()
+	windowStart := now.Truncate(limit.Window)
+	elapsed := now.Sub(windowStart)
+
+	currentKey := rateLimitKey(limit.Name, key, windowStart)
+	previousKey := rateLimitKey(limit.Name, key, windowStart.Add(-limit.Window))
+
+	current, err := rs.cache.Increment(ctx, currentKey, 2*limit.Window)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	var previous int64
+	value, err := rs.cache.Get(ctx, previousKey)
+	if err == nil {
+		previous, _ = strconv.ParseInt(string(value), 10, 64)
+	}
+
+	weight := 1 - float64(elapsed)/float64(limit.Window)
+	estimated := int64(math.Ceil(float64(previous)*weight)) + current
+
+	result := &domain.RateLimitResult{
+		Allowed:   estimated <= limit.Requests,
+		Limit:     limit.Requests,
+		Remaining: max(limit.Requests-estimated, 0),
+		Reset:     limit.Window - elapsed,
+	}
+
+	if !result.Allowed {
+		result.RetryAfter = retryAfter(previous, current, limit, elapsed)
+	}
+
+	return result, nil
+}
+
+// retryAfter estimates how long the client has to wait until its next request is allowed
+func retryAfter(previous, current int64, limit domain.RateLimit, elapsed time.Duration) time.Duration {
+	window := float64(limit.Window)
+
+	// wait until the previous window weighs little enough
+	if current < limit.Requests && previous > 0 {
+		overlap := float64(limit.Requests-current) / float64(previous)
+		wait := time.Duration((1-overlap)*window) - elapsed
+		return max(wait, time.Second)
+	}
+
+	// wait until the current window, once it becomes the previous one, weighs little enough
+	overlap := float64(limit.Requests) / float64(current+1)
+	wait := limit.Window - elapsed + time.Duration((1-overlap)*window)
+	return max(wait, time.Second)
+}
+
+// rateLimitKey generates the cache key counting the requests of a client in a fixed window
+func rateLimitKey(name, key string, windowStart time.Time) string {
+	return util.GenerateCacheKey("rate_limit", fmt.Sprintf("%s:%s:%d", name, key, windowStart.Unix()))
+}
@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type allowExpectedOutput struct {
+	allowed   bool
+	remaining int64
+	err       error
+}
+
+func TestRateLimitService_Allow(t *testing.T) {
+	ctx := context.Background()
+	key := "ip:10.0.0.1"
+	// a day long window keeps the expectations independent of when the tests run
+	limit := domain.RateLimit{
+		Name:     "public",
+		Requests: 10,
+		Window:   24 * time.Hour,
+	}
+	elapsed := float64(time.Since(time.Now().Truncate(limit.Window))) / float64(limit.Window)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(cache *mock.MockCacheRepository)
+		expected allowExpectedOutput
+	}{
+		{
+			desc: "Success_FirstRequest",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Any(), gomock.Eq(2*limit.Window)).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: allowExpectedOutput{
+				allowed:   true,
+				remaining: 9,
+			},
+		},
+		{
+			desc: "Success_LastRequest",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Any(), gomock.Eq(2*limit.Window)).
+					Return(int64(10), nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Any()).
+					Return([]byte("0"), nil)
+			},
+			expected: allowExpectedOutput{
+				allowed:   true,
+				remaining: 0,
+			},
+		},
+		{
+			desc: "Fail_CurrentWindowExceeded",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Any(), gomock.Eq(2*limit.Window)).
+					Return(int64(11), nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: allowExpectedOutput{
+				allowed:   false,
+				remaining: 0,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Any(), gomock.Eq(2*limit.Window)).
+					Return(int64(0), domain.ErrInternal)
+			},
+			expected: allowExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(cache)
+
+			rateLimitService := service.NewRateLimitService(cache)
+
+			result, err := rateLimitService.Allow(ctx, key, limit)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, tc.expected.allowed, result.Allowed, "Allowed mismatch")
+			assert.Equal(t, tc.expected.remaining, result.Remaining, "Remaining mismatch")
+			assert.Equal(t, limit.Requests, result.Limit, "Limit mismatch")
+			if !result.Allowed {
+				assert.Greater(t, result.RetryAfter, time.Duration(0), "Retry after mismatch")
+			}
+		})
+	}
+
+	t.Run("Fail_PreviousWindowExceeded", func(t *testing.T) {
+		if elapsed > 0.5 {
+			t.Skip("the previous window weighs too little this late in the window")
+		}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cache := mock.NewMockCacheRepository(ctrl)
+		cache.EXPECT().
+			Increment(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(int64(1), nil)
+		cache.EXPECT().
+			Get(gomock.Any(), gomock.Any()).
+			Return([]byte("100"), nil)
+
+		rateLimitService := service.NewRateLimitService(cache)
+
+		result, err := rateLimitService.Allow(ctx, key, limit)
+		assert.NoError(t, err, "Error mismatch")
+		assert.False(t, result.Allowed, "Allowed mismatch")
+		assert.Greater(t, result.RetryAfter, time.Duration(0), "Retry after mismatch")
+	})
+}
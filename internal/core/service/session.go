@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/google/uuid"
+)
+
+/**
+ * SessionService implements port.SessionService interface
+ * and provides an access to the session repository, refresh token repository and cache repository.
+ * A session is a refresh token family, started by a login and kept alive by refreshing
+ */
+type SessionService struct {
+	repo        port.SessionRepository
+	refreshRepo port.RefreshTokenRepository
+	cache       port.CacheRepository
+}
+
+// NewSessionService creates a new session service instance
+func NewSessionService(repo port.SessionRepository, refreshRepo port.RefreshTokenRepository, cache port.CacheRepository) *SessionService {
+	return &SessionService{
+		repo,
+		refreshRepo,
+		cache,
+	}
+}
+
+// ListSessions lists the active sessions of a user, most recently seen first
+func (ss *SessionService) ListSessions(ctx context.Context, userID uint64) ([]domain.Session, error) {
+	sessions, err := ss.repo.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes an active session of a user, its refresh tokens can no longer be used
+// and its access tokens are rejected until the session would have expired
+func (ss *SessionService) RevokeSession(ctx context.Context, userID uint64, id uuid.UUID) error {
+	session, err := ss.repo.GetSession(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if session.UserID != userID || session.RevokedAt != nil {
+		return domain.ErrDataNotFound
+	}
+
+	err = ss.refreshRepo.RevokeRefreshTokenFamily(ctx, session.ID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = ss.repo.RevokeSession(ctx, session.ID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	ttl := time.Until(session.ExpiresAt)
+	if ttl > 0 {
+		cacheKey := util.GenerateCacheKey("revoked_session", session.ID)
+
+		err = ss.cache.Set(ctx, cacheKey, []byte("1"), ttl)
+		if err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
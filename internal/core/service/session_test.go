@@ -0,0 +1,183 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionService_ListSessions(t *testing.T) {
+	ctx := context.Background()
+	userID := uint64(1)
+	sessions := []domain.Session{
+		{
+			ID:        uuid.New(),
+			UserID:    userID,
+			IP:        "203.0.113.7",
+			UserAgent: "Mozilla/5.0",
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(sessionRepo *mock.MockSessionRepository)
+		output   []domain.Session
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(sessionRepo *mock.MockSessionRepository) {
+				sessionRepo.EXPECT().
+					ListUserSessions(gomock.Any(), gomock.Eq(userID)).
+					Return(sessions, nil)
+			},
+			output:   sessions,
+			expected: nil,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(sessionRepo *mock.MockSessionRepository) {
+				sessionRepo.EXPECT().
+					ListUserSessions(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrInternal)
+			},
+			output:   nil,
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			tc.mocks(sessionRepo)
+
+			sessionService := service.NewSessionService(sessionRepo, mock.NewMockRefreshTokenRepository(ctrl), cache)
+
+			output, err := sessionService.ListSessions(ctx, userID)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			assert.Equal(t, tc.output, output, "Sessions mismatch")
+		})
+	}
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	ctx := context.Background()
+	userID := uint64(1)
+	session := &domain.Session{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	otherSession := &domain.Session{
+		ID:        session.ID,
+		UserID:    2,
+		ExpiresAt: session.ExpiresAt,
+	}
+	cacheKey := util.GenerateCacheKey("revoked_session", session.ID)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(sessionRepo *mock.MockSessionRepository, refreshRepo *mock.MockRefreshTokenRepository, cache *mock.MockCacheRepository)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(sessionRepo *mock.MockSessionRepository, refreshRepo *mock.MockRefreshTokenRepository, cache *mock.MockCacheRepository) {
+				sessionRepo.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Return(session, nil)
+				refreshRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(session.ID)).
+					Return(nil)
+				sessionRepo.EXPECT().
+					RevokeSession(gomock.Any(), gomock.Eq(session.ID)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Eq([]byte("1")), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ []byte, ttl time.Duration) error {
+						assert.InDelta(t, time.Hour, ttl, float64(time.Minute), "TTL mismatch")
+						return nil
+					})
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(sessionRepo *mock.MockSessionRepository, refreshRepo *mock.MockRefreshTokenRepository, cache *mock.MockCacheRepository) {
+				sessionRepo.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: domain.ErrDataNotFound,
+		},
+		{
+			desc: "Fail_OtherUser",
+			mocks: func(sessionRepo *mock.MockSessionRepository, refreshRepo *mock.MockRefreshTokenRepository, cache *mock.MockCacheRepository) {
+				sessionRepo.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Return(otherSession, nil)
+			},
+			expected: domain.ErrDataNotFound,
+		},
+		{
+			desc: "Fail_RevokeSessionError",
+			mocks: func(sessionRepo *mock.MockSessionRepository, refreshRepo *mock.MockRefreshTokenRepository, cache *mock.MockCacheRepository) {
+				sessionRepo.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Return(session, nil)
+				refreshRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(session.ID)).
+					Return(nil)
+				sessionRepo.EXPECT().
+					RevokeSession(gomock.Any(), gomock.Eq(session.ID)).
+					Return(domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(sessionRepo *mock.MockSessionRepository, refreshRepo *mock.MockRefreshTokenRepository, cache *mock.MockCacheRepository) {
+				sessionRepo.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Return(session, nil)
+				refreshRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(session.ID)).
+					Return(domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sessionRepo := mock.NewMockSessionRepository(ctrl)
+			refreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			tc.mocks(sessionRepo, refreshRepo, cache)
+
+			sessionService := service.NewSessionService(sessionRepo, refreshRepo, cache)
+
+			err := sessionService.RevokeSession(ctx, userID, session.ID)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
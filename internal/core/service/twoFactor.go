@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes given to a user at once
+	recoveryCodeCount = 10
+	// recoveryCodeSize is the number of random bytes of a recovery code
+	recoveryCodeSize = 5
+	// totpCodeLength is the number of digits of a TOTP code
+	totpCodeLength = 6
+	// totpReplayWindow is how long a used TOTP code is remembered, it covers the accepted clock skew
+	totpReplayWindow = 90 * time.Second
+)
+
+/**
+ * TwoFactorService implements port.TwoFactorService interface
+ * and provides an access to the two-factor repository, user repository,
+ * cache repository, TOTP generator and secret cipher
+ */
+type TwoFactorService struct {
+	repo      port.TwoFactorRepository
+	userRepo  port.UserRepository
+	cache     port.CacheRepository
+	generator port.TOTPGenerator
+	cipher    port.SecretCipher
+}
+
+// NewTwoFactorService creates a new two-factor service instance
+func NewTwoFactorService(
+	repo port.TwoFactorRepository,
+	userRepo port.UserRepository,
+	cache port.CacheRepository,
+	generator port.TOTPGenerator,
+	cipher port.SecretCipher,
+) *TwoFactorService {
+	return &TwoFactorService{
+		repo,
+		userRepo,
+		cache,
+		generator,
+		cipher,
+	}
+}
+
+// Enroll generates a new TOTP secret for a user, replacing any unconfirmed one.
+// Two-factor authentication is only enabled once the secret is confirmed with a valid code
+func (ts *TwoFactorService) Enroll(ctx context.Context, userID uint64) (*domain.TOTPKey, error) {
+	totp, err := ts.repo.GetTOTPByUserID(ctx, userID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+
+	if totp != nil && totp.ConfirmedAt != nil {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	user, err := ts.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	key, err := ts.generator.Generate(user.Email)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	secret, err := ts.cipher.Encrypt(key.Secret)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	_, err = ts.repo.SaveTOTP(ctx, &domain.TOTP{
+		UserID: userID,
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return key, nil
+}
+
+// Confirm enables two-factor authentication if the code matches the enrolled secret
+// and returns the user's recovery codes, which are only shown once
+func (ts *TwoFactorService) Confirm(ctx context.Context, userID uint64, code string) ([]string, error) {
+	totp, err := ts.repo.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrTwoFactorNotEnabled
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if totp.ConfirmedAt != nil {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	err = ts.validateTOTP(ctx, totp, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := ts.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.repo.ConfirmTOTP(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return codes, nil
+}
+
+// Disable turns off two-factor authentication after verifying a code or a recovery code
+func (ts *TwoFactorService) Disable(ctx context.Context, userID uint64, code string) error {
+	err := ts.Verify(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	err = ts.repo.DeleteTOTP(ctx, userID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of a user after verifying a code or a recovery code
+func (ts *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error) {
+	err := ts.Verify(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.replaceRecoveryCodes(ctx, userID)
+}
+
+// IsEnabled reports whether a user has confirmed two-factor authentication
+func (ts *TwoFactorService) IsEnabled(ctx context.Context, userID uint64) (bool, error) {
+	totp, err := ts.repo.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return false, nil
+		}
+		return false, domain.ErrInternal
+	}
+
+	return totp.ConfirmedAt != nil, nil
+}
+
+// Verify checks a TOTP code or, failing that format, consumes a recovery code of a user
+func (ts *TwoFactorService) Verify(ctx context.Context, userID uint64, code string) error {
+	totp, err := ts.repo.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrTwoFactorNotEnabled
+		}
+		return domain.ErrInternal
+	}
+
+	if totp.ConfirmedAt == nil {
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return ts.validateTOTP(ctx, totp, code)
+	}
+
+	err = ts.repo.UseRecoveryCode(ctx, userID, util.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// validateTOTP checks a TOTP code against the secret, rejecting codes that were already used
+func (ts *TwoFactorService) validateTOTP(ctx context.Context, totp *domain.TOTP, code string) error {
+	secret, err := ts.cipher.Decrypt(totp.Secret)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if !ts.generator.Validate(code, secret) {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	cacheKey := util.GenerateCacheKey("totp_used", fmt.Sprintf("%d:%s", totp.UserID, code))
+	uses, err := ts.cache.Increment(ctx, cacheKey, totpReplayWindow)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if uses > 1 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes generates new recovery codes for a user, invalidating the previous ones
+func (ts *TwoFactorService) replaceRecoveryCodes(ctx context.Context, userID uint64) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		codes[i] = code
+		hashes[i] = util.HashToken(normalizeRecoveryCode(code))
+	}
+
+	err := ts.repo.ReplaceRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCode generates a random recovery code formatted as two groups of letters and digits
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, recoveryCodeSize)
+
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(bytes))
+	half := len(code) / 2
+
+	return code[:half] + "-" + code[half:], nil
+}
+
+// normalizeRecoveryCode ignores the case and separators of a recovery code
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// isTOTPCode reports whether the code has the format of a TOTP code
+func isTOTPCode(code string) bool {
+	if len(code) != totpCodeLength {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
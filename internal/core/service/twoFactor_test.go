@@ -0,0 +1,300 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type twoFactorMocks struct {
+	repo      *mock.MockTwoFactorRepository
+	userRepo  *mock.MockUserRepository
+	cache     *mock.MockCacheRepository
+	generator *mock.MockTOTPGenerator
+	cipher    *mock.MockSecretCipher
+}
+
+// newTwoFactorService creates a two-factor service backed by fresh mocks
+func newTwoFactorService(ctrl *gomock.Controller) (*service.TwoFactorService, *twoFactorMocks) {
+	mocks := &twoFactorMocks{
+		repo:      mock.NewMockTwoFactorRepository(ctrl),
+		userRepo:  mock.NewMockUserRepository(ctrl),
+		cache:     mock.NewMockCacheRepository(ctrl),
+		generator: mock.NewMockTOTPGenerator(ctrl),
+		cipher:    mock.NewMockSecretCipher(ctrl),
+	}
+
+	twoFactorService := service.NewTwoFactorService(mocks.repo, mocks.userRepo, mocks.cache, mocks.generator, mocks.cipher)
+
+	return twoFactorService, mocks
+}
+
+func TestTwoFactorService_Enroll(t *testing.T) {
+	ctx := context.Background()
+	userID := uint64(1)
+	user := &domain.User{ID: userID, Email: "test@example.com"}
+	key := &domain.TOTPKey{Secret: "JBSWY3DPEHPK3PXP", URI: "otpauth://totp/go-ws:test@example.com"}
+	confirmedAt := time.Now()
+
+	testCases := []struct {
+		desc     string
+		mocks    func(m *twoFactorMocks)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+				m.userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(user, nil)
+				m.generator.EXPECT().
+					Generate(gomock.Eq(user.Email)).
+					Return(key, nil)
+				m.cipher.EXPECT().
+					Encrypt(gomock.Eq(key.Secret)).
+					Return("encrypted", nil)
+				m.repo.EXPECT().
+					SaveTOTP(gomock.Any(), gomock.Eq(&domain.TOTP{UserID: userID, Secret: "encrypted"})).
+					Return(&domain.TOTP{}, nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_AlreadyEnabled",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.TOTP{UserID: userID, ConfirmedAt: &confirmedAt}, nil)
+			},
+			expected: domain.ErrTwoFactorEnabled,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrInternal)
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			twoFactorService, mocks := newTwoFactorService(ctrl)
+			tc.mocks(mocks)
+
+			result, err := twoFactorService.Enroll(ctx, userID)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			if tc.expected == nil {
+				assert.Equal(t, key, result, "Key mismatch")
+			}
+		})
+	}
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	ctx := context.Background()
+	userID := uint64(1)
+	code := "123456"
+	totp := &domain.TOTP{UserID: userID, Secret: "encrypted"}
+	usedKey := util.GenerateCacheKey("totp_used", "1:"+code)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(m *twoFactorMocks)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(totp, nil)
+				m.cipher.EXPECT().
+					Decrypt(gomock.Eq(totp.Secret)).
+					Return("JBSWY3DPEHPK3PXP", nil)
+				m.generator.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq("JBSWY3DPEHPK3PXP")).
+					Return(true)
+				m.cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedKey), gomock.Any()).
+					Return(int64(1), nil)
+				m.repo.EXPECT().
+					ReplaceRecoveryCodes(gomock.Any(), gomock.Eq(userID), gomock.Len(10)).
+					Return(nil)
+				m.repo.EXPECT().
+					ConfirmTOTP(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_InvalidCode",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(totp, nil)
+				m.cipher.EXPECT().
+					Decrypt(gomock.Eq(totp.Secret)).
+					Return("JBSWY3DPEHPK3PXP", nil)
+				m.generator.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq("JBSWY3DPEHPK3PXP")).
+					Return(false)
+			},
+			expected: domain.ErrInvalidTwoFactorCode,
+		},
+		{
+			desc: "Fail_NotEnrolled",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: domain.ErrTwoFactorNotEnabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			twoFactorService, mocks := newTwoFactorService(ctrl)
+			tc.mocks(mocks)
+
+			codes, err := twoFactorService.Confirm(ctx, userID, code)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+			if tc.expected == nil {
+				assert.Len(t, codes, 10, "Recovery codes mismatch")
+			}
+		})
+	}
+}
+
+type verifyTestedInput struct {
+	code string
+}
+
+func TestTwoFactorService_Verify(t *testing.T) {
+	ctx := context.Background()
+	userID := uint64(1)
+	confirmedAt := time.Now()
+	totp := &domain.TOTP{UserID: userID, Secret: "encrypted", ConfirmedAt: &confirmedAt}
+	usedKey := util.GenerateCacheKey("totp_used", "1:123456")
+	recoveryCode := "ABCDE-FGHIJ"
+
+	testCases := []struct {
+		desc     string
+		mocks    func(m *twoFactorMocks)
+		input    verifyTestedInput
+		expected error
+	}{
+		{
+			desc: "Success_TOTP",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(totp, nil)
+				m.cipher.EXPECT().
+					Decrypt(gomock.Eq(totp.Secret)).
+					Return("JBSWY3DPEHPK3PXP", nil)
+				m.generator.EXPECT().
+					Validate(gomock.Eq("123456"), gomock.Eq("JBSWY3DPEHPK3PXP")).
+					Return(true)
+				m.cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedKey), gomock.Any()).
+					Return(int64(1), nil)
+			},
+			input:    verifyTestedInput{code: " 123456 "},
+			expected: nil,
+		},
+		{
+			desc: "Fail_ReplayedTOTP",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(totp, nil)
+				m.cipher.EXPECT().
+					Decrypt(gomock.Eq(totp.Secret)).
+					Return("JBSWY3DPEHPK3PXP", nil)
+				m.generator.EXPECT().
+					Validate(gomock.Eq("123456"), gomock.Eq("JBSWY3DPEHPK3PXP")).
+					Return(true)
+				m.cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedKey), gomock.Any()).
+					Return(int64(2), nil)
+			},
+			input:    verifyTestedInput{code: "123456"},
+			expected: domain.ErrInvalidTwoFactorCode,
+		},
+		{
+			desc: "Success_RecoveryCode",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(totp, nil)
+				m.repo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(userID), gomock.Eq(util.HashToken("abcdefghij"))).
+					Return(nil)
+			},
+			input:    verifyTestedInput{code: recoveryCode},
+			expected: nil,
+		},
+		{
+			desc: "Fail_UsedRecoveryCode",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(totp, nil)
+				m.repo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(userID), gomock.Any()).
+					Return(domain.ErrDataNotFound)
+			},
+			input:    verifyTestedInput{code: recoveryCode},
+			expected: domain.ErrInvalidTwoFactorCode,
+		},
+		{
+			desc: "Fail_NotConfirmed",
+			mocks: func(m *twoFactorMocks) {
+				m.repo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.TOTP{UserID: userID}, nil)
+			},
+			input:    verifyTestedInput{code: "123456"},
+			expected: domain.ErrTwoFactorNotEnabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			twoFactorService, mocks := newTwoFactorService(ctrl)
+			tc.mocks(mocks)
+
+			err := twoFactorService.Verify(ctx, userID, tc.input.code)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
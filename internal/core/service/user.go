@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"log/slog"
+	"strconv"
 
 	"github.com/cidmiranda/go-ws/internal/core/domain"
 	"github.com/cidmiranda/go-ws/internal/core/port"
@@ -9,21 +11,47 @@ import (
 )
 
 type UserService struct {
-	repo  port.UserRepository
-	cache port.CacheRepository
+	repo     port.UserRepository
+	cache    port.CacheRepository
+	authz    port.Authorizer
+	verifier port.VerificationService
+	policy   port.PasswordPolicyService
+	hasher   port.PasswordHasher
+	audit    port.AuditService
 }
 
 // NewUserService creates a new user service instance
-func NewUserService(repo port.UserRepository, cache port.CacheRepository) *UserService {
+func NewUserService(
+	repo port.UserRepository,
+	cache port.CacheRepository,
+	authz port.Authorizer,
+	verifier port.VerificationService,
+	policy port.PasswordPolicyService,
+	hasher port.PasswordHasher,
+	audit port.AuditService,
+) *UserService {
 	return &UserService{
 		repo,
 		cache,
+		authz,
+		verifier,
+		policy,
+		hasher,
+		audit,
 	}
 }
 
-// Register creates a new user
+// Register creates a new user with the default cashier role and sends a link to verify the email,
+// the password must meet the password policy
 func (us *UserService) Register(ctx context.Context, user *domain.User) (*domain.User, error) {
-	hashedPassword, err := util.HashPassword(user.Password)
+	user.Role = domain.Cashier
+
+	err := us.policy.Validate(ctx, user.Password, user)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := us.hasher.Hash(user.Password)
 	if err != nil {
 		return nil, domain.ErrInternal
 	}
@@ -54,6 +82,8 @@ func (us *UserService) Register(ctx context.Context, user *domain.User) (*domain
 		return nil, domain.ErrInternal
 	}
 
+	us.sendVerification(ctx, user)
+
 	return user, nil
 }
 
@@ -126,8 +156,29 @@ func (us *UserService) ListUsers(ctx context.Context, skip, limit uint64) ([]dom
 	return users, nil
 }
 
-// UpdateUser updates a user's name, email, and password
-func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+// UpdateUser updates a user's name, email, password, and role.
+// The actor must be allowed to update the user, and to change its role if a role is given.
+// The email and password cannot be changed while impersonating the user.
+// A new password must meet the password policy for the updated name and email.
+// Every attempt is recorded in the audit log as a password change if a password is given
+func (us *UserService) UpdateUser(ctx context.Context, actor *domain.TokenPayload, user *domain.User) (*domain.User, error) {
+	event := &domain.AuthEvent{
+		Type:     domain.UserUpdateEvent,
+		ActorID:  actor.UserID,
+		TargetID: user.ID,
+	}
+	if user.Password != "" {
+		event.Type = domain.PasswordChangeEvent
+	}
+
+	updatedUser, err := us.updateUser(ctx, actor, user)
+	recordAuthEvent(ctx, us.audit, event, err)
+
+	return updatedUser, err
+}
+
+// updateUser checks and applies the update of a user
+func (us *UserService) updateUser(ctx context.Context, actor *domain.TokenPayload, user *domain.User) (*domain.User, error) {
 	existingUser, err := us.repo.GetUserByID(ctx, user.ID)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
@@ -136,11 +187,30 @@ func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*doma
 		return nil, domain.ErrInternal
 	}
 
+	err = us.authorize(ctx, actor, "users:update", existingUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role != "" {
+		err = us.authorize(ctx, actor, "users:change_role", existingUser)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changesCredentials := user.Password != "" || (user.Email != "" && user.Email != existingUser.Email)
+	if actor.ImpersonatorID != 0 && changesCredentials {
+		return nil, domain.ErrImpersonationForbidden
+	}
+
 	emptyData := user.Name == "" &&
 		user.Email == "" &&
-		user.Password == ""
+		user.Password == "" &&
+		user.Role == ""
 	sameData := existingUser.Name == user.Name &&
-		existingUser.Email == user.Email
+		existingUser.Email == user.Email &&
+		(user.Role == "" || existingUser.Role == user.Role)
 	if emptyData || sameData {
 		return nil, domain.ErrNoUpdatedData
 	}
@@ -148,7 +218,23 @@ func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*doma
 	var hashedPassword string
 
 	if user.Password != "" {
-		hashedPassword, err = util.HashPassword(user.Password)
+		owner := &domain.User{
+			Name:  existingUser.Name,
+			Email: existingUser.Email,
+		}
+		if user.Name != "" {
+			owner.Name = user.Name
+		}
+		if user.Email != "" {
+			owner.Email = user.Email
+		}
+
+		err = us.policy.Validate(ctx, user.Password, owner)
+		if err != nil {
+			return nil, err
+		}
+
+		hashedPassword, err = us.hasher.Hash(user.Password)
 		if err != nil {
 			return nil, domain.ErrInternal
 		}
@@ -186,12 +272,29 @@ func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*doma
 		return nil, domain.ErrInternal
 	}
 
+	if user.Email != "" && user.Email != existingUser.Email {
+		us.sendVerification(ctx, user)
+	}
+
 	return user, nil
 }
 
-// DeleteUser deletes a user by ID
-func (us *UserService) DeleteUser(ctx context.Context, id uint64) error {
-	_, err := us.repo.GetUserByID(ctx, id)
+// DeleteUser deletes a user by ID, the actor must be allowed to delete the user.
+// Every attempt is recorded in the audit log
+func (us *UserService) DeleteUser(ctx context.Context, actor *domain.TokenPayload, id uint64) error {
+	err := us.deleteUser(ctx, actor, id)
+	recordAuthEvent(ctx, us.audit, &domain.AuthEvent{
+		Type:     domain.UserDeleteEvent,
+		ActorID:  actor.UserID,
+		TargetID: id,
+	}, err)
+
+	return err
+}
+
+// deleteUser checks and applies the deletion of a user
+func (us *UserService) deleteUser(ctx context.Context, actor *domain.TokenPayload, id uint64) error {
+	existingUser, err := us.repo.GetUserByID(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			return err
@@ -199,6 +302,11 @@ func (us *UserService) DeleteUser(ctx context.Context, id uint64) error {
 		return domain.ErrInternal
 	}
 
+	err = us.authorize(ctx, actor, "users:delete", existingUser)
+	if err != nil {
+		return err
+	}
+
 	cacheKey := util.GenerateCacheKey("user", id)
 
 	err = us.cache.Delete(ctx, cacheKey)
@@ -213,3 +321,34 @@ func (us *UserService) DeleteUser(ctx context.Context, id uint64) error {
 
 	return us.repo.DeleteUser(ctx, id)
 }
+
+// authorize checks whether the actor may perform the action on the given user
+func (us *UserService) authorize(ctx context.Context, actor *domain.TokenPayload, action string, user *domain.User) error {
+	resource := &domain.PolicyResource{
+		Type: "users",
+		ID:   strconv.FormatUint(user.ID, 10),
+		Attributes: map[string]string{
+			"role": string(user.Role),
+		},
+	}
+
+	decision, err := us.authz.Can(ctx, actor, action, resource)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if !decision.Allowed {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+// sendVerification sends a link to verify the email of the user, a failure is only logged
+// since the user can ask for a new link
+func (us *UserService) sendVerification(ctx context.Context, user *domain.User) {
+	err := us.verifier.SendVerification(ctx, user)
+	if err != nil {
+		slog.Error("Error sending the email verification", "user_id", user.ID, "error", err)
+	}
+}
@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -28,7 +29,7 @@ func TestUserService_Register(t *testing.T) {
 	userName := gofakeit.Name()
 	userEmail := gofakeit.Email()
 	userPassword := gofakeit.Password(true, true, true, true, false, 8)
-	hashedPassword, _ := util.HashPassword(userPassword)
+	hashedPassword := "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5"
 
 	userInput := &domain.User{
 		Name:     userName,
@@ -40,10 +41,15 @@ func TestUserService_Register(t *testing.T) {
 		Name:      userName,
 		Email:     userEmail,
 		Password:  hashedPassword,
+		Role:      domain.Cashier,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
+	weakPasswordErr := &domain.PasswordPolicyError{
+		Violations: []string{"password must contain a symbol"},
+	}
+
 	cacheKey := util.GenerateCacheKey("user", userOutput.ID)
 	userSerialized, _ := util.Serialize(userOutput)
 	ttl := time.Duration(0)
@@ -53,6 +59,9 @@ func TestUserService_Register(t *testing.T) {
 		mocks func(
 			userRepo *mock.MockUserRepository,
 			cache *mock.MockCacheRepository,
+			verifier *mock.MockVerificationService,
+			policy *mock.MockPasswordPolicyService,
+			hasher *mock.MockPasswordHasher,
 		)
 		input    registerTestedInput
 		expected registerExpectedOutput
@@ -62,7 +71,16 @@ func TestUserService_Register(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Any(), gomock.Eq(userInput)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -72,6 +90,9 @@ func TestUserService_Register(t *testing.T) {
 				cache.EXPECT().
 					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
 					Return(nil)
+				verifier.EXPECT().
+					SendVerification(gomock.Any(), gomock.Eq(userOutput)).
+					Return(nil)
 			},
 			input: registerTestedInput{
 				user: userInput,
@@ -81,12 +102,78 @@ func TestUserService_Register(t *testing.T) {
 				err:  nil,
 			},
 		},
+		{
+			desc: "Success_VerificationNotSent",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+				hasher *mock.MockPasswordHasher,
+			) {
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Any(), gomock.Eq(userInput)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
+				userRepo.EXPECT().
+					CreateUser(gomock.Any(), gomock.Eq(userInput)).
+					Return(userOutput, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Eq(userSerialized), gomock.Eq(ttl)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				verifier.EXPECT().
+					SendVerification(gomock.Any(), gomock.Eq(userOutput)).
+					Return(domain.ErrInternal)
+			},
+			input: registerTestedInput{
+				user: userInput,
+			},
+			expected: registerExpectedOutput{
+				user: userOutput,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Fail_WeakPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+				hasher *mock.MockPasswordHasher,
+			) {
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Any(), gomock.Eq(userInput)).
+					Return(weakPasswordErr)
+			},
+			input: registerTestedInput{
+				user: userInput,
+			},
+			expected: registerExpectedOutput{
+				user: nil,
+				err:  weakPasswordErr,
+			},
+		},
 		{
 			desc: "Fail_InternalError",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Any(), gomock.Eq(userInput)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(nil, domain.ErrInternal)
@@ -104,7 +191,16 @@ func TestUserService_Register(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Any(), gomock.Eq(userInput)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(nil, domain.ErrConflictingData)
@@ -122,7 +218,16 @@ func TestUserService_Register(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Any(), gomock.Eq(userInput)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -143,7 +248,16 @@ func TestUserService_Register(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+				hasher *mock.MockPasswordHasher,
 			) {
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Any(), gomock.Eq(userInput)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -174,10 +288,13 @@ func TestUserService_Register(t *testing.T) {
 
 			userRepo := mock.NewMockUserRepository(ctrl)
 			cache := mock.NewMockCacheRepository(ctrl)
+			verifier := mock.NewMockVerificationService(ctrl)
+			policy := mock.NewMockPasswordPolicyService(ctrl)
+			hasher := mock.NewMockPasswordHasher(ctrl)
 
-			tc.mocks(userRepo, cache)
+			tc.mocks(userRepo, cache, verifier, policy, hasher)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, cache, mock.NewMockAuthorizer(ctrl), verifier, policy, hasher, mock.NewMockAuditService(ctrl))
 
 			user, err := userService.Register(ctx, tc.input.user)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
@@ -358,7 +475,7 @@ func TestUserService_GetUser(t *testing.T) {
 
 			tc.mocks(userRepo, cache)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, cache, mock.NewMockAuthorizer(ctrl), mock.NewMockVerificationService(ctrl), mock.NewMockPasswordPolicyService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl))
 
 			user, err := userService.GetUser(ctx, tc.input.id)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
@@ -381,8 +498,7 @@ func TestUserService_ListUsers(t *testing.T) {
 	var users []domain.User
 
 	for i := 0; i < 10; i++ {
-		userPassword := gofakeit.Password(true, true, true, true, false, 8)
-		hashedPassword, _ := util.HashPassword(userPassword)
+		hashedPassword := gofakeit.Password(true, true, true, true, false, 60)
 
 		users = append(users, domain.User{
 			ID:       gofakeit.Uint64(),
@@ -534,7 +650,7 @@ func TestUserService_ListUsers(t *testing.T) {
 
 			tc.mocks(userRepo, cache)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, cache, mock.NewMockAuthorizer(ctrl), mock.NewMockVerificationService(ctrl), mock.NewMockPasswordPolicyService(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockAuditService(ctrl))
 
 			users, err := userService.ListUsers(ctx, tc.input.skip, tc.input.limit)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
@@ -544,7 +660,8 @@ func TestUserService_ListUsers(t *testing.T) {
 }
 
 type updateUserTestedInput struct {
-	user *domain.User
+	actor *domain.TokenPayload
+	user  *domain.User
 }
 
 type updateUserExpectedOutput struct {
@@ -574,8 +691,58 @@ func TestUserService_UpdateUser(t *testing.T) {
 		Email: gofakeit.Email(),
 	}
 
+	nameInput := &domain.User{
+		ID:   userID,
+		Name: gofakeit.Name(),
+	}
+	nameOutput := &domain.User{
+		ID:   userID,
+		Name: nameInput.Name,
+	}
+
+	passwordInput := &domain.User{
+		ID:       userID,
+		Name:     userInput.Name,
+		Password: "weak",
+	}
+	passwordOwner := &domain.User{
+		Name:  userInput.Name,
+		Email: existingUser.Email,
+	}
+	weakPasswordErr := &domain.PasswordPolicyError{
+		Violations: []string{"password must be at least 10 characters long"},
+	}
+
+	actor := &domain.TokenPayload{
+		UserID: userID,
+		Role:   domain.Cashier,
+	}
+	otherActor := &domain.TokenPayload{
+		UserID: gofakeit.Uint64(),
+		Role:   domain.Cashier,
+	}
+	impersonatingActor := &domain.TokenPayload{
+		UserID:         userID,
+		Role:           domain.Cashier,
+		ImpersonatorID: gofakeit.Uint64(),
+	}
+	resource := &domain.PolicyResource{
+		Type: "users",
+		ID:   strconv.FormatUint(userID, 10),
+		Attributes: map[string]string{
+			"role": "",
+		},
+	}
+	allowed := &domain.PolicyDecision{
+		Allowed: true,
+	}
+	denied := &domain.PolicyDecision{
+		Allowed: false,
+	}
+
 	cacheKey := util.GenerateCacheKey("user", userID)
 	userSerialized, _ := util.Serialize(userOutput)
+	nameSerialized, _ := util.Serialize(nameOutput)
 	ttl := time.Duration(0)
 
 	testCases := []struct {
@@ -583,6 +750,9 @@ func TestUserService_UpdateUser(t *testing.T) {
 		mocks func(
 			userRepo *mock.MockUserRepository,
 			cache *mock.MockCacheRepository,
+			authz *mock.MockAuthorizer,
+			verifier *mock.MockVerificationService,
+			policy *mock.MockPasswordPolicyService,
 		)
 		input    updateUserTestedInput
 		expected updateUserExpectedOutput
@@ -592,10 +762,16 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -608,27 +784,206 @@ func TestUserService_UpdateUser(t *testing.T) {
 				cache.EXPECT().
 					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
 					Return(nil)
+				verifier.EXPECT().
+					SendVerification(gomock.Any(), gomock.Eq(userInput)).
+					Return(nil)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: userOutput,
 				err:  nil,
 			},
 		},
+		{
+			desc: "Success_NameOnly",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(nameInput)).
+					Return(nameOutput, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Eq(nameSerialized), gomock.Eq(ttl)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+			},
+			input: updateUserTestedInput{
+				actor: actor,
+				user:  nameInput,
+			},
+			expected: updateUserExpectedOutput{
+				user: nameOutput,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Fail_WeakPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
+				policy.EXPECT().
+					Validate(gomock.Any(), gomock.Eq("weak"), gomock.Eq(passwordOwner)).
+					Return(weakPasswordErr)
+			},
+			input: updateUserTestedInput{
+				actor: actor,
+				user:  passwordInput,
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  weakPasswordErr,
+			},
+		},
+		{
+			desc: "Fail_Forbidden",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(otherActor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(denied, nil)
+			},
+			input: updateUserTestedInput{
+				actor: otherActor,
+				user:  userInput,
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrForbidden,
+			},
+		},
+		{
+			desc: "Fail_Impersonating",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(impersonatingActor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
+			},
+			input: updateUserTestedInput{
+				actor: impersonatingActor,
+				user:  userInput,
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrImpersonationForbidden,
+			},
+		},
+		{
+			desc: "Fail_ForbiddenRoleChange",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:change_role"), gomock.Eq(resource)).
+					Return(denied, nil)
+			},
+			input: updateUserTestedInput{
+				actor: actor,
+				user: &domain.User{
+					ID:   userID,
+					Role: domain.Admin,
+				},
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrForbidden,
+			},
+		},
+		{
+			desc: "Fail_InternalErrorAuthorize",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(nil, domain.ErrPolicyNotLoaded)
+			},
+			input: updateUserTestedInput{
+				actor: actor,
+				user:  userInput,
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrInternal,
+			},
+		},
 		{
 			desc: "Fail_NotFound",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(nil, domain.ErrDataNotFound)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -640,13 +995,17 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(nil, domain.ErrInternal)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -658,12 +1017,19 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 			},
 			input: updateUserTestedInput{
+				actor: actor,
 				user: &domain.User{
 					ID: userID,
 				},
@@ -678,13 +1044,20 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 			},
 			input: updateUserTestedInput{
-				user: existingUser,
+				actor: actor,
+				user:  existingUser,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -696,16 +1069,23 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(nil, domain.ErrConflictingData)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -717,16 +1097,23 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(nil, domain.ErrInternal)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -738,10 +1125,16 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -750,7 +1143,8 @@ func TestUserService_UpdateUser(t *testing.T) {
 					Return(domain.ErrInternal)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -762,10 +1156,16 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -777,7 +1177,8 @@ func TestUserService_UpdateUser(t *testing.T) {
 					Return(domain.ErrInternal)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -789,10 +1190,16 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+				verifier *mock.MockVerificationService,
+				policy *mock.MockPasswordPolicyService,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:update"), gomock.Eq(resource)).
+					Return(allowed, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -807,7 +1214,8 @@ func TestUserService_UpdateUser(t *testing.T) {
 					Return(domain.ErrInternal)
 			},
 			input: updateUserTestedInput{
-				user: userInput,
+				actor: actor,
+				user:  userInput,
 			},
 			expected: updateUserExpectedOutput{
 				user: nil,
@@ -826,12 +1234,22 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 			userRepo := mock.NewMockUserRepository(ctrl)
 			cache := mock.NewMockCacheRepository(ctrl)
+			authz := mock.NewMockAuthorizer(ctrl)
+			verifier := mock.NewMockVerificationService(ctrl)
+			policy := mock.NewMockPasswordPolicyService(ctrl)
 
-			tc.mocks(userRepo, cache)
+			tc.mocks(userRepo, cache, authz, verifier, policy)
+
+			eventType := domain.UserUpdateEvent
+			if tc.input.user.Password != "" {
+				eventType = domain.PasswordChangeEvent
+			}
+			audit := mock.NewMockAuditService(ctrl)
+			expectAuthEvent(t, audit, eventType, tc.expected.err)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, cache, authz, verifier, policy, mock.NewMockPasswordHasher(ctrl), audit)
 
-			user, err := userService.UpdateUser(ctx, tc.input.user)
+			user, err := userService.UpdateUser(ctx, tc.input.actor, tc.input.user)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
 			assert.Equal(t, tc.expected.user, user, "User mismatch")
 		})
@@ -839,7 +1257,8 @@ func TestUserService_UpdateUser(t *testing.T) {
 }
 
 type deleteUserTestedInput struct {
-	id uint64
+	actor *domain.TokenPayload
+	id    uint64
 }
 
 type deleteUserExpectedOutput struct {
@@ -850,6 +1269,32 @@ func TestUserService_DeleteUser(t *testing.T) {
 	ctx := context.Background()
 	userID := gofakeit.Uint64()
 
+	actor := &domain.TokenPayload{
+		UserID: userID,
+		Role:   domain.Cashier,
+	}
+	otherActor := &domain.TokenPayload{
+		UserID: gofakeit.Uint64(),
+		Role:   domain.Manager,
+	}
+	existingUser := &domain.User{
+		ID:   userID,
+		Role: domain.Admin,
+	}
+	resource := &domain.PolicyResource{
+		Type: "users",
+		ID:   strconv.FormatUint(userID, 10),
+		Attributes: map[string]string{
+			"role": string(domain.Admin),
+		},
+	}
+	allowed := &domain.PolicyDecision{
+		Allowed: true,
+	}
+	denied := &domain.PolicyDecision{
+		Allowed: false,
+	}
+
 	cacheKey := util.GenerateCacheKey("user", userID)
 
 	testCases := []struct {
@@ -857,6 +1302,7 @@ func TestUserService_DeleteUser(t *testing.T) {
 		mocks func(
 			userRepo *mock.MockUserRepository,
 			cache *mock.MockCacheRepository,
+			authz *mock.MockAuthorizer,
 		)
 		input    deleteUserTestedInput
 		expected deleteUserExpectedOutput
@@ -866,10 +1312,14 @@ func TestUserService_DeleteUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(&domain.User{}, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:delete"), gomock.Any()).
+					Return(allowed, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
@@ -881,24 +1331,49 @@ func TestUserService_DeleteUser(t *testing.T) {
 					Return(nil)
 			},
 			input: deleteUserTestedInput{
-				id: userID,
+				actor: actor,
+				id:    userID,
 			},
 			expected: deleteUserExpectedOutput{
 				err: nil,
 			},
 		},
+		{
+			desc: "Fail_Forbidden",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(otherActor), gomock.Eq("users:delete"), gomock.Eq(resource)).
+					Return(denied, nil)
+			},
+			input: deleteUserTestedInput{
+				actor: otherActor,
+				id:    userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrForbidden,
+			},
+		},
 		{
 			desc: "Fail_NotFound",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(nil, domain.ErrDataNotFound)
 			},
 			input: deleteUserTestedInput{
-				id: userID,
+				actor: actor,
+				id:    userID,
 			},
 			expected: deleteUserExpectedOutput{
 				err: domain.ErrDataNotFound,
@@ -909,13 +1384,15 @@ func TestUserService_DeleteUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(nil, domain.ErrInternal)
 			},
 			input: deleteUserTestedInput{
-				id: userID,
+				actor: actor,
+				id:    userID,
 			},
 			expected: deleteUserExpectedOutput{
 				err: domain.ErrInternal,
@@ -926,16 +1403,21 @@ func TestUserService_DeleteUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(&domain.User{}, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:delete"), gomock.Any()).
+					Return(allowed, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(domain.ErrInternal)
 			},
 			input: deleteUserTestedInput{
-				id: userID,
+				actor: actor,
+				id:    userID,
 			},
 			expected: deleteUserExpectedOutput{
 				err: domain.ErrInternal,
@@ -946,10 +1428,14 @@ func TestUserService_DeleteUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
 			) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(&domain.User{}, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:delete"), gomock.Any()).
+					Return(allowed, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
@@ -958,7 +1444,8 @@ func TestUserService_DeleteUser(t *testing.T) {
 					Return(domain.ErrInternal)
 			},
 			input: deleteUserTestedInput{
-				id: userID,
+				actor: actor,
+				id:    userID,
 			},
 			expected: deleteUserExpectedOutput{
 				err: domain.ErrInternal,
@@ -969,6 +1456,7 @@ func TestUserService_DeleteUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				authz *mock.MockAuthorizer,
 			) {
 				user := &domain.User{
 					ID: userID,
@@ -976,6 +1464,9 @@ func TestUserService_DeleteUser(t *testing.T) {
 				userRepo.EXPECT().
 					GetUserByID(gomock.Any(), gomock.Eq(userID)).
 					Return(user, nil)
+				authz.EXPECT().
+					Can(gomock.Any(), gomock.Eq(actor), gomock.Eq("users:delete"), gomock.Any()).
+					Return(allowed, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
@@ -987,7 +1478,8 @@ func TestUserService_DeleteUser(t *testing.T) {
 					Return(domain.ErrInternal)
 			},
 			input: deleteUserTestedInput{
-				id: userID,
+				actor: actor,
+				id:    userID,
 			},
 			expected: deleteUserExpectedOutput{
 				err: domain.ErrInternal,
@@ -1004,12 +1496,16 @@ func TestUserService_DeleteUser(t *testing.T) {
 
 			userRepo := mock.NewMockUserRepository(ctrl)
 			cache := mock.NewMockCacheRepository(ctrl)
+			authz := mock.NewMockAuthorizer(ctrl)
 
-			tc.mocks(userRepo, cache)
+			tc.mocks(userRepo, cache, authz)
+
+			audit := mock.NewMockAuditService(ctrl)
+			expectAuthEvent(t, audit, domain.UserDeleteEvent, tc.expected.err)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, cache, authz, mock.NewMockVerificationService(ctrl), mock.NewMockPasswordPolicyService(ctrl), mock.NewMockPasswordHasher(ctrl), audit)
 
-			err := userService.DeleteUser(ctx, tc.input.id)
+			err := userService.DeleteUser(ctx, tc.input.actor, tc.input.id)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
 		})
 	}
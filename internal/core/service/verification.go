@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+)
+
+/**
+ * VerificationService implements port.VerificationService interface
+ * and sends email verification links signed with HMAC-SHA256.
+ * The signed token carries the user ID, the email and the expiry,
+ * so it stops working once the user changes the email
+ */
+type VerificationService struct {
+	repo     port.UserRepository
+	cache    port.CacheRepository
+	mailer   port.Mailer
+	key      []byte
+	url      string
+	duration time.Duration
+}
+
+// NewVerificationService creates a new verification service instance,
+// links point to the given url with the signed token in the token query parameter
+func NewVerificationService(
+	repo port.UserRepository,
+	cache port.CacheRepository,
+	mailer port.Mailer,
+	key []byte,
+	url string,
+	duration time.Duration,
+) *VerificationService {
+	return &VerificationService{
+		repo,
+		cache,
+		mailer,
+		key,
+		url,
+		duration,
+	}
+}
+
+// SendVerification sends a verification link to the email of the user
+func (vs *VerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	expiresAt := time.Now().Add(vs.duration)
+	token := vs.sign(fmt.Sprintf("%d:%s:%d", user.ID, user.Email, expiresAt.Unix()))
+
+	link, err := url.Parse(vs.url)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	email := &domain.Email{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link is valid until %s.\n",
+			user.Name,
+			link.String(),
+			expiresAt.UTC().Format(time.RFC1123),
+		),
+	}
+
+	err = vs.mailer.Send(ctx, email)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// Verify marks the email of a user as verified if the token is valid and the user still has that email
+func (vs *VerificationService) Verify(ctx context.Context, token string) error {
+	userID, email, err := vs.parse(token)
+	if err != nil {
+		return err
+	}
+
+	user, err := vs.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidVerificationToken
+		}
+		return domain.ErrInternal
+	}
+
+	if user.Email != email {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	err = vs.repo.VerifyUserEmail(ctx, userID, email)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidVerificationToken
+		}
+		return domain.ErrInternal
+	}
+
+	err = vs.cache.Delete(ctx, util.GenerateCacheKey("user", userID))
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = vs.cache.DeleteByPrefix(ctx, "users:*")
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ResendVerification sends a new verification link if the email belongs to an unverified user,
+// it succeeds silently otherwise and sends the link in the background,
+// so that neither the response nor its timing reveals which emails are registered
+func (vs *VerificationService) ResendVerification(ctx context.Context, email string) error {
+	user, err := vs.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	go vs.deliverVerification(context.WithoutCancel(ctx), user)
+
+	return nil
+}
+
+// deliverVerification sends a verification link to the user outside of the request, a failure is only logged
+func (vs *VerificationService) deliverVerification(ctx context.Context, user *domain.User) {
+	err := vs.SendVerification(ctx, user)
+	if err != nil {
+		slog.Error("Error sending the verification link", "user_id", user.ID, "error", err)
+	}
+}
+
+// sign returns the payload and its signature, both base64 encoded and separated by a dot
+func (vs *VerificationService) sign(payload string) string {
+	mac := hmac.New(sha256.New, vs.key)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// parse checks the signature and the expiry of a token and returns the user ID and email it carries
+func (vs *VerificationService) parse(token string) (uint64, string, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, "", domain.ErrInvalidVerificationToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return 0, "", domain.ErrInvalidVerificationToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return 0, "", domain.ErrInvalidVerificationToken
+	}
+
+	mac := hmac.New(sha256.New, vs.key)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return 0, "", domain.ErrInvalidVerificationToken
+	}
+
+	// the email sits between the user ID and the expiry and may itself contain colons
+	fields := string(payload)
+	first := strings.Index(fields, ":")
+	last := strings.LastIndex(fields, ":")
+	if first < 0 || first == last {
+		return 0, "", domain.ErrInvalidVerificationToken
+	}
+
+	userID, err := strconv.ParseUint(fields[:first], 10, 64)
+	if err != nil {
+		return 0, "", domain.ErrInvalidVerificationToken
+	}
+
+	expiresAt, err := strconv.ParseInt(fields[last+1:], 10, 64)
+	if err != nil || time.Now().After(time.Unix(expiresAt, 0)) {
+		return 0, "", domain.ErrInvalidVerificationToken
+	}
+
+	return userID, fields[first+1 : last], nil
+}
@@ -0,0 +1,222 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+	"github.com/cidmiranda/go-ws/internal/core/port/mock"
+	"github.com/cidmiranda/go-ws/internal/core/service"
+	"github.com/cidmiranda/go-ws/internal/core/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	verificationKey = []byte("0123456789abcdef0123456789abcdef")
+	verificationURL = "http://localhost:8080/v1/users/verify"
+	linkPattern     = regexp.MustCompile(`http://\S+`)
+)
+
+// sentVerificationToken sends a verification link to the user and returns the token of the link
+func sentVerificationToken(t *testing.T, ctrl *gomock.Controller, user *domain.User, duration time.Duration) string {
+	t.Helper()
+
+	var sent *domain.Email
+	mailer := mock.NewMockMailer(ctrl)
+	mailer.EXPECT().
+		Send(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, email *domain.Email) error {
+			sent = email
+			return nil
+		})
+
+	verificationService := service.NewVerificationService(nil, nil, mailer, verificationKey, verificationURL, duration)
+
+	err := verificationService.SendVerification(context.Background(), user)
+	assert.NoError(t, err, "Send error")
+	assert.Equal(t, user.Email, sent.To, "Recipient mismatch")
+
+	link, err := url.Parse(linkPattern.FindString(sent.Body))
+	assert.NoError(t, err, "Link mismatch")
+
+	return link.Query().Get("token")
+}
+
+type verifyEmailExpectedOutput struct {
+	err error
+}
+
+func TestVerificationService_Verify(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{
+		ID:    42,
+		Name:  "John Doe",
+		Email: "john:doe@example.com",
+	}
+	changedUser := &domain.User{
+		ID:    user.ID,
+		Email: "other@example.com",
+	}
+	verifiedAt := time.Now()
+	verifiedUser := &domain.User{
+		ID:              user.ID,
+		Email:           user.Email,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	cacheKey := util.GenerateCacheKey("user", user.ID)
+
+	testCases := []struct {
+		desc     string
+		token    func(ctrl *gomock.Controller) string
+		mocks    func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository)
+		expected verifyEmailExpectedOutput
+	}{
+		{
+			desc: "Success",
+			token: func(ctrl *gomock.Controller) string {
+				return sentVerificationToken(t, ctrl, user, time.Hour)
+			},
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				userRepo.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(user.Email)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+			},
+			expected: verifyEmailExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_AlreadyVerified",
+			token: func(ctrl *gomock.Controller) string {
+				return sentVerificationToken(t, ctrl, user, time.Hour)
+			},
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(verifiedUser, nil)
+			},
+			expected: verifyEmailExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_EmailChanged",
+			token: func(ctrl *gomock.Controller) string {
+				return sentVerificationToken(t, ctrl, user, time.Hour)
+			},
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(changedUser, nil)
+			},
+			expected: verifyEmailExpectedOutput{
+				err: domain.ErrInvalidVerificationToken,
+			},
+		},
+		{
+			desc: "Fail_Expired",
+			token: func(ctrl *gomock.Controller) string {
+				return sentVerificationToken(t, ctrl, user, -time.Minute)
+			},
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {},
+			expected: verifyEmailExpectedOutput{
+				err: domain.ErrInvalidVerificationToken,
+			},
+		},
+		{
+			desc: "Fail_Tampered",
+			token: func(ctrl *gomock.Controller) string {
+				return sentVerificationToken(t, ctrl, user, time.Hour) + "x"
+			},
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {},
+			expected: verifyEmailExpectedOutput{
+				err: domain.ErrInvalidVerificationToken,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			token := tc.token(ctrl)
+			tc.mocks(userRepo, cache)
+
+			verificationService := service.NewVerificationService(userRepo, cache, mock.NewMockMailer(ctrl), verificationKey, verificationURL, time.Hour)
+
+			err := verificationService.Verify(ctx, token)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+		})
+	}
+}
+
+func TestVerificationService_ResendVerification_UnknownEmail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Eq("unknown@example.com")).
+		Return(nil, domain.ErrDataNotFound)
+
+	verificationService := service.NewVerificationService(userRepo, nil, mock.NewMockMailer(ctrl), verificationKey, verificationURL, time.Hour)
+
+	err := verificationService.ResendVerification(ctx, "unknown@example.com")
+	assert.NoError(t, err, "Error mismatch")
+}
+
+func TestVerificationService_ResendVerification_Unverified(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &domain.User{
+		ID:    42,
+		Name:  "John Doe",
+		Email: "test@example.com",
+	}
+	delivered := make(chan struct{})
+
+	userRepo := mock.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+		Return(user, nil)
+	mailer := mock.NewMockMailer(ctrl)
+	mailer.EXPECT().
+		Send(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, email *domain.Email) error {
+			defer close(delivered)
+			assert.NoError(t, ctx.Err(), "Delivery canceled with the request")
+			assert.Equal(t, user.Email, email.To, "Recipient mismatch")
+			return nil
+		})
+
+	verificationService := service.NewVerificationService(userRepo, nil, mailer, verificationKey, verificationURL, time.Hour)
+
+	err := verificationService.ResendVerification(ctx, user.Email)
+	assert.NoError(t, err, "Error mismatch")
+
+	// the link is sent in the background and outlives the request
+	cancel()
+	waitDelivered(t, delivered)
+}
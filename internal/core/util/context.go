@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+
+	"github.com/cidmiranda/go-ws/internal/core/domain"
+)
+
+// clientInfoKey is the context key of the client information
+type clientInfoKey struct{}
+
+// principalKey is the context key of the authenticated principal
+type principalKey struct{}
+
+// ContextWithClientInfo returns a copy of the context carrying the client information
+func ContextWithClientInfo(ctx context.Context, info *domain.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client information carried by the context,
+// it is empty when the context does not carry any
+func ClientInfoFromContext(ctx context.Context) *domain.ClientInfo {
+	info, ok := ctx.Value(clientInfoKey{}).(*domain.ClientInfo)
+	if !ok {
+		return &domain.ClientInfo{}
+	}
+
+	return info
+}
+
+// ContextWithPrincipal returns a copy of the context carrying the authenticated principal
+func ContextWithPrincipal(ctx context.Context, principal *domain.TokenPayload) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal carried by the context,
+// ok is false when the context does not carry any
+func PrincipalFromContext(ctx context.Context) (principal *domain.TokenPayload, ok bool) {
+	principal, ok = ctx.Value(principalKey{}).(*domain.TokenPayload)
+	return principal, ok
+}
@@ -0,0 +1,20 @@
+package util
+
+import "path"
+
+// ScopesAllow checks whether the scopes of a principal cover the action, scopes are glob patterns such as users:*.
+// A principal without scopes is not restricted
+func ScopesAllow(scopes []string, action string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range scopes {
+		matched, err := path.Match(scope, action)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}